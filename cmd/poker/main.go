@@ -1,66 +1,278 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	nethttp "net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"planningpoker/internal/domain/state"
 
 	"github.com/sirupsen/logrus"
 
+	"planningpoker/internal/domain/audit"
+	"planningpoker/internal/domain/batches"
 	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/reports"
+	"planningpoker/internal/domain/rooms"
+	"planningpoker/internal/domain/teams"
 	"planningpoker/internal/domain/users"
 	"planningpoker/internal/infra/async"
 	"planningpoker/internal/infra/auth"
+	"planningpoker/internal/infra/config"
 	"planningpoker/internal/infra/eventbus"
 	"planningpoker/internal/infra/http"
+	"planningpoker/internal/infra/janitor"
+	"planningpoker/internal/infra/logging"
+	"planningpoker/internal/infra/metrics"
+	"planningpoker/internal/infra/ratelimit"
 	"planningpoker/internal/infra/repository"
+	"planningpoker/internal/infra/scheduler"
+	"planningpoker/internal/infra/tracing"
 
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func main() {
-	logrus.Infof("starting the service")
+	cfg, printConfig, err := config.Load(os.Args[1:], os.Getenv)
+	if err != nil {
+		log.Fatalf("unable to load configuration: %v", err)
+	}
+
+	if printConfig {
+		out, err := cfg.YAML()
+		if err != nil {
+			log.Fatalf("unable to print configuration: %v", err)
+		}
+		fmt.Print(string(out))
+		return
+	}
+
+	logger := setupLogging(cfg.Log)
+
+	logger.Info("starting the service")
+
+	shutdownTracing, err := setupTracing(cfg.Tracing)
+	if err != nil {
+		logger.WithError(err).Fatal("unable to setup tracing")
+	}
+
+	m := metrics.NewMetrics()
 
-	eventBus := eventbus.NewInternalBus()
+	internalBus := eventbus.NewInternalBus()
+	eventBus := metrics.NewEventBus(internalBus, m)
 
-	gamesRepo := repository.NewMemoryGameRepository(eventBus)
-	usersRepo := repository.NewMemoryUserRepository(eventBus)
+	gamesRepo := repository.NewMemoryGameRepository(eventBus, logger)
+	gamesRepo.ObserveLockWait(m.ObserveLockWait)
+	m.WatchGames(gamesRepo)
+	usersRepo := repository.NewMemoryUserRepository(eventBus, logger)
+	teamsRepo := repository.NewMemoryTeamRepository(eventBus, logger)
+	roomsRepo := repository.NewMemoryRoomRepository(eventBus, logger)
+	roundsRepo := repository.NewMemoryRoundRepository()
+	batchesRepo := repository.NewMemoryBatchRepository(eventBus, logger)
+
+	var snapshot *repository.FileSnapshot
+	if cfg.Storage.Backend == config.StorageFile {
+		snapshot, err = repository.NewFileSnapshot(
+			cfg.Storage.Path, gamesRepo, usersRepo, teamsRepo, roomsRepo, roundsRepo, batchesRepo,
+		)
+		if err != nil {
+			logger.WithError(err).Fatal("unable to create storage snapshot")
+		}
+		if err := snapshot.Restore(); err != nil {
+			logger.WithError(err).Fatal("unable to restore storage snapshot")
+		}
+	}
 
-	gamesService, err := games.NewService(gamesRepo, eventBus)
+	gamesService, err := games.NewService(gamesRepo, eventBus, games.Limits{
+		MaxGamesPerUser:   cfg.Limits.MaxGamesPerUser,
+		MaxPlayersPerGame: cfg.Limits.MaxPlayersPerGame,
+	}, logger)
 	if err != nil {
-		log.Fatalf("unable to create games service: %v", err)
+		logger.WithError(err).Fatal("unable to create games service")
 	}
 
 	usersService, err := users.NewService(usersRepo)
 	if err != nil {
-		log.Fatalf("unable to create users service: %v", err)
+		logger.WithError(err).Fatal("unable to create users service")
+	}
+
+	authenticator := auth.NewUserAuthenticator(usersService, cfg.Auth.TokenSecret)
+
+	auditService, err := audit.NewService(gamesRepo, gamesRepo, usersRepo, eventBus, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("unable to create audit service")
 	}
 
-	authenticator := auth.NewUserAuthenticator(usersService)
+	commands := logging.NewGamesService(tracing.NewGamesService(metrics.NewGamesService(gamesService, m)), logger)
 
-	api, err := http.NewAPI(usersService, authenticator)
+	teamsService, err := teams.NewService(teamsRepo, gamesRepo, commands)
 	if err != nil {
-		log.Fatalf("unable to create http API: %v", err)
+		logger.WithError(err).Fatal("unable to create teams service")
 	}
 
-	fe := http.NewFrontend()
+	roomsService, err := rooms.NewService(roomsRepo, gamesRepo, commands)
+	if err != nil {
+		logger.WithError(err).Fatal("unable to create rooms service")
+	}
+
+	reportsService, err := reports.NewService(roundsRepo, teamsRepo, roomsRepo, gamesRepo, eventBus, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("unable to create reports service")
+	}
+
+	batchesService, err := batches.NewService(batchesRepo, teamsRepo, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("unable to create batches service")
+	}
 
-	r := gin.Default()
-	r.Use(gzip.Gzip(gzip.DefaultCompression))
+	api, err := http.NewAPI(
+		usersService, auditService, teamsService, roomsService, reportsService, batchesService, authenticator, logger,
+	)
+	if err != nil {
+		logger.WithError(err).Fatal("unable to create http API")
+	}
+
+	// limiters are shared by both APIs, so a client can not double its budget by switching the transport.
+	ipLimiter := ratelimit.NewLimiter(cfg.Limits.IPRate, cfg.Limits.IPBurst)
+	userLimiter := ratelimit.NewLimiter(cfg.Limits.UserRate, cfg.Limits.UserBurst)
+	api.LimitRate(ipLimiter, userLimiter)
+	proxies, err := ratelimit.NewProxies(cfg.Limits.TrustedProxies)
+	if err != nil {
+		logger.WithError(err).Fatal("unable to parse trusted proxies")
+	}
+	api.TrustProxies(proxies)
+
+	fe := http.NewFrontend(cfg.HTTP.StaticDir)
+
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.Use(http.RequestLogger(logger, proxies))
+	r.Use(m.HTTPMiddleware())
+	r.Use(http.Tracing())
+	r.Use(gzip.Gzip(cfg.HTTP.GzipLevel))
+	r.Use(http.MaxBodySize(cfg.Limits.MaxBodyBytes))
+	if len(cfg.HTTP.CORSOrigins) > 0 {
+		r.Use(http.CORS(cfg.HTTP.CORSOrigins))
+	}
 
-	asyncAPI := async.NewAPI(gamesService, authenticator)
+	asyncAPI := async.NewAPI(commands, authenticator, logger)
+	asyncAPI.LimitRate(ipLimiter, userLimiter)
+	asyncAPI.TrustProxies(proxies)
+	asyncAPI.LimitChat(ratelimit.NewLimiter(cfg.Limits.ChatRate, cfg.Limits.ChatBurst))
+	m.WatchSockets(asyncAPI)
 
-	_, err = state.NewService(gamesRepo, usersRepo, asyncAPI, eventBus)
+	_, err = state.NewService(gamesRepo, usersRepo, asyncAPI, eventBus, logger)
 	if err != nil {
-		log.Fatalf("unable to create game state service: %v", err)
+		logger.WithError(err).Fatal("unable to create game state service")
 	}
 
+	cleaner := janitor.NewJanitor(gamesService, usersService, cfg.Cleanup.Interval, cfg.Cleanup.GameTTL, cfg.Cleanup.UserTTL, logger)
+	cleaner.Start()
+
+	deadlines, err := scheduler.NewScheduler(batchesService, cfg.Scheduler.Interval, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("unable to create deadlines scheduler")
+	}
+	deadlines.Start()
+
 	api.SetupRoutes(r)
 	asyncAPI.SetupRoutes(r)
+	if cfg.Metrics.Enabled {
+		r.GET("/metrics", gin.WrapH(m.Handler()))
+	}
 	fe.SetupRoutes(r)
 
-	if err := r.Run(); err != nil {
-		log.Fatalf("failed service: %v", err)
+	srv := &nethttp.Server{
+		Addr:    cfg.HTTP.ListenAddr,
+		Handler: r,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if cfg.HTTP.TLSEnabled() {
+			serveErr <- srv.ListenAndServeTLS(cfg.HTTP.TLSCertFile, cfg.HTTP.TLSKeyFile)
+		} else {
+			serveErr <- srv.ListenAndServe()
+		}
+	}()
+
+	var failed error
+	select {
+	case failed = <-serveErr:
+		logger.WithError(failed).Error("service failed")
+	case <-ctx.Done():
+		logger.Info("shutting down the service")
+	}
+	// the next signal kills the service immediately
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+
+	// producers are stopped first, so the event bus can become idle and the flushed data is final
+	if err := asyncAPI.Shutdown(shutdownCtx); err != nil {
+		logger.WithError(err).Error("sockets shutdown failed")
 	}
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.WithError(err).Error("http server shutdown failed")
+	}
+	cleaner.Stop()
+	deadlines.Stop()
+	if err := internalBus.Drain(shutdownCtx); err != nil {
+		logger.WithError(err).Error("event bus draining failed")
+	}
+	if snapshot != nil {
+		if err := snapshot.Flush(); err != nil {
+			logger.WithError(err).Error("storage flushing failed")
+		}
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.WithError(err).Error("tracing shutdown failed")
+	}
+	cancel()
+
+	if failed != nil {
+		os.Exit(1)
+	}
+	logger.Info("the service is stopped")
+}
+
+// setupTracing installs the configured traces exporter, the returned function flushes pending spans.
+func setupTracing(cfg config.TracingConfig) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch cfg.Exporter {
+	case config.TracingStdout:
+		exporter, err = tracing.NewStdoutExporter(os.Stdout)
+	case config.TracingOTLP:
+		exporter, err = tracing.NewOTLPExporter(context.Background(), cfg.Endpoint, cfg.Insecure)
+	default:
+		return func(context.Context) error { return nil }, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return tracing.Setup(exporter, cfg.SampleRatio), nil
+}
+
+// setupLogging creates the service logger, it is injected into all the components.
+func setupLogging(cfg config.LogConfig) *logrus.Entry {
+	// the level is validated with the configuration
+	level, _ := logrus.ParseLevel(cfg.Level)
+
+	if level < logrus.DebugLevel {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	return logging.New(os.Stderr, level, cfg.Format == config.LogFormatJSON)
 }
@@ -0,0 +1,19 @@
+// Package audit contains the append-only trail of actions performed in games.
+package audit
+
+import "time"
+
+// Entry is a single recorded game action, entries are never changed once appended.
+type Entry struct {
+	GameID string
+	Action string
+	// ActorID is an ID of the user who performed the action, it is empty for system actions.
+	ActorID string
+	// ActorHandle and ActorName identify the actor publicly as they were at the time of the action.
+	ActorHandle string
+	ActorName   string
+	// Before and After contain changed values of updates.
+	Before     map[string]string
+	After      map[string]string
+	OccurredAt time.Time
+}
@@ -0,0 +1,26 @@
+package audit
+
+import (
+	"context"
+
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/users"
+)
+
+// Repository is a contract to persist the audit trail, it should be stored together with the games.
+type Repository interface {
+	// AppendAuditEntry adds the entry to the game trail, entries of unknown games are dropped.
+	AppendAuditEntry(ctx context.Context, entry Entry) error
+	// GetAuditLog returns the game trail in the order of appending.
+	GetAuditLog(ctx context.Context, gameID string) ([]Entry, error)
+}
+
+// GameRepository is a contract to fetch games data.
+type GameRepository interface {
+	Get(ctx context.Context, id string) (*games.Game, error)
+}
+
+// UsersRepository is a contract to fetch users data.
+type UsersRepository interface {
+	Get(ctx context.Context, id string) (*users.User, error)
+}
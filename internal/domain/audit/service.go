@@ -0,0 +1,113 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+
+	"planningpoker/internal/domain"
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/games"
+)
+
+// Service records game actions published with domain events and gives the trail to game players.
+type Service struct {
+	auditRepo Repository
+	gamesRepo GameRepository
+	usersRepo UsersRepository
+	logger    *logrus.Entry
+}
+
+// NewService creates a new audit service instance.
+func NewService(
+	ar Repository, gr GameRepository, ur UsersRepository, eventBus events.EventBus, logger *logrus.Entry,
+) (*Service, error) {
+	if ar == nil {
+		return nil, errors.New("audit repository should be provided")
+	}
+	if gr == nil {
+		return nil, errors.New("games repository should be provided")
+	}
+	if ur == nil {
+		return nil, errors.New("users repository should be provided")
+	}
+	if eventBus == nil {
+		return nil, errors.New("event bus should be provided")
+	}
+	if logger == nil {
+		return nil, errors.New("logger should be provided")
+	}
+
+	srv := &Service{
+		auditRepo: ar,
+		gamesRepo: gr,
+		usersRepo: ur,
+		logger:    logger,
+	}
+
+	eventBus.Subscribe(srv.processGameUpdated, events.EventTypeGameUpdated)
+
+	return srv, nil
+}
+
+// GameLog returns the game trail ordered by time, only game players can read it.
+func (s *Service) GameLog(ctx context.Context, gameID, userID string) ([]Entry, error) {
+	game, err := s.gamesRepo.Get(ctx, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("get game: %w", err)
+	}
+	if game == nil {
+		return nil, games.ErrGameNotFound
+	}
+	if !game.IsPlayer(userID) {
+		return nil, games.ErrNotAPlayer
+	}
+
+	list, err := s.auditRepo.GetAuditLog(ctx, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("get audit log: %w", err)
+	}
+
+	// events are consumed concurrently, so entries might be appended out of order
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].OccurredAt.Before(list[j].OccurredAt)
+	})
+
+	return list, nil
+}
+
+func (s *Service) processGameUpdated(ctx context.Context, e events.DomainEvent) {
+	action, ok := e.Payload().(games.Action)
+	if !ok {
+		return
+	}
+
+	entry := Entry{
+		GameID:      e.AggregateID(),
+		Action:      action.Name,
+		ActorID:     action.ActorID,
+		ActorHandle: action.ActorHandle,
+		Before:      action.Before,
+		After:       action.After,
+		OccurredAt:  e.OccurredAt(),
+	}
+
+	logger := s.logger.WithContext(ctx).WithFields(logrus.Fields{domain.LogFieldGameID: entry.GameID, domain.LogFieldUserID: entry.ActorID})
+
+	if entry.ActorID != "" {
+		user, err := s.usersRepo.Get(ctx, entry.ActorID)
+		if err != nil {
+			logger.WithError(err).Warn("unable to fetch the actor name")
+		}
+		if user != nil {
+			entry.ActorName = user.Name()
+		}
+	}
+
+	if err := s.auditRepo.AppendAuditEntry(ctx, entry); err != nil {
+		logger.WithError(err).Error("unable to append the audit entry")
+	}
+}
@@ -0,0 +1,234 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/audit"
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/users"
+	"planningpoker/test"
+)
+
+func TestNewService(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		auditRepo audit.Repository
+		gameRepo  audit.GameRepository
+		usersRepo audit.UsersRepository
+		eventBus  events.EventBus
+		logger    *logrus.Entry
+		expError  string
+	}{
+		"success": {
+			auditRepo: &auditRepoStub{},
+			gameRepo:  gamesRepoStub{},
+			usersRepo: usersRepoStub{},
+			eventBus:  &eventBusStub{},
+			logger:    test.NewLogger(),
+		},
+		"fail on no audit repo": {
+			gameRepo:  gamesRepoStub{},
+			usersRepo: usersRepoStub{},
+			eventBus:  &eventBusStub{},
+			logger:    test.NewLogger(),
+			expError:  "audit repository should be provided",
+		},
+		"fail on no game repo": {
+			auditRepo: &auditRepoStub{},
+			usersRepo: usersRepoStub{},
+			eventBus:  &eventBusStub{},
+			logger:    test.NewLogger(),
+			expError:  "games repository should be provided",
+		},
+		"fail on no users repo": {
+			auditRepo: &auditRepoStub{},
+			gameRepo:  gamesRepoStub{},
+			eventBus:  &eventBusStub{},
+			logger:    test.NewLogger(),
+			expError:  "users repository should be provided",
+		},
+		"fail on no event bus": {
+			auditRepo: &auditRepoStub{},
+			gameRepo:  gamesRepoStub{},
+			usersRepo: usersRepoStub{},
+			logger:    test.NewLogger(),
+			expError:  "event bus should be provided",
+		},
+		"fail on no logger": {
+			auditRepo: &auditRepoStub{},
+			gameRepo:  gamesRepoStub{},
+			usersRepo: usersRepoStub{},
+			eventBus:  &eventBusStub{},
+			expError:  "logger should be provided",
+		},
+	}
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			srv, err := audit.NewService(tt.auditRepo, tt.gameRepo, tt.usersRepo, tt.eventBus, tt.logger)
+
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				assert.Nil(t, srv)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, srv)
+			}
+		})
+	}
+}
+
+func TestService_RecordsActions(t *testing.T) {
+	t.Parallel()
+
+	game := test.NewTestGame(t, test.NewSimpleGame(t, true)).UserJoins(test.User1).UserVotes(test.User1, "XS").Instance()
+	repo := &auditRepoStub{}
+	bus := &eventBusStub{}
+	_, err := audit.NewService(repo, gamesRepoStub{}, usersRepoStub{
+		user: users.NewRaw(test.User1, "John", time.Now()),
+	}, bus, test.NewLogger())
+	require.NoError(t, err)
+
+	for _, e := range game.GetEvents() {
+		bus.consumer(context.Background(), e)
+	}
+	bus.consumer(context.Background(), events.NewDomainEventBuilder(events.EventTypeGameUpdated).Build())
+
+	require.Len(t, repo.entries, 3)
+	assert.Equal(t, games.ActionCreate, repo.entries[0].Action)
+	assert.Empty(t, repo.entries[0].ActorName)
+
+	vote := repo.entries[2]
+	assert.Equal(t, game.ID(), vote.GameID)
+	assert.Equal(t, games.ActionVote, vote.Action)
+	assert.Equal(t, test.User1, vote.ActorID)
+	assert.Equal(t, game.Players()[test.User1].Handle, vote.ActorHandle)
+	assert.Equal(t, "John", vote.ActorName)
+	assert.False(t, vote.OccurredAt.IsZero())
+}
+
+func TestService_GameLog(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	game := test.NewTestGame(t, test.NewSimpleGame(t, true)).UserJoins(test.User1).Instance()
+	entries := []audit.Entry{
+		{Action: games.ActionVote, OccurredAt: now.Add(time.Second)},
+		{Action: games.ActionJoin, OccurredAt: now},
+	}
+
+	testCases := map[string]struct {
+		gameRepo   audit.GameRepository
+		auditRepo  *auditRepoStub
+		userID     string
+		expError   string
+		expActions []string
+	}{
+		"success": {
+			gameRepo:   gamesRepoStub{game: game},
+			auditRepo:  &auditRepoStub{entries: entries},
+			userID:     test.User1,
+			expActions: []string{games.ActionJoin, games.ActionVote},
+		},
+		"fail on not a player": {
+			gameRepo:  gamesRepoStub{game: game},
+			auditRepo: &auditRepoStub{entries: entries},
+			userID:    test.User2,
+			expError:  "user is not a player",
+		},
+		"fail on unknown game": {
+			gameRepo:  gamesRepoStub{},
+			auditRepo: &auditRepoStub{},
+			userID:    test.User1,
+			expError:  "game not found",
+		},
+		"fail on games repo error": {
+			gameRepo:  gamesRepoStub{err: errors.New("failed")},
+			auditRepo: &auditRepoStub{},
+			userID:    test.User1,
+			expError:  "get game: failed",
+		},
+		"fail on audit repo error": {
+			gameRepo:  gamesRepoStub{game: game},
+			auditRepo: &auditRepoStub{err: errors.New("failed")},
+			userID:    test.User1,
+			expError:  "get audit log: failed",
+		},
+	}
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			srv, err := audit.NewService(tt.auditRepo, tt.gameRepo, usersRepoStub{}, &eventBusStub{}, test.NewLogger())
+			require.NoError(t, err)
+
+			list, err := srv.GameLog(context.Background(), game.ID(), tt.userID)
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				return
+			}
+
+			require.NoError(t, err)
+			actions := make([]string, len(list))
+			for i, e := range list {
+				actions[i] = e.Action
+			}
+			assert.Equal(t, tt.expActions, actions)
+		})
+	}
+}
+
+type auditRepoStub struct {
+	entries []audit.Entry
+	err     error
+}
+
+func (r *auditRepoStub) AppendAuditEntry(_ context.Context, entry audit.Entry) error {
+	r.entries = append(r.entries, entry)
+	return r.err
+}
+
+func (r *auditRepoStub) GetAuditLog(context.Context, string) ([]audit.Entry, error) {
+	list := make([]audit.Entry, len(r.entries))
+	copy(list, r.entries)
+	return list, r.err
+}
+
+type gamesRepoStub struct {
+	game *games.Game
+	err  error
+}
+
+func (r gamesRepoStub) Get(context.Context, string) (*games.Game, error) {
+	return r.game, r.err
+}
+
+type usersRepoStub struct {
+	user *users.User
+}
+
+func (r usersRepoStub) Get(context.Context, string) (*users.User, error) {
+	return r.user, nil
+}
+
+type eventBusStub struct {
+	consumer events.Consumer
+}
+
+func (b *eventBusStub) Publish(context.Context, events.DomainEvent) error {
+	return nil
+}
+
+func (b *eventBusStub) Subscribe(consumer events.Consumer, _ ...string) {
+	b.consumer = consumer
+}
@@ -0,0 +1,286 @@
+// Package batches contains domain level asynchronous voting logic.
+package batches
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"planningpoker/internal/domain"
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/games"
+)
+
+// Batch is a domain aggregate of tickets voted asynchronously until the deadline.
+// A ticket is revealed as soon as every participant voted it, the rest are revealed at the deadline.
+// A batch outside of teams is revealed early only once someone besides the facilitator joined it.
+type Batch struct {
+	domain.BaseAggregate
+	id            string
+	name          string
+	facilitatorID string
+	// teamID is an ID of the team the batch is published for, it is empty for batches outside of teams.
+	teamID    string
+	cardsDeck games.CardsDeck
+	deadline  time.Time
+	// participants are user IDs mapped to the time they joined the batch.
+	participants map[string]time.Time
+	tickets      []*Ticket
+	createdAt    time.Time
+}
+
+// Ticket is an entity of a batch ticket with its votes.
+type Ticket struct {
+	// ID is a short ticket identifier within the batch.
+	ID   string
+	Name string
+	URL  string
+	// Votes are cards mapped to user IDs of participants who voted.
+	Votes map[string]games.Card
+	// RevealedAt is zero until the ticket votes are revealed.
+	RevealedAt time.Time
+}
+
+// IsRevealed checks if the ticket votes are revealed.
+func (t Ticket) IsRevealed() bool {
+	return !t.RevealedAt.IsZero()
+}
+
+// NewBatch publishes a new batch, the facilitator and the provided participants can vote right away.
+func NewBatch(cmd CreateBatchCommand, participantIDs []string) *Batch {
+	b := &Batch{
+		id:            newID(),
+		name:          cmd.Name,
+		facilitatorID: cmd.UserID,
+		teamID:        cmd.TeamID,
+		cardsDeck:     cmd.CardsDeck,
+		deadline:      cmd.Deadline,
+		participants:  make(map[string]time.Time),
+		tickets:       make([]*Ticket, len(cmd.Tickets)),
+		createdAt:     time.Now(),
+	}
+
+	b.participants[cmd.UserID] = b.createdAt
+	for _, uid := range participantIDs {
+		b.participants[uid] = b.createdAt
+	}
+
+	for i, t := range cmd.Tickets {
+		b.tickets[i] = &Ticket{
+			ID:    newID()[:12],
+			Name:  t.Name,
+			URL:   t.URL,
+			Votes: make(map[string]games.Card),
+		}
+	}
+	b.setChanged()
+
+	return b
+}
+
+// NewRaw instantiates a batch aggregate from raw data.
+// It should never be used in any logic except aggregate hydration from any serialized format (db, etc...)
+func NewRaw(
+	id, name, facilitatorID, teamID string,
+	deck games.CardsDeck,
+	deadline time.Time,
+	participants map[string]time.Time,
+	tickets []*Ticket,
+	createdAt time.Time,
+) *Batch {
+	return &Batch{
+		id:            id,
+		name:          name,
+		facilitatorID: facilitatorID,
+		teamID:        teamID,
+		cardsDeck:     deck,
+		deadline:      deadline,
+		participants:  participants,
+		tickets:       tickets,
+		createdAt:     createdAt,
+	}
+}
+
+// ID returns the batch ID.
+func (b Batch) ID() string {
+	return b.id
+}
+
+// Name returns the batch name.
+func (b Batch) Name() string {
+	return b.name
+}
+
+// FacilitatorID returns the ID of the user who published the batch.
+func (b Batch) FacilitatorID() string {
+	return b.facilitatorID
+}
+
+// TeamID returns the ID of the team the batch is published for, empty if there is no team.
+func (b Batch) TeamID() string {
+	return b.teamID
+}
+
+// CardsDeck returns the cards deck tickets are voted with.
+func (b Batch) CardsDeck() games.CardsDeck {
+	return b.cardsDeck
+}
+
+// Deadline returns the time all the tickets are revealed at.
+func (b Batch) Deadline() time.Time {
+	return b.deadline
+}
+
+// Participants returns user IDs of all participants mapped to the time they joined.
+func (b Batch) Participants() map[string]time.Time {
+	return b.participants
+}
+
+// ParticipantIDs returns user IDs of all participants ordered by the time they joined.
+func (b Batch) ParticipantIDs() []string {
+	ids := make([]string, 0, len(b.participants))
+	for id := range b.participants {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		ti, tj := b.participants[ids[i]], b.participants[ids[j]]
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
+
+// Tickets returns the batch tickets in the published order.
+func (b Batch) Tickets() []*Ticket {
+	return b.tickets
+}
+
+// CreatedAt returns the time the batch was published at.
+func (b Batch) CreatedAt() time.Time {
+	return b.createdAt
+}
+
+// IsParticipant checks if specific user can vote the batch tickets.
+func (b Batch) IsParticipant(uid string) bool {
+	_, ok := b.participants[uid]
+	return ok
+}
+
+// IsFacilitator checks if specific user published the batch.
+func (b Batch) IsFacilitator(uid string) bool {
+	return b.facilitatorID == uid
+}
+
+// IsOverdue checks if the deadline has passed but some tickets are not revealed yet.
+func (b Batch) IsOverdue(now time.Time) bool {
+	return !now.Before(b.deadline) && !b.IsClosed()
+}
+
+// IsClosed checks if all the tickets are revealed.
+func (b Batch) IsClosed() bool {
+	for _, t := range b.tickets {
+		if !t.IsRevealed() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Join adds the user to the batch participants, joining twice is not an error.
+// Tickets which are not revealed yet wait for the new participant vote.
+func (b *Batch) Join(cmd JoinBatchCommand) {
+	if b.IsParticipant(cmd.UserID) {
+		return
+	}
+
+	b.participants[cmd.UserID] = time.Now()
+	b.setChanged()
+}
+
+// Vote votes the ticket, a vote can be changed until the ticket is revealed.
+// The ticket is revealed when it is the last missing vote, see everyoneVoted.
+func (b *Batch) Vote(cmd VoteCommand) error {
+	if !b.IsParticipant(cmd.UserID) {
+		return ErrNotAParticipant
+	}
+
+	ticket := b.ticket(cmd.TicketID)
+	if ticket == nil {
+		return ErrTicketNotFound
+	}
+
+	if ticket.IsRevealed() {
+		return ErrTicketRevealed
+	}
+
+	now := time.Now()
+	if !now.Before(b.deadline) {
+		return ErrDeadlinePassed
+	}
+
+	if !b.cardsDeck.IsInDeck(cmd.Card) {
+		return games.ErrUnknownCard
+	}
+
+	ticket.Votes[cmd.UserID] = cmd.Card
+	if b.everyoneVoted(ticket) {
+		ticket.RevealedAt = now
+	}
+	b.setChanged()
+
+	return nil
+}
+
+// RevealOverdue reveals all the tickets which are not revealed yet once the deadline has passed.
+func (b *Batch) RevealOverdue(now time.Time) {
+	if !b.IsOverdue(now) {
+		return
+	}
+
+	for _, t := range b.tickets {
+		if !t.IsRevealed() {
+			t.RevealedAt = now
+		}
+	}
+	b.setChanged()
+}
+
+func (b Batch) ticket(id string) *Ticket {
+	for _, t := range b.tickets {
+		if t.ID == id {
+			return t
+		}
+	}
+
+	return nil
+}
+
+func (b Batch) everyoneVoted(t *Ticket) bool {
+	// an open batch starts with the facilitator only, their vote alone should not reveal the ticket
+	if b.teamID == "" && len(b.participants) < 2 {
+		return false
+	}
+
+	for uid := range b.participants {
+		if _, ok := t.Votes[uid]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (b *Batch) setChanged() {
+	b.AddEvent(events.NewDomainEventBuilder(events.EventTypeBatchUpdated).ForAggregate(b.id).Build())
+}
+
+func newID() string {
+	return strings.ReplaceAll(uuid.New().String(), "-", "")
+}
@@ -0,0 +1,164 @@
+package batches_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/batches"
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/games"
+	"planningpoker/test"
+)
+
+func TestNewBatch(t *testing.T) {
+	t.Parallel()
+
+	batch := test.NewBatch(t, test.User1, "first", "second")
+
+	assert.NotEmpty(t, batch.ID())
+	assert.True(t, batch.IsFacilitator(test.User1))
+	assert.Equal(t, []string{test.User1}, batch.ParticipantIDs())
+	require.Len(t, batch.Tickets(), 2)
+	assert.NotEqual(t, batch.Tickets()[0].ID, batch.Tickets()[1].ID)
+	assert.False(t, batch.IsClosed())
+	require.Len(t, batch.GetEvents(), 1)
+	assert.Equal(t, events.EventTypeBatchUpdated, batch.GetEvents()[0].EventType())
+}
+
+func TestNewCreateBatchCommand(t *testing.T) {
+	t.Parallel()
+
+	tomorrow := time.Now().Add(24 * time.Hour)
+	tickets := []batches.TicketData{{Name: " first ", URL: " https://example.com/1 "}}
+
+	testCases := map[string]struct {
+		name     string
+		deck     games.CardsDeck
+		tickets  []batches.TicketData
+		deadline time.Time
+		expError string
+	}{
+		"success":                    {name: " batch ", tickets: tickets, deadline: tomorrow},
+		"fail on too long name":      {name: strings.Repeat("a", 129), tickets: tickets, deadline: tomorrow, expError: batches.ErrBatchNameTooLong.Error()},
+		"fail on dimensional deck":   {deck: test.NewTestDimensionalDeck(t, ""), tickets: tickets, deadline: tomorrow, expError: batches.ErrDimensionalDeck.Error()},
+		"fail on no tickets":         {deadline: tomorrow, expError: batches.ErrInvalidTicketsNumber.Error()},
+		"fail on too many tickets":   {tickets: make([]batches.TicketData, batches.MaxTickets+1), deadline: tomorrow, expError: batches.ErrInvalidTicketsNumber.Error()},
+		"fail on blank ticket name":  {tickets: []batches.TicketData{{Name: " "}}, deadline: tomorrow, expError: batches.ErrEmptyTicketName.Error()},
+		"fail on long ticket name":   {tickets: []batches.TicketData{{Name: strings.Repeat("a", 129)}}, deadline: tomorrow, expError: batches.ErrTicketNameTooLong.Error()},
+		"fail on wrong ticket URL":   {tickets: []batches.TicketData{{Name: "a", URL: "javascript:alert(1)"}}, deadline: tomorrow, expError: games.ErrInvalidTicketURL.Error()},
+		"fail on past deadline":      {tickets: tickets, deadline: time.Now().Add(-time.Minute), expError: batches.ErrInvalidDeadline.Error()},
+		"fail on too distant future": {tickets: tickets, deadline: time.Now().Add(batches.MaxVotingPeriod + time.Hour), expError: batches.ErrInvalidDeadline.Error()},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			deck := tt.deck
+			if !deck.IsDimensional() {
+				deck = test.NewTestDeck(t)
+			}
+
+			cmd, err := batches.NewCreateBatchCommand(tt.name, test.User1, deck, tt.tickets, tt.deadline)
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				assert.Nil(t, cmd)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "batch", cmd.Name)
+			assert.Equal(t, []batches.TicketData{{Name: "first", URL: "https://example.com/1"}}, cmd.Tickets)
+		})
+	}
+}
+
+func TestBatch_VoteRevealsWhenEveryoneVoted(t *testing.T) {
+	t.Parallel()
+
+	batch := test.NewBatch(t, test.User1, "first", "second")
+	batch.Join(batches.JoinBatchCommand{BatchID: batch.ID(), UserID: test.User2})
+	first, second := batch.Tickets()[0], batch.Tickets()[1]
+
+	require.NoError(t, batch.Vote(voteCmd(batch, first, test.User1, "XS")))
+	// a vote can be changed until the ticket is revealed
+	require.NoError(t, batch.Vote(voteCmd(batch, first, test.User1, "S")))
+	assert.False(t, first.IsRevealed())
+
+	require.NoError(t, batch.Vote(voteCmd(batch, first, test.User2, "S")))
+	assert.True(t, first.IsRevealed())
+	assert.Equal(t, map[string]games.Card{test.User1: "S", test.User2: "S"}, first.Votes)
+	assert.ErrorIs(t, batch.Vote(voteCmd(batch, first, test.User2, "XS")), batches.ErrTicketRevealed)
+
+	// a late participant is waited for on tickets which are not revealed yet
+	require.NoError(t, batch.Vote(voteCmd(batch, second, test.User1, "XS")))
+	batch.Join(batches.JoinBatchCommand{BatchID: batch.ID(), UserID: test.User3})
+	require.NoError(t, batch.Vote(voteCmd(batch, second, test.User2, "XS")))
+	assert.False(t, second.IsRevealed())
+	require.NoError(t, batch.Vote(voteCmd(batch, second, test.User3, "S")))
+	assert.True(t, second.IsRevealed())
+	assert.True(t, batch.IsClosed())
+}
+
+func TestBatch_VoteWaitsForOpenBatchParticipants(t *testing.T) {
+	t.Parallel()
+
+	batch := test.NewBatch(t, test.User1, "ticket")
+	ticket := batch.Tickets()[0]
+
+	require.NoError(t, batch.Vote(voteCmd(batch, ticket, test.User1, "XS")))
+	assert.False(t, ticket.IsRevealed(), "the facilitator vote alone should not reveal an open batch")
+
+	batch.Join(batches.JoinBatchCommand{BatchID: batch.ID(), UserID: test.User2})
+	require.NoError(t, batch.Vote(voteCmd(batch, ticket, test.User2, "S")))
+	assert.True(t, ticket.IsRevealed())
+}
+
+func TestBatch_VoteFails(t *testing.T) {
+	t.Parallel()
+
+	batch := test.NewBatch(t, test.User1, "ticket")
+	ticket := batch.Tickets()[0]
+
+	assert.ErrorIs(t, batch.Vote(voteCmd(batch, ticket, test.User2, "XS")), batches.ErrNotAParticipant)
+	assert.ErrorIs(t, batch.Vote(voteCmd(batch, &batches.Ticket{ID: "unknown"}, test.User1, "XS")), batches.ErrTicketNotFound)
+	assert.ErrorIs(t, batch.Vote(voteCmd(batch, ticket, test.User1, "XL")), games.ErrUnknownCard)
+
+	overdue := batches.NewRaw("id", "name", test.User1, "", test.NewTestDeck(t), time.Now().Add(-time.Minute),
+		batch.Participants(), batch.Tickets(), batch.CreatedAt())
+	assert.ErrorIs(t, overdue.Vote(voteCmd(overdue, ticket, test.User1, "XS")), batches.ErrDeadlinePassed)
+}
+
+func TestBatch_RevealOverdue(t *testing.T) {
+	t.Parallel()
+
+	batch := test.NewBatch(t, test.User1, "first", "second")
+	batch.Join(batches.JoinBatchCommand{BatchID: batch.ID(), UserID: test.User2})
+	require.NoError(t, batch.Vote(voteCmd(batch, batch.Tickets()[0], test.User1, "XS")))
+	require.NoError(t, batch.Vote(voteCmd(batch, batch.Tickets()[0], test.User2, "S")))
+	revealedAt := batch.Tickets()[0].RevealedAt
+	require.NoError(t, batch.Vote(voteCmd(batch, batch.Tickets()[1], test.User1, "XS")))
+
+	batch.RevealOverdue(time.Now())
+	assert.False(t, batch.Tickets()[1].IsRevealed())
+	assert.False(t, batch.IsOverdue(time.Now()))
+
+	afterDeadline := batch.Deadline().Add(time.Second)
+	assert.True(t, batch.IsOverdue(afterDeadline))
+	batch.RevealOverdue(afterDeadline)
+
+	assert.True(t, batch.IsClosed())
+	assert.False(t, batch.IsOverdue(afterDeadline))
+	assert.Equal(t, revealedAt, batch.Tickets()[0].RevealedAt)
+	assert.Equal(t, afterDeadline, batch.Tickets()[1].RevealedAt)
+	assert.Equal(t, map[string]games.Card{test.User1: "XS"}, batch.Tickets()[1].Votes)
+}
+
+func voteCmd(batch *batches.Batch, ticket *batches.Ticket, uid string, card games.Card) batches.VoteCommand {
+	return batches.VoteCommand{BatchID: batch.ID(), TicketID: ticket.ID, UserID: uid, Card: card}
+}
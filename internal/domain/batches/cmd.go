@@ -0,0 +1,131 @@
+package batches
+
+import (
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"planningpoker/internal/domain/games"
+)
+
+const (
+	// MaxNameLength is the maximal batch name length in characters.
+	MaxNameLength = 128
+	// MaxTickets is the maximal number of tickets in a batch.
+	MaxTickets = 50
+	// MaxVotingPeriod is the maximal time between publishing a batch and its deadline.
+	MaxVotingPeriod = 30 * 24 * time.Hour
+)
+
+// TicketData is a ticket to publish in a batch.
+type TicketData struct {
+	Name string
+	URL  string
+}
+
+// CreateBatchCommand is a batch publishing command.
+type CreateBatchCommand struct {
+	UserID    string
+	Name      string
+	CardsDeck games.CardsDeck
+	Tickets   []TicketData
+	Deadline  time.Time
+	// TeamID makes all the team members participants, it is empty for batches outside of teams.
+	TeamID string
+}
+
+// NewCreateBatchCommand creates a new command instance, the names and URLs are trimmed.
+func NewCreateBatchCommand(
+	name, userID string, deck games.CardsDeck, tickets []TicketData, deadline time.Time,
+) (*CreateBatchCommand, error) {
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return nil, ErrBatchNameTooLong
+	}
+
+	if deck.IsDimensional() {
+		return nil, ErrDimensionalDeck
+	}
+
+	if len(tickets) == 0 || len(tickets) > MaxTickets {
+		return nil, ErrInvalidTicketsNumber
+	}
+
+	list := make([]TicketData, len(tickets))
+	for i, t := range tickets {
+		ticket, err := newTicketData(t.Name, t.URL)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = *ticket
+	}
+
+	now := time.Now()
+	if !deadline.After(now) || deadline.After(now.Add(MaxVotingPeriod)) {
+		return nil, ErrInvalidDeadline
+	}
+
+	return &CreateBatchCommand{
+		UserID:    userID,
+		Name:      name,
+		CardsDeck: deck,
+		Tickets:   list,
+		Deadline:  deadline,
+	}, nil
+}
+
+// JoinBatchCommand is a command to become a batch participant.
+type JoinBatchCommand struct {
+	BatchID string
+	UserID  string
+}
+
+// NewJoinBatchCommand creates a new command instance.
+func NewJoinBatchCommand(batchID, userID string) (*JoinBatchCommand, error) {
+	return &JoinBatchCommand{
+		BatchID: batchID,
+		UserID:  userID,
+	}, nil
+}
+
+// VoteCommand is a command to vote a batch ticket.
+type VoteCommand struct {
+	BatchID  string
+	TicketID string
+	UserID   string
+	Card     games.Card
+}
+
+// NewVoteCommand creates a new command instance.
+func NewVoteCommand(batchID, ticketID, userID string, card games.Card) (*VoteCommand, error) {
+	return &VoteCommand{
+		BatchID:  batchID,
+		TicketID: ticketID,
+		UserID:   userID,
+		Card:     card,
+	}, nil
+}
+
+func newTicketData(name, ticketURL string) (*TicketData, error) {
+	name, ticketURL = strings.TrimSpace(name), strings.TrimSpace(ticketURL)
+	if name == "" {
+		return nil, ErrEmptyTicketName
+	}
+	if utf8.RuneCountInString(name) > games.MaxNameLength {
+		return nil, ErrTicketNameTooLong
+	}
+
+	if ticketURL != "" {
+		if utf8.RuneCountInString(ticketURL) > games.MaxTicketURLLength {
+			return nil, games.ErrTicketURLTooLong
+		}
+		// the URL is rendered as a link, so other schemes, e.g. javascript:, are not allowed.
+		u, err := url.Parse(ticketURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, games.ErrInvalidTicketURL
+		}
+	}
+
+	return &TicketData{Name: name, URL: ticketURL}, nil
+}
@@ -0,0 +1,40 @@
+package batches
+
+import "planningpoker/internal/domain"
+
+var (
+	// ErrBatchNotFound is returned when the batch does not exist.
+	ErrBatchNotFound = domain.NewError(domain.KindNotFound, "batch_not_found", "batch not found")
+	// ErrTicketNotFound is returned when the batch has no ticket with the ID.
+	ErrTicketNotFound = domain.NewError(domain.KindNotFound, "ticket_not_found", "ticket not found")
+	// ErrNotAParticipant is returned when the user acts in a batch they did not join.
+	ErrNotAParticipant = domain.NewError(domain.KindForbidden, "not_a_participant", "user is not a batch participant")
+	// ErrNotATeamMember is returned when the user joins a team batch without being the team member.
+	ErrNotATeamMember = domain.NewError(domain.KindForbidden, "not_a_member", "user is not a team member")
+	// ErrTicketRevealed is returned on a vote of a ticket which votes are already revealed.
+	ErrTicketRevealed = domain.NewError(domain.KindConflict, "ticket_revealed", "ticket votes are already revealed")
+	// ErrDeadlinePassed is returned on a vote after the batch deadline.
+	ErrDeadlinePassed = domain.NewError(domain.KindConflict, "deadline_passed", "batch deadline has passed")
+	// ErrBatchNameTooLong is returned when the batch name is longer than MaxNameLength.
+	ErrBatchNameTooLong = domain.NewError(
+		domain.KindValidation, "invalid_batch_name", "batch name should be at most 128 characters long",
+	)
+	// ErrDimensionalDeck is returned when a batch is published with a dimensional deck.
+	ErrDimensionalDeck = domain.NewError(
+		domain.KindValidation, "invalid_deck", "batch tickets can be voted with plain decks only",
+	)
+	// ErrInvalidTicketsNumber is returned when a batch has no tickets or more than MaxTickets.
+	ErrInvalidTicketsNumber = domain.NewError(
+		domain.KindValidation, "invalid_tickets", "batch should have 1 to 50 tickets",
+	)
+	// ErrEmptyTicketName is returned when the ticket name is not provided.
+	ErrEmptyTicketName = domain.NewError(domain.KindValidation, "invalid_ticket_name", "ticket name should be provided")
+	// ErrTicketNameTooLong is returned when the ticket name is longer than games.MaxNameLength.
+	ErrTicketNameTooLong = domain.NewError(
+		domain.KindValidation, "invalid_ticket_name", "ticket name should be at most 128 characters long",
+	)
+	// ErrInvalidDeadline is returned when the deadline is in the past or further than MaxVotingPeriod.
+	ErrInvalidDeadline = domain.NewError(
+		domain.KindValidation, "invalid_deadline", "deadline should be in the future within 30 days",
+	)
+)
@@ -0,0 +1,23 @@
+package batches
+
+import (
+	"context"
+	"time"
+
+	"planningpoker/internal/domain/teams"
+)
+
+// Repository is a repository contract to fetch/persist batches.
+type Repository interface {
+	ModifyExclusively(ctx context.Context, id string, cb func(batch *Batch) error) error
+	Get(ctx context.Context, id string) (*Batch, error)
+	Save(ctx context.Context, batch *Batch) error
+	GetBatchesByParticipantID(ctx context.Context, userID string) ([]Batch, error)
+	// GetOverdueBatchIDs returns IDs of batches with the deadline before now and tickets which are not revealed yet.
+	GetOverdueBatchIDs(ctx context.Context, now time.Time) ([]string, error)
+}
+
+// TeamRepository is a contract to fetch teams batches are published for.
+type TeamRepository interface {
+	Get(ctx context.Context, id string) (*teams.Team, error)
+}
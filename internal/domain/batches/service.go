@@ -0,0 +1,156 @@
+package batches
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"planningpoker/internal/domain"
+	"planningpoker/internal/domain/teams"
+)
+
+// Service is an asynchronous voting application service.
+type Service struct {
+	batchesRepo Repository
+	teamsRepo   TeamRepository
+	logger      *logrus.Entry
+}
+
+// NewService creates a new batches service instance.
+func NewService(br Repository, tr TeamRepository, logger *logrus.Entry) (*Service, error) {
+	if br == nil {
+		return nil, errors.New("batches repository should be provided")
+	}
+	if tr == nil {
+		return nil, errors.New("teams repository should be provided")
+	}
+	if logger == nil {
+		return nil, errors.New("logger should be provided")
+	}
+
+	return &Service{
+		batchesRepo: br,
+		teamsRepo:   tr,
+		logger:      logger,
+	}, nil
+}
+
+// Create publishes a new batch, all the team members become participants of a team batch.
+func (s *Service) Create(ctx context.Context, cmd CreateBatchCommand) (*Batch, error) {
+	participantIDs := make([]string, 0)
+	if cmd.TeamID != "" {
+		team, err := s.team(ctx, cmd.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		if !team.IsMember(cmd.UserID) {
+			return nil, teams.ErrNotAMember
+		}
+		for uid := range team.Members() {
+			participantIDs = append(participantIDs, uid)
+		}
+	}
+
+	batch := NewBatch(cmd, participantIDs)
+	if err := s.batchesRepo.Save(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// Get returns the batch, only participants can see it.
+func (s *Service) Get(ctx context.Context, id, userID string) (*Batch, error) {
+	batch, err := s.batchesRepo.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get batch: %w", err)
+	}
+	if batch == nil {
+		return nil, ErrBatchNotFound
+	}
+	if !batch.IsParticipant(userID) {
+		return nil, ErrNotAParticipant
+	}
+
+	return batch, nil
+}
+
+// ListByParticipant returns all batches the user participates in, the closest deadline goes first.
+func (s *Service) ListByParticipant(ctx context.Context, userID string) ([]Batch, error) {
+	list, err := s.batchesRepo.GetBatchesByParticipantID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].Deadline().Equal(list[j].Deadline()) {
+			return list[i].Deadline().Before(list[j].Deadline())
+		}
+		return list[i].ID() < list[j].ID()
+	})
+
+	return list, nil
+}
+
+// Join makes the user a batch participant, only team members can join a team batch.
+func (s *Service) Join(ctx context.Context, cmd JoinBatchCommand) error {
+	return s.batchesRepo.ModifyExclusively(ctx, cmd.BatchID, func(batch *Batch) error {
+		if batch.TeamID() != "" && !batch.IsParticipant(cmd.UserID) {
+			team, err := s.team(ctx, batch.TeamID())
+			if err != nil {
+				return err
+			}
+			if !team.IsMember(cmd.UserID) {
+				return ErrNotATeamMember
+			}
+		}
+
+		batch.Join(cmd)
+		return nil
+	})
+}
+
+// Vote votes the batch ticket.
+func (s *Service) Vote(ctx context.Context, cmd VoteCommand) error {
+	return s.batchesRepo.ModifyExclusively(ctx, cmd.BatchID, func(batch *Batch) error {
+		return batch.Vote(cmd)
+	})
+}
+
+// RevealOverdue reveals tickets of all batches which deadline has passed by now.
+// A batch which fails to be revealed is logged and left for the next call, the rest are revealed anyway.
+func (s *Service) RevealOverdue(ctx context.Context, now time.Time) error {
+	ids, err := s.batchesRepo.GetOverdueBatchIDs(ctx, now)
+	if err != nil {
+		return fmt.Errorf("overdue batches fetching: %w", err)
+	}
+
+	for _, id := range ids {
+		err := s.batchesRepo.ModifyExclusively(ctx, id, func(batch *Batch) error {
+			batch.RevealOverdue(now)
+			return nil
+		})
+		if err != nil {
+			// a broken batch should not keep the rest unrevealed, it is retried on the next check
+			s.logger.WithContext(ctx).WithError(err).WithField(domain.LogFieldBatchID, id).Error("unable to reveal the batch")
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) team(ctx context.Context, id string) (*teams.Team, error) {
+	team, err := s.teamsRepo.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get team: %w", err)
+	}
+	if team == nil {
+		return nil, teams.ErrTeamNotFound
+	}
+
+	return team, nil
+}
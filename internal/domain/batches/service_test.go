@@ -0,0 +1,227 @@
+package batches_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/batches"
+	"planningpoker/internal/domain/teams"
+	"planningpoker/test"
+)
+
+func TestNewService(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		batchesRepo batches.Repository
+		teamsRepo   batches.TeamRepository
+		logger      *logrus.Entry
+		expError    string
+	}{
+		"success": {
+			batchesRepo: &batchesRepoStub{},
+			teamsRepo:   teamsRepoStub{},
+			logger:      test.NewLogger(),
+		},
+		"fail on no batches repo": {
+			teamsRepo: teamsRepoStub{},
+			logger:    test.NewLogger(),
+			expError:  "batches repository should be provided",
+		},
+		"fail on no teams repo": {
+			batchesRepo: &batchesRepoStub{},
+			logger:      test.NewLogger(),
+			expError:    "teams repository should be provided",
+		},
+		"fail on no logger": {
+			batchesRepo: &batchesRepoStub{},
+			teamsRepo:   teamsRepoStub{},
+			expError:    "logger should be provided",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			srv, err := batches.NewService(tt.batchesRepo, tt.teamsRepo, tt.logger)
+
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				assert.Nil(t, srv)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, srv)
+			}
+		})
+	}
+}
+
+func TestService_CreateTeamBatch(t *testing.T) {
+	t.Parallel()
+
+	team := test.NewTeam(t, test.User1)
+	test.JoinTeam(t, team, test.User2)
+	repo := &batchesRepoStub{}
+	srv, err := batches.NewService(repo, teamsRepoStub{team: team}, test.NewLogger())
+	require.NoError(t, err)
+
+	cmd := newCreateCmd(t, test.User3)
+	cmd.TeamID = team.ID()
+	_, err = srv.Create(context.Background(), cmd)
+	assert.ErrorIs(t, err, teams.ErrNotAMember)
+
+	cmd.TeamID = "unknown"
+	_, err = srv.Create(context.Background(), cmd)
+	assert.ErrorIs(t, err, teams.ErrTeamNotFound)
+
+	cmd = newCreateCmd(t, test.User2)
+	cmd.TeamID = team.ID()
+	batch, err := srv.Create(context.Background(), cmd)
+	require.NoError(t, err)
+	assert.Equal(t, team.ID(), batch.TeamID())
+	assert.ElementsMatch(t, []string{test.User1, test.User2}, batch.ParticipantIDs())
+	assert.Same(t, batch, repo.batch)
+
+	// only team members can join a team batch
+	err = srv.Join(context.Background(), batches.JoinBatchCommand{BatchID: batch.ID(), UserID: test.User3})
+	assert.ErrorIs(t, err, batches.ErrNotATeamMember)
+	test.JoinTeam(t, team, test.User3)
+	require.NoError(t, srv.Join(context.Background(), batches.JoinBatchCommand{BatchID: batch.ID(), UserID: test.User3}))
+	assert.True(t, batch.IsParticipant(test.User3))
+}
+
+func TestService_Get(t *testing.T) {
+	t.Parallel()
+
+	batch := test.NewBatch(t, test.User1, "ticket")
+
+	testCases := map[string]struct {
+		batch    *batches.Batch
+		repoErr  error
+		userID   string
+		expError string
+	}{
+		"success":                 {batch: batch, userID: test.User1},
+		"fail on unknown batch":   {userID: test.User1, expError: "batch not found"},
+		"fail on repo error":      {repoErr: errors.New("failed"), userID: test.User1, expError: "get batch: failed"},
+		"fail on non participant": {batch: batch, userID: test.User2, expError: "user is not a batch participant"},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			srv, err := batches.NewService(&batchesRepoStub{batch: tt.batch, err: tt.repoErr}, teamsRepoStub{}, test.NewLogger())
+			require.NoError(t, err)
+
+			got, err := srv.Get(context.Background(), batch.ID(), tt.userID)
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, batch.ID(), got.ID())
+		})
+	}
+}
+
+func TestService_VoteAndRevealOverdue(t *testing.T) {
+	t.Parallel()
+
+	batch := test.NewBatch(t, test.User1, "first", "second")
+	repo := &batchesRepoStub{batch: batch}
+	srv, err := batches.NewService(repo, teamsRepoStub{}, test.NewLogger())
+	require.NoError(t, err)
+
+	require.NoError(t, srv.Join(context.Background(), batches.JoinBatchCommand{BatchID: batch.ID(), UserID: test.User2}))
+	require.NoError(t, srv.Vote(context.Background(), voteCmd(batch, batch.Tickets()[0], test.User2, "XS")))
+	assert.False(t, batch.Tickets()[0].IsRevealed())
+
+	require.NoError(t, srv.RevealOverdue(context.Background(), time.Now()))
+	assert.False(t, batch.IsClosed())
+
+	require.NoError(t, srv.RevealOverdue(context.Background(), batch.Deadline()))
+	assert.True(t, batch.IsClosed())
+
+	repo.err = errors.New("failed")
+	assert.EqualError(t, srv.RevealOverdue(context.Background(), time.Now()), "overdue batches fetching: failed")
+}
+
+func TestService_RevealOverdueSkipsFailingBatches(t *testing.T) {
+	t.Parallel()
+
+	batch := test.NewBatch(t, test.User1, "ticket")
+	repo := &batchesRepoStub{batch: batch, overdueIDs: []string{"broken", batch.ID()}}
+	srv, err := batches.NewService(repo, teamsRepoStub{}, test.NewLogger())
+	require.NoError(t, err)
+
+	require.NoError(t, srv.RevealOverdue(context.Background(), batch.Deadline()))
+	assert.True(t, batch.IsClosed())
+}
+
+func newCreateCmd(t *testing.T, userID string) batches.CreateBatchCommand {
+	cmd, err := batches.NewCreateBatchCommand("batch", userID, test.NewTestDeck(t),
+		[]batches.TicketData{{Name: "ticket"}}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	return *cmd
+}
+
+type batchesRepoStub struct {
+	batch *batches.Batch
+	// overdueIDs replace IDs of overdue batches when they are set.
+	overdueIDs []string
+	err        error
+}
+
+func (r *batchesRepoStub) ModifyExclusively(_ context.Context, id string, cb func(batch *batches.Batch) error) error {
+	if r.batch == nil || r.batch.ID() != id {
+		return batches.ErrBatchNotFound
+	}
+	return cb(r.batch)
+}
+
+func (r *batchesRepoStub) Get(context.Context, string) (*batches.Batch, error) {
+	return r.batch, r.err
+}
+
+func (r *batchesRepoStub) Save(_ context.Context, batch *batches.Batch) error {
+	r.batch = batch
+	return r.err
+}
+
+func (r *batchesRepoStub) GetBatchesByParticipantID(context.Context, string) ([]batches.Batch, error) {
+	if r.batch == nil {
+		return nil, r.err
+	}
+	return []batches.Batch{*r.batch}, r.err
+}
+
+func (r *batchesRepoStub) GetOverdueBatchIDs(_ context.Context, now time.Time) ([]string, error) {
+	if r.overdueIDs != nil {
+		return r.overdueIDs, r.err
+	}
+	if r.batch == nil || !r.batch.IsOverdue(now) {
+		return nil, r.err
+	}
+	return []string{r.batch.ID()}, r.err
+}
+
+type teamsRepoStub struct {
+	team *teams.Team
+}
+
+func (r teamsRepoStub) Get(_ context.Context, id string) (*teams.Team, error) {
+	if r.team == nil || r.team.ID() != id {
+		return nil, nil
+	}
+	return r.team, nil
+}
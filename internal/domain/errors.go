@@ -0,0 +1,46 @@
+package domain
+
+// ErrorKind is a category of a domain error, infra layers use it to choose a protocol specific status.
+type ErrorKind int
+
+const (
+	// KindNotFound means that a requested aggregate does not exist.
+	KindNotFound ErrorKind = iota + 1
+	// KindForbidden means that the user is not allowed to perform the action.
+	KindForbidden
+	// KindConflict means that the action can not be performed in the current aggregate state.
+	KindConflict
+	// KindValidation means that the provided data is not valid.
+	KindValidation
+)
+
+// Error is a domain error with a stable machine-readable code.
+type Error struct {
+	kind    ErrorKind
+	code    string
+	message string
+}
+
+// NewError creates a new domain error, it is intended to be used for package level sentinel errors.
+func NewError(kind ErrorKind, code, message string) *Error {
+	return &Error{
+		kind:    kind,
+		code:    code,
+		message: message,
+	}
+}
+
+// Error returns a human-readable error message.
+func (e *Error) Error() string {
+	return e.message
+}
+
+// Kind returns the error category.
+func (e *Error) Kind() ErrorKind {
+	return e.kind
+}
+
+// Code returns the error code, e.g. "not_a_player".
+func (e *Error) Code() string {
+	return e.code
+}
@@ -24,6 +24,12 @@ func (b *DomainEventBuilder) ForAggregate(id string) *DomainEventBuilder {
 	return b
 }
 
+// WithPayload sets the event specific data.
+func (b *DomainEventBuilder) WithPayload(payload interface{}) *DomainEventBuilder {
+	b.e.payload = payload
+	return b
+}
+
 // Build creates a domain event.
 func (b *DomainEventBuilder) Build() DomainEvent {
 	return b.e
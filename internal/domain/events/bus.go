@@ -1,10 +1,13 @@
 package events
 
+import "context"
+
 // Consumer is a generic domain event consumer function.
-type Consumer func(DomainEvent)
+// The context carries the publisher context, e.g. a trace, but not its cancellation.
+type Consumer func(ctx context.Context, e DomainEvent)
 
 // EventBus represents a domain events bus generic contract.
 type EventBus interface {
-	Publish(event DomainEvent) error
+	Publish(ctx context.Context, event DomainEvent) error
 	Subscribe(consumer Consumer, eventTypes ...string)
 }
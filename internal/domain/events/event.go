@@ -8,6 +8,24 @@ const (
 
 	// EventTypeGameUpdated is a domain event that game state has changed.
 	EventTypeGameUpdated = "game:updated"
+
+	// EventTypeGameDeleted is a domain event that the game was deleted for good.
+	EventTypeGameDeleted = "game:deleted"
+
+	// EventTypeRoundRevealed is a domain event that game cards were revealed, the payload is the round.
+	EventTypeRoundRevealed = "round:revealed"
+
+	// EventTypeTeamUpdated is a domain event that team members or settings have changed.
+	EventTypeTeamUpdated = "team:updated"
+
+	// EventTypeRoomUpdated is a domain event that room settings, members or sessions have changed.
+	EventTypeRoomUpdated = "room:updated"
+
+	// EventTypeRoomSessionForgotten is a domain event that the room dropped an old session, the payload is the session.
+	EventTypeRoomSessionForgotten = "room:session_forgotten"
+
+	// EventTypeBatchUpdated is a domain event that batch participants or votes have changed.
+	EventTypeBatchUpdated = "batch:updated"
 )
 
 // DomainEvent is a generic domain event.
@@ -15,6 +33,10 @@ type DomainEvent struct {
 	eventType   string
 	aggregateID string
 	occurredAt  time.Time
+	// payload carries event specific data, consumers assert it to a type known for the event type.
+	payload interface{}
+	// metadata carries cross-cutting context of the event, e.g. a trace context, so consumers can continue it.
+	metadata map[string]string
 }
 
 // EventType returns the domain event type.
@@ -26,3 +48,33 @@ func (e DomainEvent) EventType() string {
 func (e DomainEvent) AggregateID() string {
 	return e.aggregateID
 }
+
+// OccurredAt returns the time when the event occurred.
+func (e DomainEvent) OccurredAt() time.Time {
+	return e.occurredAt
+}
+
+// Payload returns the event specific data, nil if the event has no data.
+func (e DomainEvent) Payload() interface{} {
+	return e.payload
+}
+
+// Metadata returns a copy of the event metadata.
+func (e DomainEvent) Metadata() map[string]string {
+	md := make(map[string]string, len(e.metadata))
+	for k, v := range e.metadata {
+		md[k] = v
+	}
+
+	return md
+}
+
+// WithMetadata returns a copy of the event with the provided metadata.
+func (e DomainEvent) WithMetadata(md map[string]string) DomainEvent {
+	e.metadata = make(map[string]string, len(md))
+	for k, v := range md {
+		e.metadata[k] = v
+	}
+
+	return e
+}
@@ -0,0 +1,41 @@
+package games
+
+const (
+	// ActionCreate is an action of the game creation.
+	ActionCreate = "create"
+	// ActionJoin is an action of a new player joining the game.
+	ActionJoin = "join"
+	// ActionLeave is an action of a player leaving the game.
+	ActionLeave = "leave"
+	// ActionUpdate is an action of the game data update.
+	ActionUpdate = "update"
+	// ActionVote is an action of a player voting, the card is not recorded since it is hidden until reveal.
+	ActionVote = "vote"
+	// ActionUnVote is an action of a player removing the vote.
+	ActionUnVote = "unvote"
+	// ActionReveal is an action of revealing the cards.
+	ActionReveal = "reveal"
+	// ActionRestart is an action of the game restart.
+	ActionRestart = "restart"
+	// ActionRevote is an action of voting on the same ticket again, the revealed votes are kept as an attempt.
+	ActionRevote = "revote"
+	// ActionRearrange is an action of the seats rearrangement.
+	ActionRearrange = "rearrange"
+	// ActionChangePasscode is an action of the join passcode rotation, the passcode itself is never recorded.
+	ActionChangePasscode = "change_passcode"
+	// ActionArchive is an action of the game archiving, it has no actor since it is done by the system.
+	ActionArchive = "archive"
+)
+
+// Action describes a change done in the game, it is the payload of the game updated event.
+// Updated events without an action are technical ones, e.g. a player name change.
+type Action struct {
+	Name string
+	// ActorID is an ID of the user who performed the action, it is empty for system actions.
+	ActorID string
+	// ActorHandle is a public handle of the actor at the time of the action.
+	ActorHandle string
+	// Before and After contain changed values, they are set for the actions changing game data only.
+	Before map[string]string
+	After  map[string]string
+}
@@ -1,6 +1,24 @@
 package games
 
-import "errors"
+import "strconv"
+
+// AbstainCard is a card of a player who can not estimate the ticket, such votes are not counted.
+const AbstainCard Card = "?"
+
+const (
+	// MaxDimensions is the maximal number of dimensions in a deck.
+	MaxDimensions = 5
+	// MaxDimensionNameLength is the maximal dimension name length in characters.
+	MaxDimensionNameLength = 32
+	// FormulaSum combines dimension estimates by summing them up.
+	FormulaSum = "sum"
+	// FormulaProduct combines dimension estimates by multiplying them.
+	FormulaProduct = "product"
+	// FormulaMax takes the highest dimension estimate.
+	FormulaMax = "max"
+	// FormulaAverage takes the average of dimension estimates.
+	FormulaAverage = "average"
+)
 
 // Card represents a playing card.
 type Card string
@@ -8,11 +26,11 @@ type Card string
 // NewCard creates a card with specific type.
 func NewCard(typ string) (*Card, error) {
 	if typ == "" {
-		return nil, errors.New("card type should be provided")
+		return nil, ErrEmptyCardType
 	}
 
 	if len(typ) > 3 {
-		return nil, errors.New("card type should be 1-3 chars long")
+		return nil, ErrCardTypeTooLong
 	}
 
 	card := Card(typ)
@@ -30,19 +48,67 @@ func (c Card) Type() string {
 	return string(c)
 }
 
-// CardsDeck represents a deck of cards.
-type CardsDeck struct {
+// Value parses numeric cards, e.g. "5", "0.5" or "½", false is returned for other cards.
+func (c Card) Value() (float64, bool) {
+	if c == "½" {
+		return 0.5, true
+	}
+
+	v, err := strconv.ParseFloat(string(c), 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return v, true
+}
+
+// Dimension is a named estimation axis with its own cards, e.g. complexity or uncertainty.
+type Dimension struct {
 	name  string
 	cards []Card
 }
 
+// NewDimension creates a new named dimension.
+func NewDimension(name string, cards []Card) (*Dimension, error) {
+	if name == "" || len([]rune(name)) > MaxDimensionNameLength {
+		return nil, ErrInvalidDimensionName
+	}
+	if len(cards) == 0 {
+		return nil, ErrEmptyDeck
+	}
+
+	return &Dimension{
+		name:  name,
+		cards: cards,
+	}, nil
+}
+
+// Name returns the dimension name.
+func (d Dimension) Name() string {
+	return d.name
+}
+
+// Cards return all cards of the dimension.
+func (d Dimension) Cards() []Card {
+	return d.cards
+}
+
+// CardsDeck represents a deck of cards.
+// A deck either has plain cards or several dimensions, each voted with its own cards.
+type CardsDeck struct {
+	name       string
+	cards      []Card
+	dimensions []Dimension
+	formula    string
+}
+
 // NewCardsDeck creates a new named deck of cards.
 func NewCardsDeck(name string, cards []Card) (*CardsDeck, error) {
 	if name == "" {
-		return nil, errors.New("name should be provided")
+		return nil, ErrEmptyDeckName
 	}
 	if len(cards) == 0 {
-		return nil, errors.New("cards should be provided")
+		return nil, ErrEmptyDeck
 	}
 
 	return &CardsDeck{
@@ -51,6 +117,36 @@ func NewCardsDeck(name string, cards []Card) (*CardsDeck, error) {
 	}, nil
 }
 
+// NewDimensionalCardsDeck creates a new named deck of dimensions, the formula combining them is optional.
+func NewDimensionalCardsDeck(name string, dimensions []Dimension, formula string) (*CardsDeck, error) {
+	if name == "" {
+		return nil, ErrEmptyDeckName
+	}
+	if len(dimensions) == 0 || len(dimensions) > MaxDimensions {
+		return nil, ErrInvalidDimensions
+	}
+
+	names := make(map[string]bool, len(dimensions))
+	for _, d := range dimensions {
+		if names[d.name] {
+			return nil, ErrInvalidDimensions
+		}
+		names[d.name] = true
+	}
+
+	switch formula {
+	case "", FormulaSum, FormulaProduct, FormulaMax, FormulaAverage:
+	default:
+		return nil, ErrUnknownFormula
+	}
+
+	return &CardsDeck{
+		name:       name,
+		dimensions: dimensions,
+		formula:    formula,
+	}, nil
+}
+
 // Name returns cards deck name.
 func (d CardsDeck) Name() string {
 	return d.name
@@ -61,9 +157,44 @@ func (d CardsDeck) Cards() []Card {
 	return d.cards
 }
 
+// IsInDeck checks if specific card belongs to the dimension.
+func (d Dimension) IsInDeck(card Card) bool {
+	return isInCards(d.cards, card)
+}
+
+// Dimensions returns deck dimensions, it is empty for plain decks.
+func (d CardsDeck) Dimensions() []Dimension {
+	return d.dimensions
+}
+
+// IsDimensional returns true if players vote a card per dimension.
+func (d CardsDeck) IsDimensional() bool {
+	return len(d.dimensions) > 0
+}
+
+// Formula returns a formula combining dimension estimates, it is empty when they are not combined.
+func (d CardsDeck) Formula() string {
+	return d.formula
+}
+
+// Dimension returns the dimension by its name.
+func (d CardsDeck) Dimension(name string) (Dimension, bool) {
+	for _, dim := range d.dimensions {
+		if dim.name == name {
+			return dim, true
+		}
+	}
+
+	return Dimension{}, false
+}
+
 // IsInDeck checks if specific card exists in the deck.
 func (d CardsDeck) IsInDeck(card Card) bool {
-	for _, c := range d.cards {
+	return isInCards(d.cards, card)
+}
+
+func isInCards(cards []Card, card Card) bool {
+	for _, c := range cards {
 		if c == card {
 			return true
 		}
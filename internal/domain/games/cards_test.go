@@ -1,6 +1,7 @@
 package games_test
 
 import (
+	"strings"
 	"testing"
 
 	"planningpoker/internal/domain/games"
@@ -97,6 +98,86 @@ func TestNewCardsDeck(t *testing.T) {
 	}
 }
 
+func TestCard_Value(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		card     games.Card
+		expValue float64
+		expOK    bool
+	}{
+		"integer":     {card: "5", expValue: 5, expOK: true},
+		"decimal":     {card: "0.5", expValue: 0.5, expOK: true},
+		"half":        {card: "½", expValue: 0.5, expOK: true},
+		"non numeric": {card: "XL"},
+		"abstain":     {card: games.AbstainCard},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			v, ok := tt.card.Value()
+			assert.Equal(t, tt.expOK, ok)
+			assert.Equal(t, tt.expValue, v)
+		})
+	}
+}
+
+func TestNewDimensionalCardsDeck(t *testing.T) {
+	t.Parallel()
+
+	complexity, err := games.NewDimension("complexity", []games.Card{"1", "2"})
+	require.NoError(t, err)
+	effort, err := games.NewDimension("effort", []games.Card{"S", "M"})
+	require.NoError(t, err)
+
+	_, err = games.NewDimension("", []games.Card{"1"})
+	assert.ErrorIs(t, err, games.ErrInvalidDimensionName)
+	_, err = games.NewDimension(strings.Repeat("a", games.MaxDimensionNameLength+1), []games.Card{"1"})
+	assert.ErrorIs(t, err, games.ErrInvalidDimensionName)
+	_, err = games.NewDimension("effort", nil)
+	assert.ErrorIs(t, err, games.ErrEmptyDeck)
+
+	testCases := map[string]struct {
+		name       string
+		dimensions []games.Dimension
+		formula    string
+		expErr     error
+	}{
+		"success":                 {name: "axes", dimensions: []games.Dimension{*complexity, *effort}, formula: games.FormulaSum},
+		"success without formula": {name: "axes", dimensions: []games.Dimension{*complexity}},
+		"fail on empty name":      {dimensions: []games.Dimension{*complexity}, expErr: games.ErrEmptyDeckName},
+		"fail on no dimensions":   {name: "axes", expErr: games.ErrInvalidDimensions},
+		"fail on same dimensions": {name: "axes", dimensions: []games.Dimension{*effort, *effort}, expErr: games.ErrInvalidDimensions},
+		"fail on unknown formula": {name: "axes", dimensions: []games.Dimension{*effort}, formula: "pow", expErr: games.ErrUnknownFormula},
+		"fail on many dimensions": {
+			name:       "axes",
+			dimensions: []games.Dimension{*complexity, *effort, *complexity, *effort, *complexity, *effort},
+			expErr:     games.ErrInvalidDimensions,
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			deck, err := games.NewDimensionalCardsDeck(tt.name, tt.dimensions, tt.formula)
+			if tt.expErr != nil {
+				assert.ErrorIs(t, err, tt.expErr)
+				assert.Nil(t, deck)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, deck.IsDimensional())
+			assert.Equal(t, tt.dimensions, deck.Dimensions())
+			assert.Equal(t, tt.formula, deck.Formula())
+			assert.Empty(t, deck.Cards())
+		})
+	}
+}
+
 func TestCardsDeck_IsInDeck(t *testing.T) {
 	t.Parallel()
 	card1, err := games.NewCard("XS")
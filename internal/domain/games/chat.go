@@ -0,0 +1,111 @@
+package games
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxChatMessageLength is the maximal chat message length in characters.
+const MaxChatMessageLength = 280
+
+// Reactions are emojis players can react with, anything else is rejected.
+var Reactions = []string{"👍", "👎", "🎉", "🤔", "😂", "😮", "☕", "🔥"}
+
+// ChatMessage is a chat message or an emoji reaction of a player, it is not persisted with the game.
+type ChatMessage struct {
+	// SenderHandle and TargetHandle are player handles, so user IDs are never shared with other players.
+	SenderHandle string
+	Text         string
+	Reaction     string
+	// TargetHandle is an optional handle of the player a reaction is addressed to.
+	TargetHandle string
+	SentAt       time.Time
+}
+
+// ChatCommand is a command to send a chat message or an emoji reaction, exactly one of them is set.
+type ChatCommand struct {
+	GameID   string
+	UserID   string
+	Text     string
+	Reaction string
+	// TargetHandle is an optional handle of the player a reaction is addressed to.
+	TargetHandle string
+}
+
+// NewChatMessageCommand creates a new command instance, the text is trimmed.
+func NewChatMessageCommand(gameID, userID, text string) (*ChatCommand, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > MaxChatMessageLength {
+		return nil, ErrInvalidChatMessage
+	}
+
+	return &ChatCommand{
+		GameID: gameID,
+		UserID: userID,
+		Text:   text,
+	}, nil
+}
+
+// NewReactionCommand creates a new command instance, an empty target handle addresses the whole table.
+func NewReactionCommand(gameID, userID, reaction, targetHandle string) (*ChatCommand, error) {
+	if !isReaction(reaction) {
+		return nil, ErrUnknownReaction
+	}
+
+	return &ChatCommand{
+		GameID:       gameID,
+		UserID:       userID,
+		Reaction:     reaction,
+		TargetHandle: targetHandle,
+	}, nil
+}
+
+// Chat creates a message of the player, chatting does not change the game and does not prolong its activity.
+func (g Game) Chat(cmd ChatCommand) (*ChatMessage, error) {
+	p, ok := g.players[cmd.UserID]
+	if !ok {
+		return nil, ErrNotAPlayer
+	}
+
+	if g.state == GameStateArchived {
+		return nil, ErrGameArchived
+	}
+
+	if g.chatDisabled {
+		return nil, ErrChatDisabled
+	}
+
+	if cmd.TargetHandle != "" && !g.hasHandle(cmd.TargetHandle) {
+		return nil, ErrUnknownChatTarget
+	}
+
+	return &ChatMessage{
+		SenderHandle: p.Handle,
+		Text:         cmd.Text,
+		Reaction:     cmd.Reaction,
+		TargetHandle: cmd.TargetHandle,
+		SentAt:       time.Now(),
+	}, nil
+}
+
+// hasHandle checks if a player with the handle is in the game.
+func (g Game) hasHandle(handle string) bool {
+	for _, p := range g.players {
+		if p.Handle == handle {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isReaction(reaction string) bool {
+	for _, r := range Reactions {
+		if r == reaction {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,5 +1,24 @@
 package games
 
+import (
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// MaxNameLength is the maximal game name length in characters.
+	MaxNameLength = 128
+	// MaxTicketURLLength is the maximal ticket URL length in characters.
+	MaxTicketURLLength = 2048
+	// MinPasscodeLength is the minimal join passcode length in bytes.
+	MinPasscodeLength = 4
+	// MaxPasscodeLength is the maximal join passcode length in bytes, longer passcodes can not be hashed.
+	MaxPasscodeLength = 72
+)
+
 // CreateGameCommand is a game creation command.
 type CreateGameCommand struct {
 	UserID            string
@@ -7,10 +26,25 @@ type CreateGameCommand struct {
 	TicketURL         string
 	CardsDeck         CardsDeck
 	EveryoneCanReveal bool
+	// PasscodeHash protects the game from joining by strangers, the game is not protected when it is empty.
+	PasscodeHash []byte
+	// TeamID is an ID of the team the game is played by, it is empty for games outside of teams.
+	TeamID string
+	// RevoteThreshold is a spread in deck cards a re-vote is suggested above, zero disables suggestions.
+	RevoteThreshold int
+	// Anonymous hides who voted which card, players still see who has voted.
+	Anonymous bool
+	// ChatDisabled turns off chat messages and emoji reactions of the game.
+	ChatDisabled bool
 }
 
-// NewCreateGameCommand creates a new command instance.
+// NewCreateGameCommand creates a new command instance, the name and the ticket URL are trimmed.
 func NewCreateGameCommand(name, ticketURL, userID string, deck CardsDeck, everyoneCanReveal bool) (*CreateGameCommand, error) {
+	name, ticketURL = strings.TrimSpace(name), strings.TrimSpace(ticketURL)
+	if err := validateGameData(name, ticketURL); err != nil {
+		return nil, err
+	}
+
 	return &CreateGameCommand{
 		UserID:            userID,
 		Name:              name,
@@ -20,6 +54,54 @@ func NewCreateGameCommand(name, ticketURL, userID string, deck CardsDeck, everyo
 	}, nil
 }
 
+// ProtectWith requires the passcode from new players joining the game.
+func (c *CreateGameCommand) ProtectWith(passcode string) error {
+	hash, err := hashPasscode(passcode)
+	if err != nil {
+		return err
+	}
+	c.PasscodeHash = hash
+
+	return nil
+}
+
+// SuggestRevoteAbove makes the game suggest a re-vote when the revealed votes spread is wider than the threshold.
+func (c *CreateGameCommand) SuggestRevoteAbove(threshold int) error {
+	if threshold < 0 {
+		return ErrInvalidRevoteThreshold
+	}
+	c.RevoteThreshold = threshold
+
+	return nil
+}
+
+// ChangePasscodeCommand is a command to rotate or remove the game join passcode.
+type ChangePasscodeCommand struct {
+	GameID string
+	UserID string
+	// PasscodeHash is a hash of the new passcode, empty hash removes the protection.
+	PasscodeHash []byte
+}
+
+// NewChangePasscodeCommand creates a new command instance, an empty passcode removes the protection.
+func NewChangePasscodeCommand(gameID, userID, passcode string) (*ChangePasscodeCommand, error) {
+	cmd := &ChangePasscodeCommand{
+		GameID: gameID,
+		UserID: userID,
+	}
+	if passcode == "" {
+		return cmd, nil
+	}
+
+	hash, err := hashPasscode(passcode)
+	if err != nil {
+		return nil, err
+	}
+	cmd.PasscodeHash = hash
+
+	return cmd, nil
+}
+
 // UpdateGameCommand is a game update command.
 type UpdateGameCommand struct {
 	GameID    string
@@ -28,8 +110,13 @@ type UpdateGameCommand struct {
 	TicketURL string
 }
 
-// NewUpdateGameCommand creates a new command instance.
+// NewUpdateGameCommand creates a new command instance, the name and the ticket URL are trimmed.
 func NewUpdateGameCommand(id string, name string, ticketURL string, userID string) (*UpdateGameCommand, error) {
+	name, ticketURL = strings.TrimSpace(name), strings.TrimSpace(ticketURL)
+	if err := validateGameData(name, ticketURL); err != nil {
+		return nil, err
+	}
+
 	return &UpdateGameCommand{
 		GameID:    id,
 		UserID:    userID,
@@ -42,6 +129,8 @@ func NewUpdateGameCommand(id string, name string, ticketURL string, userID strin
 type JoinGameCommand struct {
 	GameID string
 	UserID string
+	// Passcode is required from new players of protected games only.
+	Passcode string
 }
 
 // NewJoinGameCommand creates a new command instance.
@@ -52,16 +141,40 @@ func NewJoinGameCommand(gameID, userID string) (*JoinGameCommand, error) {
 	}, nil
 }
 
+// RearrangeSeatsCommand is a command to change players seats at the table.
+type RearrangeSeatsCommand struct {
+	GameID string
+	UserID string
+	// Order contains current players positions at the table, the index of each position is the new seat.
+	Order []int
+}
+
+// NewRearrangeSeatsCommand creates a new command instance.
+func NewRearrangeSeatsCommand(gameID, userID string, order []int) (*RearrangeSeatsCommand, error) {
+	return &RearrangeSeatsCommand{
+		GameID: gameID,
+		UserID: userID,
+		Order:  order,
+	}, nil
+}
+
 // VoteCommand is a user voting command.
 type VoteCommand struct {
-	GameID     string
-	UserID     string
-	Vote       Card
+	GameID string
+	UserID string
+	Vote   Card
+	// Cards are cards voted per dimension name, they are used instead of Vote in dimensional decks.
+	Cards      map[string]Card
 	Confidence string
 }
 
-// NewVoteCommand creates a new command instance.
+// NewVoteCommand creates a new command instance, an empty confidence means the normal one.
 func NewVoteCommand(gameID, userID string, card Card, confidence string) (*VoteCommand, error) {
+	confidence, err := normalizeConfidence(confidence)
+	if err != nil {
+		return nil, err
+	}
+
 	return &VoteCommand{
 		GameID:     gameID,
 		UserID:     userID,
@@ -70,6 +183,25 @@ func NewVoteCommand(gameID, userID string, card Card, confidence string) (*VoteC
 	}, nil
 }
 
+// NewDimensionalVoteCommand creates a new command instance with a card per dimension name.
+func NewDimensionalVoteCommand(gameID, userID string, cards map[string]Card, confidence string) (*VoteCommand, error) {
+	if len(cards) == 0 {
+		return nil, ErrDimensionsNotVoted
+	}
+
+	confidence, err := normalizeConfidence(confidence)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VoteCommand{
+		GameID:     gameID,
+		UserID:     userID,
+		Cards:      cards,
+		Confidence: confidence,
+	}, nil
+}
+
 // UnVoteCommand is a user un-voting command.
 type UnVoteCommand struct {
 	GameID string
@@ -98,6 +230,20 @@ func NewRestartGameCommand(gameID, userID string) (*RestartGameCommand, error) {
 	}, nil
 }
 
+// RevoteCommand is a command to vote on the same ticket again.
+type RevoteCommand struct {
+	GameID string
+	UserID string
+}
+
+// NewRevoteCommand creates a new command instance.
+func NewRevoteCommand(gameID, userID string) (*RevoteCommand, error) {
+	return &RevoteCommand{
+		GameID: gameID,
+		UserID: userID,
+	}, nil
+}
+
 // RevealCardsCommand is a stop-game-and-reveal-cards command.
 type RevealCardsCommand struct {
 	GameID string
@@ -125,3 +271,42 @@ func NewLeaveGameCommand(gameID, userID string) (*LeaveGameCommand, error) {
 		UserID: userID,
 	}, nil
 }
+
+func validateGameData(name, ticketURL string) error {
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return ErrGameNameTooLong
+	}
+	if ticketURL == "" {
+		return nil
+	}
+	if utf8.RuneCountInString(ticketURL) > MaxTicketURLLength {
+		return ErrTicketURLTooLong
+	}
+	// the URL is rendered as a link, so other schemes, e.g. javascript:, are not allowed.
+	u, err := url.Parse(ticketURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidTicketURL
+	}
+
+	return nil
+}
+
+// normalizeConfidence validates the confidence level, an empty confidence means the normal one.
+func normalizeConfidence(confidence string) (string, error) {
+	switch confidence {
+	case "":
+		return ConfidenceNormal, nil
+	case ConfidenceLow, ConfidenceNormal, ConfidenceHigh:
+		return confidence, nil
+	default:
+		return "", ErrInvalidConfidence
+	}
+}
+
+func hashPasscode(passcode string) ([]byte, error) {
+	if len(passcode) < MinPasscodeLength || len(passcode) > MaxPasscodeLength {
+		return nil, ErrInvalidPasscode
+	}
+
+	return bcrypt.GenerateFromPassword([]byte(passcode), bcrypt.DefaultCost)
+}
@@ -0,0 +1,235 @@
+package games_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/games"
+	"planningpoker/test"
+)
+
+func TestNewCreateGameCommand(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		name         string
+		ticketURL    string
+		expName      string
+		expTicketURL string
+		expError     string
+	}{
+		"success": {
+			name:         "Sprint 1",
+			ticketURL:    "https://tracker.example.com/issues/1",
+			expName:      "Sprint 1",
+			expTicketURL: "https://tracker.example.com/issues/1",
+		},
+		"success on trimmed data": {
+			name:         "  Sprint 1\t",
+			ticketURL:    " http://tracker.example.com/1 ",
+			expName:      "Sprint 1",
+			expTicketURL: "http://tracker.example.com/1",
+		},
+		"success on no ticket URL": {
+			name:    "Sprint 1",
+			expName: "Sprint 1",
+		},
+		"fail on too long name": {
+			name:     strings.Repeat("a", games.MaxNameLength+1),
+			expError: "game name should be at most 128 characters long",
+		},
+		"fail on too long ticket URL": {
+			ticketURL: "https://example.com/" + strings.Repeat("a", games.MaxTicketURLLength),
+			expError:  "ticket URL should be at most 2048 characters long",
+		},
+		"fail on javascript ticket URL": {
+			ticketURL: "javascript:alert(1)",
+			expError:  "ticket URL should be an absolute http or https URL",
+		},
+		"fail on relative ticket URL": {
+			ticketURL: "/issues/1",
+			expError:  "ticket URL should be an absolute http or https URL",
+		},
+		"fail on malformed ticket URL": {
+			ticketURL: "http://[::1",
+			expError:  "ticket URL should be an absolute http or https URL",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, err := games.NewCreateGameCommand(tt.name, tt.ticketURL, test.User1, test.NewTestDeck(t), false)
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				assert.Nil(t, cmd)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expName, cmd.Name)
+			assert.Equal(t, tt.expTicketURL, cmd.TicketURL)
+		})
+	}
+}
+
+func TestNewUpdateGameCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd, err := games.NewUpdateGameCommand("id", " name ", " https://example.com ", test.User1)
+	require.NoError(t, err)
+	assert.Equal(t, "name", cmd.Name)
+	assert.Equal(t, "https://example.com", cmd.TicketURL)
+
+	_, err = games.NewUpdateGameCommand("id", "name", "ftp://example.com", test.User1)
+	assert.ErrorIs(t, err, games.ErrInvalidTicketURL)
+}
+
+func TestCreateGameCommand_ProtectWith(t *testing.T) {
+	t.Parallel()
+
+	cmd, err := games.NewCreateGameCommand("name", "", test.User1, test.NewTestDeck(t), false)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, cmd.ProtectWith("abc"), games.ErrInvalidPasscode)
+	assert.ErrorIs(t, cmd.ProtectWith(strings.Repeat("a", games.MaxPasscodeLength+1)), games.ErrInvalidPasscode)
+	assert.Empty(t, cmd.PasscodeHash)
+
+	require.NoError(t, cmd.ProtectWith("secret"))
+	assert.NotEmpty(t, cmd.PasscodeHash)
+	assert.NotContains(t, string(cmd.PasscodeHash), "secret")
+}
+
+func TestCreateGameCommand_SuggestRevoteAbove(t *testing.T) {
+	t.Parallel()
+
+	cmd, err := games.NewCreateGameCommand("name", "", test.User1, test.NewTestDeck(t), false)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, cmd.SuggestRevoteAbove(-1), games.ErrInvalidRevoteThreshold)
+	assert.Zero(t, cmd.RevoteThreshold)
+
+	require.NoError(t, cmd.SuggestRevoteAbove(2))
+	assert.Equal(t, 2, cmd.RevoteThreshold)
+}
+
+func TestNewChangePasscodeCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd, err := games.NewChangePasscodeCommand("id", test.User1, "")
+	require.NoError(t, err)
+	assert.Empty(t, cmd.PasscodeHash)
+
+	cmd, err = games.NewChangePasscodeCommand("id", test.User1, "secret")
+	require.NoError(t, err)
+	assert.NotEmpty(t, cmd.PasscodeHash)
+
+	_, err = games.NewChangePasscodeCommand("id", test.User1, "abc")
+	assert.EqualError(t, err, "passcode should be 4 to 72 bytes long")
+}
+
+func TestNewDimensionalVoteCommand(t *testing.T) {
+	t.Parallel()
+
+	cards := map[string]games.Card{"complexity": "1"}
+	cmd, err := games.NewDimensionalVoteCommand("id", test.User1, cards, "")
+	require.NoError(t, err)
+	assert.Equal(t, cards, cmd.Cards)
+	assert.Equal(t, games.ConfidenceNormal, cmd.Confidence)
+
+	_, err = games.NewDimensionalVoteCommand("id", test.User1, nil, "")
+	assert.ErrorIs(t, err, games.ErrDimensionsNotVoted)
+	_, err = games.NewDimensionalVoteCommand("id", test.User1, cards, "very high")
+	assert.ErrorIs(t, err, games.ErrInvalidConfidence)
+}
+
+func TestNewVoteCommand(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		confidence    string
+		expConfidence string
+		expError      string
+	}{
+		"success on low":    {confidence: games.ConfidenceLow, expConfidence: games.ConfidenceLow},
+		"success on normal": {confidence: games.ConfidenceNormal, expConfidence: games.ConfidenceNormal},
+		"success on high":   {confidence: games.ConfidenceHigh, expConfidence: games.ConfidenceHigh},
+		"success on empty":  {expConfidence: games.ConfidenceNormal},
+		"fail on unknown": {
+			confidence: "very high",
+			expError:   "confidence should be one of: low, normal, high",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, err := games.NewVoteCommand("id", test.User1, "XS", tt.confidence)
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				assert.Nil(t, cmd)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expConfidence, cmd.Confidence)
+		})
+	}
+}
+
+func TestNewChatMessageCommand(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		text     string
+		expText  string
+		expError string
+	}{
+		"success":            {text: "hello", expText: "hello"},
+		"success on trimmed": {text: "  hello \n", expText: "hello"},
+		"success on max length in characters": {
+			text:    strings.Repeat("я", games.MaxChatMessageLength),
+			expText: strings.Repeat("я", games.MaxChatMessageLength),
+		},
+		"fail on empty": {text: " ", expError: "chat message should be 1 to 280 characters long"},
+		"fail on too long": {
+			text:     strings.Repeat("a", games.MaxChatMessageLength+1),
+			expError: "chat message should be 1 to 280 characters long",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			cmd, err := games.NewChatMessageCommand("id", test.User1, tt.text)
+
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expText, cmd.Text)
+			assert.Empty(t, cmd.Reaction)
+		})
+	}
+}
+
+func TestNewReactionCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd, err := games.NewReactionCommand("id", test.User1, games.Reactions[0], "handle")
+	require.NoError(t, err)
+	assert.Equal(t, games.Reactions[0], cmd.Reaction)
+	assert.Equal(t, "handle", cmd.TargetHandle)
+
+	_, err = games.NewReactionCommand("id", test.User1, "<script>", "")
+	assert.ErrorIs(t, err, games.ErrUnknownReaction)
+}
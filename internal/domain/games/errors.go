@@ -0,0 +1,96 @@
+package games
+
+import "planningpoker/internal/domain"
+
+var (
+	// ErrGameNotFound is returned when the game does not exist.
+	ErrGameNotFound = domain.NewError(domain.KindNotFound, "game_not_found", "game not found")
+	// ErrNotAPlayer is returned when the user tries to act in a game they did not join.
+	ErrNotAPlayer = domain.NewError(domain.KindForbidden, "not_a_player", "user is not a player")
+	// ErrNotAFacilitator is returned when the action is allowed for the facilitator only.
+	ErrNotAFacilitator = domain.NewError(domain.KindForbidden, "not_a_facilitator", "user is not a facilitator")
+	// ErrCanNotReveal is returned when the player has no permission to reveal cards.
+	ErrCanNotReveal = domain.NewError(domain.KindForbidden, "reveal_not_allowed", "user can not reveal cards")
+	// ErrVoteOnFinishedGame is returned on voting after cards were revealed.
+	ErrVoteOnFinishedGame = domain.NewError(domain.KindConflict, "game_finished", "can not vote on ended game")
+	// ErrUnVoteOnFinishedGame is returned on un-voting after cards were revealed.
+	ErrUnVoteOnFinishedGame = domain.NewError(domain.KindConflict, "game_finished", "can not un-vote on ended game")
+	// ErrRevoteBeforeReveal is returned on re-voting before cards were revealed.
+	ErrRevoteBeforeReveal = domain.NewError(domain.KindConflict, "game_not_finished", "can not re-vote before reveal")
+	// ErrInvalidRevoteThreshold is returned when the re-vote threshold is negative.
+	ErrInvalidRevoteThreshold = domain.NewError(
+		domain.KindValidation, "invalid_revote_threshold", "re-vote threshold should not be negative",
+	)
+	// ErrGameArchived is returned on any action in the archived game.
+	ErrGameArchived = domain.NewError(domain.KindConflict, "game_archived", "game is archived")
+	// ErrUnknownCard is returned when the card does not belong to the game deck.
+	ErrUnknownCard = domain.NewError(domain.KindValidation, "unknown_card", "unknown card")
+	// ErrNotAllPlayersSeated is returned when a new seats order does not include every player.
+	ErrNotAllPlayersSeated = domain.NewError(domain.KindValidation, "invalid_seats_order", "all players should be seated")
+	// ErrInvalidSeatsOrder is returned when a new seats order has unknown or duplicated positions.
+	ErrInvalidSeatsOrder = domain.NewError(
+		domain.KindValidation, "invalid_seats_order", "seats order should contain every player exactly once",
+	)
+	// ErrGameNameTooLong is returned when the game name is longer than MaxNameLength.
+	ErrGameNameTooLong = domain.NewError(
+		domain.KindValidation, "invalid_game_name", "game name should be at most 128 characters long",
+	)
+	// ErrTicketURLTooLong is returned when the ticket URL is longer than MaxTicketURLLength.
+	ErrTicketURLTooLong = domain.NewError(
+		domain.KindValidation, "invalid_ticket_url", "ticket URL should be at most 2048 characters long",
+	)
+	// ErrInvalidTicketURL is returned when the ticket URL is not an absolute http(s) URL.
+	ErrInvalidTicketURL = domain.NewError(
+		domain.KindValidation, "invalid_ticket_url", "ticket URL should be an absolute http or https URL",
+	)
+	// ErrInvalidConfidence is returned when the vote confidence is not one of the known levels.
+	ErrInvalidConfidence = domain.NewError(
+		domain.KindValidation, "invalid_confidence", "confidence should be one of: low, normal, high",
+	)
+	// ErrInvalidPasscode is returned when the join passcode is too short or too long.
+	ErrInvalidPasscode = domain.NewError(
+		domain.KindValidation, "invalid_passcode", "passcode should be 4 to 72 bytes long",
+	)
+	// ErrPasscodeRequired is returned when a new player joins the protected game without a passcode.
+	ErrPasscodeRequired = domain.NewError(domain.KindForbidden, "passcode_required", "game passcode should be provided")
+	// ErrWrongPasscode is returned when a new player joins the protected game with a wrong passcode.
+	ErrWrongPasscode = domain.NewError(domain.KindForbidden, "wrong_passcode", "wrong game passcode")
+	// ErrTooManyGames is returned when the user already runs the maximal number of games.
+	ErrTooManyGames = domain.NewError(domain.KindConflict, "too_many_games", "user has too many active games")
+	// ErrGameIsFull is returned when a new player tries to join the game with the maximal number of players.
+	ErrGameIsFull = domain.NewError(domain.KindConflict, "game_is_full", "game has the maximal number of players")
+	// ErrEmptyCardType is returned on creation of a card without type.
+	ErrEmptyCardType = domain.NewError(domain.KindValidation, "invalid_card", "card type should be provided")
+	// ErrCardTypeTooLong is returned on creation of a card with too long type.
+	ErrCardTypeTooLong = domain.NewError(domain.KindValidation, "invalid_card", "card type should be 1-3 chars long")
+	// ErrEmptyDeckName is returned on creation of a deck without name.
+	ErrEmptyDeckName = domain.NewError(domain.KindValidation, "invalid_deck", "name should be provided")
+	// ErrInvalidDimensionName is returned on creation of a dimension without name or with too long one.
+	ErrInvalidDimensionName = domain.NewError(
+		domain.KindValidation, "invalid_deck", "dimension name should be 1-32 characters long",
+	)
+	// ErrInvalidDimensions is returned on creation of a deck without dimensions, with too many or duplicated ones.
+	ErrInvalidDimensions = domain.NewError(
+		domain.KindValidation, "invalid_deck", "deck should have 1-5 dimensions with unique names",
+	)
+	// ErrUnknownFormula is returned on creation of a deck with an unknown formula.
+	ErrUnknownFormula = domain.NewError(
+		domain.KindValidation, "invalid_deck", "formula should be one of: sum, product, max, average",
+	)
+	// ErrDimensionsNotVoted is returned when the vote does not have exactly one card per deck dimension.
+	ErrDimensionsNotVoted = domain.NewError(
+		domain.KindValidation, "invalid_vote", "a card should be voted for every deck dimension",
+	)
+	// ErrChatDisabled is returned on chatting in a game with the chat turned off.
+	ErrChatDisabled = domain.NewError(domain.KindForbidden, "chat_disabled", "chat is disabled in the game")
+	// ErrInvalidChatMessage is returned when the chat message is empty or longer than MaxChatMessageLength.
+	ErrInvalidChatMessage = domain.NewError(
+		domain.KindValidation, "invalid_chat_message", "chat message should be 1 to 280 characters long",
+	)
+	// ErrUnknownReaction is returned when the reaction is not one of Reactions.
+	ErrUnknownReaction = domain.NewError(domain.KindValidation, "unknown_reaction", "unknown reaction")
+	// ErrUnknownChatTarget is returned when a reaction is addressed to a player who is not in the game.
+	ErrUnknownChatTarget = domain.NewError(domain.KindValidation, "unknown_target", "unknown reaction target")
+	// ErrEmptyDeck is returned on creation of a deck without cards.
+	ErrEmptyDeck = domain.NewError(domain.KindValidation, "invalid_deck", "cards should be provided")
+)
@@ -0,0 +1,152 @@
+package games
+
+// DimensionEstimate is a summary of votes of a single dimension, abstains and unknown cards are not counted.
+type DimensionEstimate struct {
+	// Dimension is a dimension name, it is empty for plain decks.
+	Dimension string
+	Votes     int
+	Lowest    Card
+	Highest   Card
+	// MostVoted is the most voted card, the higher card wins a tie.
+	MostVoted Card
+	// Average is an average of numeric votes, it is nil when no vote is numeric.
+	Average *float64
+	// Spread is a distance in deck cards between the lowest and the highest vote.
+	Spread int
+}
+
+// Estimate is a summary of the game votes per dimension, plain decks have a single unnamed dimension.
+type Estimate struct {
+	Dimensions []DimensionEstimate
+	// Final combines dimension averages with the deck formula.
+	// It is nil when the deck has no formula or any dimension has no numeric votes.
+	Final *float64
+}
+
+// ballot is a set of cards and votes of a single dimension.
+type ballot struct {
+	dimension string
+	cards     []Card
+	votes     []Card
+}
+
+// Estimate summarizes the current votes, it is meant to be shown once the cards are revealed.
+func (g Game) Estimate() Estimate {
+	ballots := g.ballots()
+	e := Estimate{Dimensions: make([]DimensionEstimate, len(ballots))}
+	averages := make([]float64, 0, len(ballots))
+	for i, b := range ballots {
+		e.Dimensions[i] = b.estimate()
+		if avg := e.Dimensions[i].Average; avg != nil {
+			averages = append(averages, *avg)
+		}
+	}
+
+	if len(averages) == len(ballots) {
+		e.Final = combine(g.cardsDeck.Formula(), averages)
+	}
+
+	return e
+}
+
+// ballots collects player votes per dimension in the deck order.
+func (g Game) ballots() []ballot {
+	if !g.cardsDeck.IsDimensional() {
+		b := ballot{cards: g.cardsDeck.Cards()}
+		for _, p := range g.players {
+			if p.VotedCard != nil {
+				b.votes = append(b.votes, *p.VotedCard)
+			}
+		}
+		return []ballot{b}
+	}
+
+	list := make([]ballot, 0, len(g.cardsDeck.Dimensions()))
+	for _, d := range g.cardsDeck.Dimensions() {
+		b := ballot{dimension: d.Name(), cards: d.Cards()}
+		for _, p := range g.players {
+			if c, ok := p.VotedCards[d.Name()]; ok {
+				b.votes = append(b.votes, c)
+			}
+		}
+		list = append(list, b)
+	}
+
+	return list
+}
+
+func (b ballot) estimate() DimensionEstimate {
+	index := make(map[Card]int, len(b.cards))
+	for i, c := range b.cards {
+		index[c] = i
+	}
+
+	e := DimensionEstimate{Dimension: b.dimension}
+	lowest, highest, best := -1, -1, -1
+	counts := make(map[int]int)
+	sum, numeric := 0.0, 0
+	for _, v := range b.votes {
+		pos, ok := index[v]
+		if !ok || v == AbstainCard {
+			continue
+		}
+		e.Votes++
+		counts[pos]++
+		if lowest < 0 || pos < lowest {
+			lowest = pos
+		}
+		if pos > highest {
+			highest = pos
+		}
+		if best < 0 || counts[pos] > counts[best] || (counts[pos] == counts[best] && pos > best) {
+			best = pos
+		}
+		if value, ok := v.Value(); ok {
+			sum += value
+			numeric++
+		}
+	}
+
+	if e.Votes == 0 {
+		return e
+	}
+
+	e.Lowest, e.Highest, e.MostVoted = b.cards[lowest], b.cards[highest], b.cards[best]
+	e.Spread = highest - lowest
+	if numeric > 0 {
+		avg := sum / float64(numeric)
+		e.Average = &avg
+	}
+
+	return e
+}
+
+// combine applies the formula to dimension averages, nil is returned when there is no formula.
+func combine(formula string, values []float64) *float64 {
+	var result float64
+	switch formula {
+	case FormulaSum, FormulaAverage:
+		for _, v := range values {
+			result += v
+		}
+		if formula == FormulaAverage {
+			result /= float64(len(values))
+		}
+	case FormulaProduct:
+		result = 1
+		for _, v := range values {
+			result *= v
+		}
+	case FormulaMax:
+		result = values[0]
+		for _, v := range values[1:] {
+			if v > result {
+				result = v
+			}
+		}
+	default:
+		return nil
+	}
+
+	return &result
+}
@@ -0,0 +1,111 @@
+package games_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/games"
+	"planningpoker/test"
+)
+
+func TestDimensionalGame(t *testing.T) {
+	game := test.NewTestGame(t, test.NewDimensionalGame(t, games.FormulaSum)).
+		When().UserJoins(test.User1).
+		And().UserJoins(test.User2).
+		And().UserVotes(test.User1, "1").
+		Then().ShouldFail("a card should be voted for every deck dimension").
+		When().UserVotesDimensions(test.User1, "complexity", "1").
+		Then().ShouldFail("a card should be voted for every deck dimension").
+		When().UserVotesDimensions(test.User1, "complexity", "1", "size", "2").
+		Then().ShouldFail("a card should be voted for every deck dimension").
+		When().UserVotesDimensions(test.User1, "complexity", "1", "effort", "3").
+		Then().ShouldFail("unknown card").
+		When().UserVotesDimensions(test.User1, "complexity", "1", "effort", "1").
+		And().UserVotesDimensions(test.User2, "complexity", "3", "effort", "8").
+		Then().ShouldSucceed().
+		Instance()
+
+	assert.True(t, game.Players()[test.User1].Voted())
+	assert.Nil(t, game.Players()[test.User1].VotedCard)
+	assert.Equal(t, map[string]games.Card{"complexity": "1", "effort": "1"}, game.Players()[test.User1].VotedCards)
+
+	// the widest dimension spread is used for re-vote suggestions
+	assert.Equal(t, 3, game.Spread())
+
+	test.NewTestGame(t, game).
+		When().UserUnVotes(test.User2).
+		Then().ShouldSucceed()
+	assert.False(t, game.Players()[test.User2].Voted())
+}
+
+func TestGame_Estimate(t *testing.T) {
+	t.Parallel()
+
+	value := func(v float64) *float64 {
+		return &v
+	}
+
+	testCases := map[string]struct {
+		formula  string
+		expFinal *float64
+	}{
+		"no formula":      {},
+		"sum formula":     {formula: games.FormulaSum, expFinal: value(8)},
+		"product formula": {formula: games.FormulaProduct, expFinal: value(12)},
+		"max formula":     {formula: games.FormulaMax, expFinal: value(6)},
+		"average formula": {formula: games.FormulaAverage, expFinal: value(4)},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			game := test.NewTestGame(t, test.NewDimensionalGame(t, tt.formula)).
+				When().UserJoins(test.User1).
+				And().UserJoins(test.User2).
+				And().UserJoins(test.User3).
+				And().UserVotesDimensions(test.User1, "complexity", "1", "effort", "2").
+				And().UserVotesDimensions(test.User2, "complexity", "3", "effort", "8").
+				And().UserVotesDimensions(test.User3, "complexity", "?", "effort", "8").
+				Then().ShouldSucceed().
+				Instance()
+
+			e := game.Estimate()
+			require.Len(t, e.Dimensions, 2)
+			assert.Equal(t, games.DimensionEstimate{
+				Dimension: "complexity", Votes: 2, Lowest: "1", Highest: "3", MostVoted: "3", Average: value(2), Spread: 2,
+			}, e.Dimensions[0])
+			assert.Equal(t, games.DimensionEstimate{
+				Dimension: "effort", Votes: 3, Lowest: "2", Highest: "8", MostVoted: "8", Average: value(6), Spread: 2,
+			}, e.Dimensions[1])
+			if tt.expFinal == nil {
+				assert.Nil(t, e.Final)
+			} else {
+				require.NotNil(t, e.Final)
+				assert.InDelta(t, *tt.expFinal, *e.Final, 0.001)
+			}
+		})
+	}
+}
+
+func TestGame_EstimatePlainDeck(t *testing.T) {
+	t.Parallel()
+
+	game := test.NewTestGame(t, test.NewSimpleGame(t, true)).
+		When().UserJoins(test.User1).
+		And().UserJoins(test.User2).
+		And().UserVotes(test.User1, "XS").
+		Then().ShouldSucceed().
+		Instance()
+
+	// plain decks have a single unnamed dimension and non numeric cards have no average
+	assert.Equal(t, games.Estimate{Dimensions: []games.DimensionEstimate{
+		{Votes: 1, Lowest: "XS", Highest: "XS", MostVoted: "XS"},
+	}}, game.Estimate())
+
+	test.NewTestGame(t, game).When().UserUnVotes(test.User1).Then().ShouldSucceed()
+	assert.Equal(t, games.Estimate{Dimensions: []games.DimensionEstimate{{}}}, game.Estimate())
+}
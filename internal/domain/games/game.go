@@ -2,13 +2,15 @@
 package games
 
 import (
-	"errors"
+	"sort"
 	"strings"
+	"time"
 
 	"planningpoker/internal/domain"
 	"planningpoker/internal/domain/events"
 
 	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
 )
 
 const (
@@ -16,8 +18,16 @@ const (
 	GameStateStarted = "started"
 	// GameStateFinished represents finished game state.
 	GameStateFinished = "finished"
+	// GameStateArchived represents a game which was idle for too long, it can not be played anymore.
+	GameStateArchived = "archived"
+	// ConfidenceLow represents a vote the player is not sure about.
+	ConfidenceLow = "low"
 	// ConfidenceNormal represents default confidence level.
 	ConfidenceNormal = "normal"
+	// ConfidenceHigh represents a vote the player is sure about.
+	ConfidenceHigh = "high"
+	// MaxAttempts is the maximal number of archived attempts of a ticket, the oldest ones are dropped.
+	MaxAttempts = 20
 )
 
 // Game is a domain aggregate that represents one single game.
@@ -30,19 +40,38 @@ type Game struct {
 	players           map[string]*Player
 	state             string
 	everyoneCanReveal bool
+	facilitatorID     string
+	lastActivityAt    time.Time
+	passcodeHash      []byte
+	teamID            string
+	attempts          []Attempt
+	revoteThreshold   int
+	anonymous         bool
+	chatDisabled      bool
 }
 
 // Player is an entity of a game player with state.
 type Player struct {
-	VotedCard  *Card
+	VotedCard *Card
+	// VotedCards are cards voted per dimension name, they are used instead of VotedCard in dimensional decks.
+	VotedCards map[string]Card
 	Confidence string
 	CanReveal  bool
 	Active     bool
+	// Seat is a player position at the table, players are shown in ascending seats order.
+	Seat int
+	// Handle is a stable public player identifier within the game, unlike user ID it is safe to share.
+	Handle string
+}
+
+// Voted returns true if the player voted a card, or cards of every dimension in dimensional decks.
+func (p Player) Voted() bool {
+	return p.VotedCard != nil || len(p.VotedCards) > 0
 }
 
 // NewGame creates a new game aggregate instance.
 func NewGame(cmd CreateGameCommand) *Game {
-	return &Game{
+	g := &Game{
 		id:                strings.ReplaceAll(uuid.New().String(), "-", ""),
 		name:              cmd.Name,
 		ticketURL:         cmd.TicketURL,
@@ -50,12 +79,40 @@ func NewGame(cmd CreateGameCommand) *Game {
 		players:           make(map[string]*Player),
 		state:             GameStateStarted,
 		everyoneCanReveal: cmd.EveryoneCanReveal,
+		facilitatorID:     cmd.UserID,
+		lastActivityAt:    time.Now(),
+		passcodeHash:      cmd.PasscodeHash,
+		teamID:            cmd.TeamID,
+		revoteThreshold:   cmd.RevoteThreshold,
+		anonymous:         cmd.Anonymous,
+		chatDisabled:      cmd.ChatDisabled,
 	}
+	g.addUpdatedEvent(&Action{
+		Name:    ActionCreate,
+		ActorID: cmd.UserID,
+		After:   map[string]string{"name": g.name, "ticket_url": g.ticketURL},
+	})
+
+	return g
 }
 
 // NewRaw instantiates a game aggregate from raw data.
 // It should never be used in any logic except aggregate hydration from any serialized format (db, etc...)
-func NewRaw(id, name, ticketURL string, deck CardsDeck, players map[string]*Player, state string, ecr bool) *Game {
+func NewRaw(
+	id, name, ticketURL string,
+	deck CardsDeck,
+	players map[string]*Player,
+	state string,
+	ecr bool,
+	facilitatorID string,
+	lastActivityAt time.Time,
+	passcodeHash []byte,
+	teamID string,
+	attempts []Attempt,
+	revoteThreshold int,
+	anonymous bool,
+	chatDisabled bool,
+) *Game {
 	return &Game{
 		id:                id,
 		name:              name,
@@ -64,6 +121,14 @@ func NewRaw(id, name, ticketURL string, deck CardsDeck, players map[string]*Play
 		players:           players,
 		state:             state,
 		everyoneCanReveal: ecr,
+		facilitatorID:     facilitatorID,
+		lastActivityAt:    lastActivityAt,
+		passcodeHash:      passcodeHash,
+		teamID:            teamID,
+		attempts:          attempts,
+		revoteThreshold:   revoteThreshold,
+		anonymous:         anonymous,
+		chatDisabled:      chatDisabled,
 	}
 }
 
@@ -102,40 +167,249 @@ func (g Game) EveryoneCanReveal() bool {
 	return g.everyoneCanReveal
 }
 
+// FacilitatorID returns an ID of the user who created the game.
+func (g Game) FacilitatorID() string {
+	return g.facilitatorID
+}
+
+// TeamID returns an ID of the team the game is played by, it is empty for games outside of teams.
+func (g Game) TeamID() string {
+	return g.teamID
+}
+
+// PasscodeHash returns a hash of the join passcode, it is empty for unprotected games.
+func (g Game) PasscodeHash() []byte {
+	return g.passcodeHash
+}
+
+// IsProtected returns true if new players should provide the passcode to join the game.
+func (g Game) IsProtected() bool {
+	return len(g.passcodeHash) > 0
+}
+
+// IsAnonymous returns true if revealed cards should never be shown next to player names.
+func (g Game) IsAnonymous() bool {
+	return g.anonymous
+}
+
+// IsChatDisabled returns true when players can not send chat messages and reactions.
+func (g Game) IsChatDisabled() bool {
+	return g.chatDisabled
+}
+
+// Attempts returns archived voting attempts of the current ticket, the oldest first.
+func (g Game) Attempts() []Attempt {
+	return g.attempts
+}
+
+// RevoteThreshold returns a spread in deck cards a re-vote is suggested above, zero disables suggestions.
+func (g Game) RevoteThreshold() int {
+	return g.revoteThreshold
+}
+
+// Spread returns a distance in deck cards between the lowest and the highest vote, abstains are not counted.
+// The widest spread is returned for dimensional decks.
+func (g Game) Spread() int {
+	spread := 0
+	for _, d := range g.Estimate().Dimensions {
+		if d.Spread > spread {
+			spread = d.Spread
+		}
+	}
+
+	return spread
+}
+
+// RevoteSuggested returns true when the revealed votes spread is wider than the game re-vote threshold.
+func (g Game) RevoteSuggested() bool {
+	return g.state == GameStateFinished && g.revoteThreshold > 0 && g.Spread() > g.revoteThreshold
+}
+
+// LastActivityAt returns the time of the last player action in the game.
+func (g Game) LastActivityAt() time.Time {
+	return g.lastActivityAt
+}
+
+// IsIdleSince checks that there was no activity in the game since specific time.
+func (g Game) IsIdleSince(t time.Time) bool {
+	return g.lastActivityAt.Before(t)
+}
+
+// PlayerIDs returns IDs of all players ordered by their seats.
+func (g Game) PlayerIDs() []string {
+	ids := make([]string, 0, len(g.players))
+	for id := range g.players {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		si, sj := g.players[ids[i]].Seat, g.players[ids[j]].Seat
+		if si != sj {
+			return si < sj
+		}
+		// seats might collide for games persisted before seats were introduced
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
+
 // Update updates game generic data.
 func (g *Game) Update(cmd UpdateGameCommand) error {
 	_, ok := g.players[cmd.UserID]
 	if !ok {
-		return errors.New("user is not a player")
+		return ErrNotAPlayer
+	}
+
+	if g.state == GameStateArchived {
+		return ErrGameArchived
+	}
+
+	before, after := map[string]string{}, map[string]string{}
+	if g.name != cmd.Name {
+		before["name"], after["name"] = g.name, cmd.Name
+	}
+	if g.ticketURL != cmd.TicketURL {
+		before["ticket_url"], after["ticket_url"] = g.ticketURL, cmd.TicketURL
 	}
 
 	g.name = cmd.Name
 	g.ticketURL = cmd.TicketURL
-	g.setChanged()
+
+	if len(after) == 0 {
+		g.setChanged(nil)
+		return nil
+	}
+	// attempts belong to the estimated ticket, they are not relevant to another one
+	g.attempts = nil
+	action := g.action(ActionUpdate, cmd.UserID)
+	action.Before, action.After = before, after
+	g.setChanged(action)
 
 	return nil
 }
 
 // Join adds a new player to the game.
 func (g *Game) Join(cmd JoinGameCommand) error {
-	g.setChanged()
+	if g.state == GameStateArchived {
+		return ErrGameArchived
+	}
 
 	if g.IsPlayer(cmd.UserID) {
+		// players rejoin on every reconnect, it is not an action worth recording
+		g.setChanged(nil)
 		g.players[cmd.UserID].Active = true
+		if g.players[cmd.UserID].Handle == "" {
+			g.players[cmd.UserID].Handle = newPlayerHandle()
+		}
 		return nil
 	}
 
+	if err := g.checkPasscode(cmd); err != nil {
+		return err
+	}
+
 	canReveal := g.everyoneCanReveal || len(g.players) == 0
 
 	g.players[cmd.UserID] = &Player{
 		VotedCard: nil,
 		CanReveal: canReveal,
 		Active:    true,
+		Seat:      g.nextSeat(),
+		Handle:    newPlayerHandle(),
+	}
+	g.setChanged(g.action(ActionJoin, cmd.UserID))
+
+	return nil
+}
+
+// ChangePasscode rotates or removes the join passcode, only the facilitator can do that.
+// Players who already joined the game stay in it.
+func (g *Game) ChangePasscode(cmd ChangePasscodeCommand) error {
+	if !g.IsPlayer(cmd.UserID) {
+		return ErrNotAPlayer
+	}
+
+	if !g.IsFacilitator(cmd.UserID) {
+		return ErrNotAFacilitator
+	}
+
+	if g.state == GameStateArchived {
+		return ErrGameArchived
+	}
+
+	g.passcodeHash = cmd.PasscodeHash
+	g.setChanged(g.action(ActionChangePasscode, cmd.UserID))
+
+	return nil
+}
+
+// CanSpectate checks that the user is allowed to watch the game without taking a seat,
+// protected games require the passcode from spectators like from new players.
+func (g *Game) CanSpectate(cmd JoinGameCommand) error {
+	if g.IsPlayer(cmd.UserID) {
+		return nil
+	}
+
+	return g.checkPasscode(cmd)
+}
+
+// checkPasscode checks that a new player is allowed to join the game, the facilitator is always allowed.
+func (g *Game) checkPasscode(cmd JoinGameCommand) error {
+	if !g.IsProtected() || g.IsFacilitator(cmd.UserID) {
+		return nil
+	}
+
+	if cmd.Passcode == "" {
+		return ErrPasscodeRequired
+	}
+	if bcrypt.CompareHashAndPassword(g.passcodeHash, []byte(cmd.Passcode)) != nil {
+		return ErrWrongPasscode
 	}
 
 	return nil
 }
 
+// Rearrange changes players seats according to the provided order, only the facilitator can do that.
+func (g *Game) Rearrange(cmd RearrangeSeatsCommand) error {
+	if !g.IsPlayer(cmd.UserID) {
+		return ErrNotAPlayer
+	}
+
+	if !g.IsFacilitator(cmd.UserID) {
+		return ErrNotAFacilitator
+	}
+
+	if g.state == GameStateArchived {
+		return ErrGameArchived
+	}
+
+	current := g.PlayerIDs()
+	if len(cmd.Order) != len(current) {
+		return ErrNotAllPlayersSeated
+	}
+
+	seated := make(map[int]bool, len(cmd.Order))
+	for _, pos := range cmd.Order {
+		if pos < 0 || pos >= len(current) || seated[pos] {
+			return ErrInvalidSeatsOrder
+		}
+		seated[pos] = true
+	}
+
+	action := g.action(ActionRearrange, cmd.UserID)
+	action.Before = map[string]string{"seats": g.seatsOrder()}
+
+	for seat, pos := range cmd.Order {
+		g.players[current[pos]].Seat = seat
+	}
+
+	action.After = map[string]string{"seats": g.seatsOrder()}
+	g.setChanged(action)
+
+	return nil
+}
+
 // Leave marks a player as inactive or removes them from players depending on voting state.
 func (g *Game) Leave(cmd LeaveGameCommand) error {
 	p, ok := g.players[cmd.UserID]
@@ -143,10 +417,10 @@ func (g *Game) Leave(cmd LeaveGameCommand) error {
 		return nil
 	}
 
-	g.setChanged()
+	g.setChanged(g.action(ActionLeave, cmd.UserID))
 
 	// if the player is voted, we don't want to delete the data until cards not revealed.
-	if p.VotedCard != nil {
+	if p.Voted() {
 		p.Active = false
 		return nil
 	}
@@ -160,22 +434,43 @@ func (g *Game) Leave(cmd LeaveGameCommand) error {
 func (g *Game) Restart(cmd RestartGameCommand) error {
 	_, ok := g.players[cmd.UserID]
 	if !ok {
-		return errors.New("user is not a player")
+		return ErrNotAPlayer
+	}
+
+	if g.state == GameStateArchived {
+		return ErrGameArchived
 	}
+
 	g.state = GameStateStarted
+	g.attempts = nil
+	g.setChanged(g.action(ActionRestart, cmd.UserID))
+	g.resetVotes()
 
-	// cleanup non-active players and remove votes
-	players := g.players
-	for id, p := range g.players {
-		if !p.Active {
-			delete(players, id)
-			continue
-		}
-		players[id].VotedCard = nil
+	return nil
+}
+
+// Revote archives the revealed votes as the next attempt and starts voting on the same ticket again.
+func (g *Game) Revote(cmd RevoteCommand) error {
+	if !g.IsPlayer(cmd.UserID) {
+		return ErrNotAPlayer
+	}
+
+	if g.state == GameStateArchived {
+		return ErrGameArchived
+	}
+
+	if g.state != GameStateFinished {
+		return ErrRevoteBeforeReveal
+	}
+
+	g.attempts = append(g.attempts, g.attempt())
+	if len(g.attempts) > MaxAttempts {
+		g.attempts = g.attempts[len(g.attempts)-MaxAttempts:]
 	}
-	g.players = players
 
-	g.setChanged()
+	g.state = GameStateStarted
+	g.setChanged(g.action(ActionRevote, cmd.UserID))
+	g.resetVotes()
 
 	return nil
 }
@@ -183,21 +478,32 @@ func (g *Game) Restart(cmd RestartGameCommand) error {
 // Vote performs a player voting.
 func (g *Game) Vote(cmd VoteCommand) error {
 	if !g.IsPlayer(cmd.UserID) {
-		return errors.New("user is not a player")
+		return ErrNotAPlayer
 	}
 
-	if g.state != GameStateStarted {
-		return errors.New("can not vote on ended game")
+	if g.state == GameStateArchived {
+		return ErrGameArchived
 	}
 
-	if !g.cardsDeck.IsInDeck(cmd.Vote) {
-		return errors.New("unknown card")
+	if g.state != GameStateStarted {
+		return ErrVoteOnFinishedGame
 	}
 
-	g.players[cmd.UserID].VotedCard = &cmd.Vote
-	g.players[cmd.UserID].Confidence = cmd.Confidence
+	p := g.players[cmd.UserID]
+	if g.cardsDeck.IsDimensional() {
+		if err := g.checkDimensionalVote(cmd.Cards); err != nil {
+			return err
+		}
+		p.VotedCard, p.VotedCards = nil, cmd.Cards
+	} else {
+		if !g.cardsDeck.IsInDeck(cmd.Vote) {
+			return ErrUnknownCard
+		}
+		p.VotedCard, p.VotedCards = &cmd.Vote, nil
+	}
+	p.Confidence = cmd.Confidence
 
-	g.setChanged()
+	g.setChanged(g.action(ActionVote, cmd.UserID))
 
 	return nil
 }
@@ -206,16 +512,26 @@ func (g *Game) Vote(cmd VoteCommand) error {
 func (g *Game) Reveal(cmd RevealCardsCommand) error {
 	p, ok := g.players[cmd.UserID]
 	if !ok {
-		return errors.New("user is not a player")
+		return ErrNotAPlayer
 	}
 
 	if !p.CanReveal {
-		return errors.New("user can not reveal cards")
+		return ErrCanNotReveal
+	}
+
+	if g.state == GameStateArchived {
+		return ErrGameArchived
+	}
+
+	// revealing twice does not make a new round
+	if g.state == GameStateStarted {
+		round := g.round()
+		g.AddEvent(events.NewDomainEventBuilder(events.EventTypeRoundRevealed).ForAggregate(g.id).WithPayload(round).Build())
 	}
 
 	g.state = GameStateFinished
 
-	g.setChanged()
+	g.setChanged(g.action(ActionReveal, cmd.UserID))
 
 	return nil
 }
@@ -223,24 +539,41 @@ func (g *Game) Reveal(cmd RevealCardsCommand) error {
 // UnVote removes a vote for a passenger.
 func (g *Game) UnVote(cmd UnVoteCommand) error {
 	if !g.IsPlayer(cmd.UserID) {
-		return errors.New("user is not a player")
+		return ErrNotAPlayer
+	}
+
+	if g.state == GameStateArchived {
+		return ErrGameArchived
 	}
 
 	if g.state != GameStateStarted {
-		return errors.New("can not un-vote on ended game")
+		return ErrUnVoteOnFinishedGame
 	}
 
 	g.players[cmd.UserID].VotedCard = nil
+	g.players[cmd.UserID].VotedCards = nil
 	g.players[cmd.UserID].Confidence = ConfidenceNormal
 
-	g.setChanged()
+	g.setChanged(g.action(ActionUnVote, cmd.UserID))
 
 	return nil
 }
 
+// Archive stops the game forever, players are notified with the archived game state.
+func (g *Game) Archive() {
+	g.state = GameStateArchived
+	g.setChanged(&Action{Name: ActionArchive})
+}
+
 // ForceChanged marks the aggregate as changes (dirty state).
+// Unlike player actions, it does not prolong the game activity.
 func (g *Game) ForceChanged() {
-	g.setChanged()
+	g.addUpdatedEvent(nil)
+}
+
+// IsFacilitator checks if specific user is the game facilitator.
+func (g *Game) IsFacilitator(uid string) bool {
+	return g.facilitatorID != "" && g.facilitatorID == uid
 }
 
 // IsPlayer checks if specific user is a player.
@@ -249,6 +582,82 @@ func (g *Game) IsPlayer(uid string) bool {
 	return ok
 }
 
-func (g *Game) setChanged() {
-	g.AddEvent(events.NewDomainEventBuilder(events.EventTypeGameUpdated).ForAggregate(g.id).Build())
+// resetVotes removes votes and cleans up non-active players, who were kept until the cards were revealed.
+func (g *Game) resetVotes() {
+	for id, p := range g.players {
+		if !p.Active {
+			delete(g.players, id)
+			continue
+		}
+		p.VotedCard, p.VotedCards = nil, nil
+	}
+}
+
+// checkDimensionalVote checks that exactly one card of every deck dimension is voted.
+func (g *Game) checkDimensionalVote(cards map[string]Card) error {
+	if len(cards) != len(g.cardsDeck.Dimensions()) {
+		return ErrDimensionsNotVoted
+	}
+
+	for name, card := range cards {
+		d, ok := g.cardsDeck.Dimension(name)
+		if !ok {
+			return ErrDimensionsNotVoted
+		}
+		if !d.IsInDeck(card) {
+			return ErrUnknownCard
+		}
+	}
+
+	return nil
+}
+
+func newPlayerHandle() string {
+	return strings.ReplaceAll(uuid.New().String(), "-", "")[:12]
+}
+
+func (g *Game) nextSeat() int {
+	seat := 0
+	for _, p := range g.players {
+		if p.Seat >= seat {
+			seat = p.Seat + 1
+		}
+	}
+	return seat
+}
+
+// setChanged prolongs the game activity and records the action if any.
+func (g *Game) setChanged(action *Action) {
+	g.lastActivityAt = time.Now()
+	g.addUpdatedEvent(action)
+}
+
+func (g *Game) addUpdatedEvent(action *Action) {
+	b := events.NewDomainEventBuilder(events.EventTypeGameUpdated).ForAggregate(g.id)
+	if action != nil {
+		b.WithPayload(*action)
+	}
+
+	g.AddEvent(b.Build())
+}
+
+// action creates an action of the player.
+func (g *Game) action(name, userID string) *Action {
+	a := &Action{Name: name, ActorID: userID}
+	if p, ok := g.players[userID]; ok {
+		a.ActorHandle = p.Handle
+	}
+
+	return a
+}
+
+// seatsOrder returns player handles ordered by seats.
+func (g *Game) seatsOrder() string {
+	ids := g.PlayerIDs()
+	handles := make([]string, len(ids))
+	for i, id := range ids {
+		handles[i] = g.players[id].Handle
+	}
+
+	return strings.Join(handles, ",")
 }
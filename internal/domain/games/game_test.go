@@ -3,7 +3,12 @@ package games_test
 import (
 	"testing"
 
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/games"
 	"planningpoker/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSimpleGame(t *testing.T) {
@@ -54,6 +59,92 @@ func TestRestartGame(t *testing.T) {
 		And().GameShouldBeRunning()
 }
 
+func TestRevoteKeepsAttempts(t *testing.T) {
+	game := test.NewTestGame(t, test.NewSimpleGame(t, true)).
+		When().UserJoins(test.User1).
+		And().UserJoins(test.User2).
+		And().UserUpdatesGameName(test.User1, "ticket").
+		And().UserVotes(test.User2, "S").
+		And().UserVotes(test.User1, "XS").
+		And().UserRevotes(test.User1).
+		Then().ShouldFail("can not re-vote before reveal").
+		When().UserReveals(test.User1).
+		And().UserLeaves(test.User2).
+		And().UserRevotes(test.User1).
+		Then().ShouldSucceed().
+		And().GameShouldBeRunning().
+		And().ShouldHaveNoVote(test.User1).
+		When().UserVotes(test.User1, "S").
+		And().UserReveals(test.User1).
+		And().UserRevotes(test.User1).
+		Then().ShouldSucceed().
+		And().ShouldHaveGameName("ticket").
+		Instance()
+
+	handle1 := game.Players()[test.User1].Handle
+	assert.False(t, game.IsPlayer(test.User2))
+	require.Len(t, game.Attempts(), 2)
+	assert.Equal(t, 1, game.Attempts()[0].Number)
+	assert.Equal(t, []games.AttemptVote{{UserID: test.User1, Handle: handle1, Card: "XS"}, {
+		UserID: test.User2, Handle: game.Attempts()[0].Votes[1].Handle, Card: "S",
+	}}, game.Attempts()[0].Votes)
+	assert.Equal(t, games.Attempt{
+		Number: 2, Votes: []games.AttemptVote{{UserID: test.User1, Handle: handle1, Card: "S"}},
+	}, game.Attempts()[1])
+
+	test.NewTestGame(t, game).
+		When().UserUpdatesGameName(test.User1, "another ticket").
+		Then().ShouldSucceed()
+	assert.Empty(t, game.Attempts())
+}
+
+func TestRestartClearsAttempts(t *testing.T) {
+	game := test.NewTestGame(t, test.NewSimpleGame(t, true)).
+		When().UserJoins(test.User1).
+		And().UserReveals(test.User1).
+		And().UserRevotes(test.User1).
+		And().UserReveals(test.User1).
+		And().UserRestartsGame(test.User1).
+		Then().ShouldSucceed().
+		Instance()
+
+	assert.Empty(t, game.Attempts())
+}
+
+func TestRevoteIsSuggestedOnWideSpread(t *testing.T) {
+	deck, err := games.NewCardsDeck("fibonacci", []games.Card{"1", "2", "3", "5", "8", games.AbstainCard})
+	require.NoError(t, err)
+	cmd, err := games.NewCreateGameCommand("", "", test.User1, *deck, true)
+	require.NoError(t, err)
+	require.NoError(t, cmd.SuggestRevoteAbove(2))
+
+	game := test.NewTestGame(t, games.NewGame(*cmd)).
+		When().UserJoins(test.User1).
+		And().UserJoins(test.User2).
+		And().UserJoins(test.User3).
+		And().UserVotes(test.User1, "1").
+		And().UserVotes(test.User2, "?").
+		And().UserVotes(test.User3, "5").
+		Then().ShouldSucceed().
+		Instance()
+
+	// abstains are not counted and cards are not suggested to be re-voted before reveal
+	assert.Equal(t, 3, game.Spread())
+	assert.False(t, game.RevoteSuggested())
+
+	test.NewTestGame(t, game).When().UserReveals(test.User1).Then().ShouldSucceed()
+	assert.True(t, game.RevoteSuggested())
+
+	test.NewTestGame(t, game).
+		When().UserRevotes(test.User1).
+		And().UserVotes(test.User1, "3").
+		And().UserVotes(test.User3, "5").
+		And().UserReveals(test.User1).
+		Then().ShouldSucceed()
+	assert.Equal(t, 1, game.Spread())
+	assert.False(t, game.RevoteSuggested())
+}
+
 func TestNonPlayerCanNotRestartAGame(t *testing.T) {
 	test.NewTestGame(t, test.NewSimpleGame(t, true)).
 		When().UserJoins(test.User1).
@@ -137,3 +228,287 @@ func TestCanNotUpdateGameNameWhenNotAPlayer(t *testing.T) {
 		And().UserUpdatesGameName(test.User2, "new name").
 		Then().ShouldFail("user is not a player")
 }
+
+func TestPlayersAreOrderedBySeats(t *testing.T) {
+	test.NewTestGame(t, test.NewSimpleGame(t, false)).
+		When().UserJoins(test.User3).
+		And().UserJoins(test.User1).
+		And().UserJoins(test.User2).
+		Then().ShouldHavePlayersOrder(test.User3, test.User1, test.User2).
+		When().UserLeaves(test.User1).
+		And().UserJoins(test.User1).
+		Then().ShouldHavePlayersOrder(test.User3, test.User2, test.User1)
+}
+
+func TestFacilitatorCanRearrangeSeats(t *testing.T) {
+	test.NewTestGame(t, test.NewFacilitatedGame(t, test.User1)).
+		When().UserJoins(test.User1).
+		And().UserJoins(test.User2).
+		And().UserJoins(test.User3).
+		And().UserRearrangesSeats(test.User1, 2, 0, 1).
+		Then().ShouldSucceed().
+		And().ShouldHavePlayersOrder(test.User3, test.User1, test.User2)
+}
+
+func TestOnlyFacilitatorCanRearrangeSeats(t *testing.T) {
+	test.NewTestGame(t, test.NewFacilitatedGame(t, test.User1)).
+		When().UserJoins(test.User1).
+		And().UserJoins(test.User2).
+		And().UserRearrangesSeats(test.User2, 1, 0).
+		Then().ShouldFail("user is not a facilitator").
+		And().ShouldHavePlayersOrder(test.User1, test.User2)
+}
+
+func TestCanNotRearrangeSeatsWithWrongOrder(t *testing.T) {
+	test.NewTestGame(t, test.NewFacilitatedGame(t, test.User1)).
+		When().UserJoins(test.User1).
+		And().UserJoins(test.User2).
+		And().UserRearrangesSeats(test.User1, 1).
+		Then().ShouldFail("all players should be seated").
+		When().UserRearrangesSeats(test.User1, 1, 1).
+		Then().ShouldFail("seats order should contain every player exactly once").
+		When().UserRearrangesSeats(test.User1, 0, 2).
+		Then().ShouldFail("seats order should contain every player exactly once")
+}
+
+func TestProtectedGameRequiresPasscode(t *testing.T) {
+	test.NewTestGame(t, test.NewProtectedGame(t, test.User1, "secret")).
+		When().UserJoins(test.User1).
+		Then().ShouldSucceed().
+		When().UserJoins(test.User2).
+		Then().ShouldFail("game passcode should be provided").
+		When().UserJoinsWithPasscode(test.User2, "wrong").
+		Then().ShouldFail("wrong game passcode").
+		When().UserJoinsWithPasscode(test.User2, "secret").
+		Then().ShouldSucceed().
+		When().UserJoins(test.User2).
+		Then().ShouldSucceed().
+		When().UserLeaves(test.User2).
+		And().UserJoins(test.User2).
+		Then().ShouldFail("game passcode should be provided")
+}
+
+func TestProtectedGameRequiresPasscodeFromSpectators(t *testing.T) {
+	test.NewTestGame(t, test.NewProtectedGame(t, test.User1, "secret")).
+		When().UserSpectates(test.User2, "").
+		Then().ShouldFail("game passcode should be provided").
+		When().UserSpectates(test.User2, "wrong").
+		Then().ShouldFail("wrong game passcode").
+		When().UserSpectates(test.User2, "secret").
+		Then().ShouldSucceed().
+		When().UserSpectates(test.User1, "").
+		Then().ShouldSucceed().
+		When().UserJoinsWithPasscode(test.User3, "secret").
+		And().UserSpectates(test.User3, "").
+		Then().ShouldSucceed()
+}
+
+func TestFacilitatorCanChangePasscode(t *testing.T) {
+	game := test.NewTestGame(t, test.NewProtectedGame(t, test.User1, "secret")).
+		When().UserJoins(test.User1).
+		And().UserJoinsWithPasscode(test.User2, "secret").
+		And().UserChangesPasscode(test.User2, "other").
+		Then().ShouldFail("user is not a facilitator").
+		When().UserChangesPasscode(test.User1, "rotated").
+		Then().ShouldSucceed().
+		When().UserJoinsWithPasscode(test.User3, "secret").
+		Then().ShouldFail("wrong game passcode").
+		When().UserJoinsWithPasscode(test.User3, "rotated").
+		Then().ShouldSucceed().
+		Instance()
+	assert.True(t, game.IsProtected())
+
+	test.NewTestGame(t, game).
+		When().UserChangesPasscode(test.User1, "").
+		Then().ShouldSucceed()
+	assert.False(t, game.IsProtected())
+}
+
+func TestPlayersHavePublicHandles(t *testing.T) {
+	game := test.NewTestGame(t, test.NewSimpleGame(t, false)).
+		When().UserJoins(test.User1).
+		And().UserJoins(test.User2).
+		Then().ShouldSucceed().
+		Instance()
+
+	handle1 := game.Players()[test.User1].Handle
+	handle2 := game.Players()[test.User2].Handle
+	assert.NotEmpty(t, handle1)
+	assert.NotEmpty(t, handle2)
+	assert.NotEqual(t, handle1, handle2)
+
+	test.NewTestGame(t, game).
+		When().UserJoins(test.User1).
+		Then().ShouldSucceed()
+	assert.Equal(t, handle1, game.Players()[test.User1].Handle)
+}
+
+func TestArchivedGameCanNotBePlayed(t *testing.T) {
+	game := test.NewTestGame(t, test.NewSimpleGame(t, true)).
+		When().UserJoins(test.User1).
+		Then().ShouldSucceed().
+		Instance()
+
+	game.Archive()
+
+	test.NewTestGame(t, game).
+		When().UserJoins(test.User2).
+		Then().ShouldFail("game is archived").
+		When().UserVotes(test.User1, "XS").
+		Then().ShouldFail("game is archived").
+		When().UserReveals(test.User1).
+		Then().ShouldFail("game is archived").
+		When().UserRestartsGame(test.User1).
+		Then().ShouldFail("game is archived").
+		When().UserRevotes(test.User1).
+		Then().ShouldFail("game is archived")
+}
+
+func TestGameActionsAreRecorded(t *testing.T) {
+	game := test.NewTestGame(t, test.NewSimpleGame(t, true)).
+		When().UserJoins(test.User1).
+		And().UserJoins(test.User2).
+		And().UserJoins(test.User1).
+		And().UserVotes(test.User1, "XS").
+		And().UserUpdatesGameName(test.User2, "new name").
+		And().UserReveals(test.User2).
+		Then().ShouldSucceed().
+		Instance()
+	game.ForceChanged()
+
+	actions := make([]games.Action, 0)
+	for _, e := range game.GetEvents() {
+		if e.EventType() == events.EventTypeRoundRevealed {
+			continue
+		}
+		assert.Equal(t, events.EventTypeGameUpdated, e.EventType())
+		if a, ok := e.Payload().(games.Action); ok {
+			actions = append(actions, a)
+		}
+	}
+
+	names := make([]string, len(actions))
+	for i, a := range actions {
+		names[i] = a.Name
+	}
+	// rejoin and forced changes are not actions
+	assert.Equal(t, []string{
+		games.ActionCreate, games.ActionJoin, games.ActionJoin, games.ActionVote, games.ActionUpdate, games.ActionReveal,
+	}, names)
+
+	update := actions[4]
+	assert.Equal(t, test.User2, update.ActorID)
+	assert.Equal(t, game.Players()[test.User2].Handle, update.ActorHandle)
+	assert.Equal(t, map[string]string{"name": ""}, update.Before)
+	assert.Equal(t, map[string]string{"name": "new name"}, update.After)
+}
+
+func TestRevealRecordsRound(t *testing.T) {
+	game := test.NewTestGame(t, test.NewSimpleGame(t, true)).
+		When().UserJoins(test.User1).
+		And().UserJoins(test.User2).
+		And().UserJoins(test.User3).
+		And().UserVotes(test.User2, "S").
+		And().UserVotes(test.User1, "XS").
+		And().UserUpdatesGameName(test.User1, "ticket").
+		And().UserReveals(test.User1).
+		And().UserReveals(test.User2).
+		Then().ShouldSucceed().
+		Instance()
+
+	rounds := make([]games.Round, 0)
+	for _, e := range game.GetEvents() {
+		if e.EventType() != events.EventTypeRoundRevealed {
+			continue
+		}
+		assert.Equal(t, game.ID(), e.AggregateID())
+		rounds = append(rounds, e.Payload().(games.Round))
+	}
+
+	// the second reveal does not make a new round
+	require.Len(t, rounds, 1)
+	assert.Equal(t, game.ID(), rounds[0].GameID)
+	assert.Equal(t, "ticket", rounds[0].TicketName)
+	assert.Equal(t, game.CardsDeck().Cards(), rounds[0].Cards)
+	assert.Equal(t, []games.RoundVote{
+		{UserID: test.User1, Card: "XS", Confidence: games.ConfidenceNormal},
+		{UserID: test.User2, Card: "S", Confidence: games.ConfidenceNormal},
+	}, rounds[0].Votes)
+	assert.False(t, rounds[0].RevealedAt.IsZero())
+}
+
+func TestAnonymousRoundIsMarked(t *testing.T) {
+	cmd, err := games.NewCreateGameCommand("", "", "", test.NewTestDeck(t), true)
+	require.NoError(t, err)
+	cmd.Anonymous = true
+
+	game := test.NewTestGame(t, games.NewGame(*cmd)).
+		When().UserJoins(test.User1).
+		And().UserVotes(test.User1, "XS").
+		And().UserReveals(test.User1).
+		Then().ShouldSucceed().
+		Instance()
+
+	assert.True(t, game.IsAnonymous())
+	for _, e := range game.GetEvents() {
+		if e.EventType() == events.EventTypeRoundRevealed {
+			assert.True(t, e.Payload().(games.Round).Anonymous)
+		}
+	}
+}
+
+func TestPlayersCanChat(t *testing.T) {
+	game := test.NewTestGame(t, test.NewSimpleGame(t, true)).
+		When().UserJoins(test.User1).
+		And().UserJoins(test.User2).
+		Then().ShouldSucceed().
+		Instance()
+	game.ClearEvents()
+
+	cmd, err := games.NewChatMessageCommand(game.ID(), test.User1, "hello")
+	require.NoError(t, err)
+	msg, err := game.Chat(*cmd)
+	require.NoError(t, err)
+	assert.Equal(t, game.Players()[test.User1].Handle, msg.SenderHandle)
+	assert.Equal(t, "hello", msg.Text)
+	assert.Empty(t, game.GetEvents(), "chat should not change the game")
+
+	cmd, err = games.NewReactionCommand(game.ID(), test.User2, "👍", game.Players()[test.User1].Handle)
+	require.NoError(t, err)
+	msg, err = game.Chat(*cmd)
+	require.NoError(t, err)
+	assert.Equal(t, game.Players()[test.User1].Handle, msg.TargetHandle)
+
+	cmd, err = games.NewReactionCommand(game.ID(), test.User2, "👍", "unknown")
+	require.NoError(t, err)
+	_, err = game.Chat(*cmd)
+	assert.ErrorIs(t, err, games.ErrUnknownChatTarget)
+
+	cmd, err = games.NewChatMessageCommand(game.ID(), test.User3, "hello")
+	require.NoError(t, err)
+	_, err = game.Chat(*cmd)
+	assert.ErrorIs(t, err, games.ErrNotAPlayer)
+
+	game.Archive()
+	cmd, err = games.NewChatMessageCommand(game.ID(), test.User1, "hello")
+	require.NoError(t, err)
+	_, err = game.Chat(*cmd)
+	assert.ErrorIs(t, err, games.ErrGameArchived)
+}
+
+func TestChatCanBeDisabled(t *testing.T) {
+	cmd, err := games.NewCreateGameCommand("", "", "", test.NewTestDeck(t), true)
+	require.NoError(t, err)
+	cmd.ChatDisabled = true
+
+	game := test.NewTestGame(t, games.NewGame(*cmd)).
+		When().UserJoins(test.User1).
+		Then().ShouldSucceed().
+		Instance()
+
+	chatCmd, err := games.NewChatMessageCommand(game.ID(), test.User1, "hello")
+	require.NoError(t, err)
+	_, err = game.Chat(*chatCmd)
+	assert.True(t, game.IsChatDisabled())
+	assert.ErrorIs(t, err, games.ErrChatDisabled)
+}
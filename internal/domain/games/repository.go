@@ -1,9 +1,17 @@
 package games
 
+import (
+	"context"
+	"time"
+)
+
 // GameRepository is a repository contract to fetch/persist games.
 type GameRepository interface {
-	ModifyExclusively(id string, cb func(game *Game) error) error
-	Get(id string) (*Game, error)
-	Save(game *Game) error
-	GetActiveGamesByPlayerID(playerID string) ([]Game, error)
+	ModifyExclusively(ctx context.Context, id string, cb func(game *Game) error) error
+	Get(ctx context.Context, id string) (*Game, error)
+	Save(ctx context.Context, game *Game) error
+	Delete(ctx context.Context, id string) error
+	GetActiveGamesByPlayerID(ctx context.Context, playerID string) ([]Game, error)
+	CountGamesByFacilitatorID(ctx context.Context, facilitatorID string) (int, error)
+	GetIdleGameIDs(ctx context.Context, since time.Time) ([]string, error)
 }
@@ -0,0 +1,95 @@
+package games
+
+import "time"
+
+// Round is a revealed voting round of a ticket, it is the payload of the round revealed event.
+type Round struct {
+	GameID string
+	TeamID string
+	// TicketName and TicketURL identify the estimated ticket, they are the game name and URL at the time of reveal.
+	TicketName string
+	TicketURL  string
+	// Cards are the game deck cards in the deck order, they are empty for dimensional decks.
+	Cards      []Card
+	Votes      []RoundVote
+	RevealedAt time.Time
+	// Anonymous is true for rounds of anonymous games, their votes should not be reported per player.
+	Anonymous bool
+}
+
+// RoundVote is a card of the player in the revealed round.
+type RoundVote struct {
+	UserID string
+	Card   Card
+	// Cards are cards voted per dimension name in dimensional decks.
+	Cards      map[string]Card
+	Confidence string
+}
+
+// round creates the round of the current game cards, votes are ordered by seats.
+func (g *Game) round() Round {
+	r := Round{
+		GameID:     g.id,
+		TeamID:     g.teamID,
+		TicketName: g.name,
+		TicketURL:  g.ticketURL,
+		Cards:      g.cardsDeck.Cards(),
+		Votes:      make([]RoundVote, 0, len(g.players)),
+		RevealedAt: time.Now(),
+		Anonymous:  g.anonymous,
+	}
+
+	for _, id := range g.PlayerIDs() {
+		p := g.players[id]
+		if !p.Voted() {
+			continue
+		}
+		r.Votes = append(r.Votes, RoundVote{UserID: id, Card: p.card(), Cards: p.VotedCards, Confidence: p.Confidence})
+	}
+
+	return r
+}
+
+// Attempt is an archived voting attempt of the current ticket, it is kept on re-vote.
+type Attempt struct {
+	// Number is a sequential attempt number starting from 1.
+	Number int
+	Votes  []AttemptVote
+}
+
+// AttemptVote is a card of the player in the archived attempt.
+type AttemptVote struct {
+	UserID string
+	// Handle is a public handle of the player, the player might leave the game after the attempt.
+	Handle string
+	Card   Card
+	// Cards are cards voted per dimension name in dimensional decks.
+	Cards map[string]Card
+}
+
+// attempt creates the next attempt of the current game cards, votes are ordered by seats.
+func (g *Game) attempt() Attempt {
+	a := Attempt{Number: 1, Votes: make([]AttemptVote, 0, len(g.players))}
+	if len(g.attempts) > 0 {
+		a.Number = g.attempts[len(g.attempts)-1].Number + 1
+	}
+
+	for _, id := range g.PlayerIDs() {
+		p := g.players[id]
+		if !p.Voted() {
+			continue
+		}
+		a.Votes = append(a.Votes, AttemptVote{UserID: id, Handle: p.Handle, Card: p.card(), Cards: p.VotedCards})
+	}
+
+	return a
+}
+
+// card returns the voted card, it is empty for dimensional votes.
+func (p Player) card() Card {
+	if p.VotedCard == nil {
+		return ""
+	}
+
+	return *p.VotedCard
+}
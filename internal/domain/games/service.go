@@ -1,28 +1,67 @@
 package games
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/sirupsen/logrus"
+
+	"planningpoker/internal/domain"
 	"planningpoker/internal/domain/events"
 )
 
+// Limits caps resources used by users, zero values mean no limit.
+type Limits struct {
+	// MaxGamesPerUser is the maximal number of not archived games facilitated by a user.
+	MaxGamesPerUser int
+	// MaxPlayersPerGame is the maximal number of players in a game.
+	MaxPlayersPerGame int
+}
+
+// Commander is a contract of the game commands handling, implemented by Service and wrapped by the infra decorators.
+type Commander interface {
+	Create(ctx context.Context, cmd CreateGameCommand) (string, error)
+	Join(ctx context.Context, cmd JoinGameCommand) error
+	Leave(ctx context.Context, cmd LeaveGameCommand) error
+	Update(ctx context.Context, cmd UpdateGameCommand) error
+	Vote(ctx context.Context, cmd VoteCommand) error
+	UnVote(ctx context.Context, cmd UnVoteCommand) error
+	Reveal(ctx context.Context, cmd RevealCardsCommand) error
+	Restart(ctx context.Context, cmd RestartGameCommand) error
+	Revote(ctx context.Context, cmd RevoteCommand) error
+	Rearrange(ctx context.Context, cmd RearrangeSeatsCommand) error
+	ChangePasscode(ctx context.Context, cmd ChangePasscodeCommand) error
+	Spectate(ctx context.Context, cmd JoinGameCommand) error
+	Chat(ctx context.Context, cmd ChatCommand) (*ChatMessage, error)
+}
+
 // Service is the game related application service.
 type Service struct {
 	gamesRepo GameRepository
+	eventBus  events.EventBus
+	limits    Limits
+	logger    *logrus.Entry
 }
 
 // NewService creates a new game domain service instance.
-func NewService(gr GameRepository, eb events.EventBus) (*Service, error) {
+func NewService(gr GameRepository, eb events.EventBus, limits Limits, logger *logrus.Entry) (*Service, error) {
 	if gr == nil {
 		return nil, errors.New("games repository should be provided")
 	}
 	if eb == nil {
 		return nil, errors.New("event bus should be provided")
 	}
+	if logger == nil {
+		return nil, errors.New("logger should be provided")
+	}
 
 	gs := &Service{
 		gamesRepo: gr,
+		eventBus:  eb,
+		limits:    limits,
+		logger:    logger,
 	}
 	eb.Subscribe(gs.processUserUpdated, events.EventTypeUserUpdated)
 
@@ -30,7 +69,11 @@ func NewService(gr GameRepository, eb events.EventBus) (*Service, error) {
 }
 
 // Create creates a game.
-func (s *Service) Create(cmd CreateGameCommand) (string, error) {
+func (s *Service) Create(ctx context.Context, cmd CreateGameCommand) (string, error) {
+	if err := s.checkGamesLimit(ctx, cmd.UserID); err != nil {
+		return "", err
+	}
+
 	game := NewGame(cmd)
 
 	joinCmd, err := NewJoinGameCommand(game.id, cmd.UserID)
@@ -41,7 +84,7 @@ func (s *Service) Create(cmd CreateGameCommand) (string, error) {
 	if err := game.Join(*joinCmd); err != nil {
 		return "", err
 	}
-	if err := s.gamesRepo.Save(game); err != nil {
+	if err := s.gamesRepo.Save(ctx, game); err != nil {
 		return "", err
 	}
 
@@ -49,67 +92,192 @@ func (s *Service) Create(cmd CreateGameCommand) (string, error) {
 }
 
 // Update updates a game.
-func (s *Service) Update(cmd UpdateGameCommand) error {
-	return s.gamesRepo.ModifyExclusively(cmd.GameID, func(game *Game) error {
+func (s *Service) Update(ctx context.Context, cmd UpdateGameCommand) error {
+	return s.gamesRepo.ModifyExclusively(ctx, cmd.GameID, func(game *Game) error {
 		return game.Update(cmd)
 	})
 }
 
 // Join adds a player to the game.
-func (s *Service) Join(cmd JoinGameCommand) error {
-	return s.gamesRepo.ModifyExclusively(cmd.GameID, func(game *Game) error {
+func (s *Service) Join(ctx context.Context, cmd JoinGameCommand) error {
+	return s.gamesRepo.ModifyExclusively(ctx, cmd.GameID, func(game *Game) error {
+		if s.limits.MaxPlayersPerGame > 0 && !game.IsPlayer(cmd.UserID) && len(game.Players()) >= s.limits.MaxPlayersPerGame {
+			return ErrGameIsFull
+		}
+
 		return game.Join(cmd)
 	})
 }
 
+// Spectate checks that the user can watch the game without joining it, the game is not modified.
+func (s *Service) Spectate(ctx context.Context, cmd JoinGameCommand) error {
+	game, err := s.gamesRepo.Get(ctx, cmd.GameID)
+	if err != nil {
+		return fmt.Errorf("game fetching: %w", err)
+	}
+	if game == nil {
+		return ErrGameNotFound
+	}
+
+	return game.CanSpectate(cmd)
+}
+
+// ChangePasscode rotates or removes the game join passcode.
+func (s *Service) ChangePasscode(ctx context.Context, cmd ChangePasscodeCommand) error {
+	return s.gamesRepo.ModifyExclusively(ctx, cmd.GameID, func(game *Game) error {
+		return game.ChangePasscode(cmd)
+	})
+}
+
+// Rearrange changes players seats.
+func (s *Service) Rearrange(ctx context.Context, cmd RearrangeSeatsCommand) error {
+	return s.gamesRepo.ModifyExclusively(ctx, cmd.GameID, func(game *Game) error {
+		return game.Rearrange(cmd)
+	})
+}
+
 // Leave forces a player to leave the game.
-func (s *Service) Leave(cmd LeaveGameCommand) error {
-	return s.gamesRepo.ModifyExclusively(cmd.GameID, func(game *Game) error {
+func (s *Service) Leave(ctx context.Context, cmd LeaveGameCommand) error {
+	return s.gamesRepo.ModifyExclusively(ctx, cmd.GameID, func(game *Game) error {
 		return game.Leave(cmd)
 	})
 }
 
 // Restart restarts the game.
-func (s *Service) Restart(cmd RestartGameCommand) error {
-	return s.gamesRepo.ModifyExclusively(cmd.GameID, func(game *Game) error {
+func (s *Service) Restart(ctx context.Context, cmd RestartGameCommand) error {
+	return s.gamesRepo.ModifyExclusively(ctx, cmd.GameID, func(game *Game) error {
 		return game.Restart(cmd)
 	})
 }
 
+// Revote starts voting on the same ticket again, the revealed votes are kept as an attempt.
+func (s *Service) Revote(ctx context.Context, cmd RevoteCommand) error {
+	return s.gamesRepo.ModifyExclusively(ctx, cmd.GameID, func(game *Game) error {
+		return game.Revote(cmd)
+	})
+}
+
 // Vote performs player voting.
-func (s *Service) Vote(cmd VoteCommand) error {
-	return s.gamesRepo.ModifyExclusively(cmd.GameID, func(game *Game) error {
+func (s *Service) Vote(ctx context.Context, cmd VoteCommand) error {
+	return s.gamesRepo.ModifyExclusively(ctx, cmd.GameID, func(game *Game) error {
 		return game.Vote(cmd)
 	})
 }
 
 // UnVote removes a player vote.
-func (s *Service) UnVote(cmd UnVoteCommand) error {
-	return s.gamesRepo.ModifyExclusively(cmd.GameID, func(game *Game) error {
+func (s *Service) UnVote(ctx context.Context, cmd UnVoteCommand) error {
+	return s.gamesRepo.ModifyExclusively(ctx, cmd.GameID, func(game *Game) error {
 		return game.UnVote(cmd)
 	})
 }
 
 // Reveal opens all cards and stops the game.
-func (s *Service) Reveal(cmd RevealCardsCommand) error {
-	return s.gamesRepo.ModifyExclusively(cmd.GameID, func(game *Game) error {
+func (s *Service) Reveal(ctx context.Context, cmd RevealCardsCommand) error {
+	return s.gamesRepo.ModifyExclusively(ctx, cmd.GameID, func(game *Game) error {
 		return game.Reveal(cmd)
 	})
 }
 
-func (s *Service) processUserUpdated(e events.DomainEvent) {
-	list, err := s.gamesRepo.GetActiveGamesByPlayerID(e.AggregateID())
+// Chat checks that the player can send the message to the game, the message itself is not persisted.
+func (s *Service) Chat(ctx context.Context, cmd ChatCommand) (*ChatMessage, error) {
+	game, err := s.gamesRepo.Get(ctx, cmd.GameID)
+	if err != nil {
+		return nil, fmt.Errorf("game fetching: %w", err)
+	}
+	if game == nil {
+		return nil, ErrGameNotFound
+	}
+
+	return game.Chat(cmd)
+}
+
+// CleanupIdle archives games which were idle longer than idleFor, so connected players are notified,
+// and deletes archived games which stayed idle for the same period after archiving.
+func (s *Service) CleanupIdle(ctx context.Context, idleFor time.Duration) error {
+	since := time.Now().Add(-idleFor)
+
+	ids, err := s.gamesRepo.GetIdleGameIDs(ctx, since)
 	if err != nil {
-		fmt.Printf("error fetching games for player id=%s: %v", e.AggregateID(), err)
+		return fmt.Errorf("idle games fetching: %w", err)
+	}
+
+	for _, id := range ids {
+		game, err := s.gamesRepo.Get(ctx, id)
+		if err != nil {
+			return fmt.Errorf("game fetching: %w", err)
+		}
+		if game == nil || !game.IsIdleSince(since) {
+			continue
+		}
+
+		if game.State() == GameStateArchived {
+			// team games are kept archived, so members can look back at their past games
+			if game.TeamID() != "" {
+				continue
+			}
+			if err := s.gamesRepo.Delete(ctx, id); err != nil {
+				return fmt.Errorf("game deletion: %w", err)
+			}
+			s.publishDeleted(ctx, id)
+			continue
+		}
+
+		err = s.gamesRepo.ModifyExclusively(ctx, id, func(game *Game) error {
+			// the game might have been modified since it was fetched
+			if game.IsIdleSince(since) {
+				game.Archive()
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("game archiving: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// checkGamesLimit checks that the user can create one more game.
+// The check is not atomic with the creation, so concurrent requests might slightly exceed the limit.
+func (s *Service) checkGamesLimit(ctx context.Context, userID string) error {
+	if s.limits.MaxGamesPerUser <= 0 {
+		return nil
+	}
+
+	created, err := s.gamesRepo.CountGamesByFacilitatorID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("facilitated games counting: %w", err)
+	}
+	if created >= s.limits.MaxGamesPerUser {
+		return ErrTooManyGames
+	}
+
+	return nil
+}
+
+func (s *Service) processUserUpdated(ctx context.Context, e events.DomainEvent) {
+	logger := s.logger.WithContext(ctx).WithField(domain.LogFieldUserID, e.AggregateID())
+
+	list, err := s.gamesRepo.GetActiveGamesByPlayerID(ctx, e.AggregateID())
+	if err != nil {
+		logger.WithError(err).Error("unable to fetch games of the player")
 	}
 
 	for _, g := range list {
-		err := s.gamesRepo.ModifyExclusively(g.id, func(g *Game) error {
+		err := s.gamesRepo.ModifyExclusively(ctx, g.id, func(g *Game) error {
 			g.ForceChanged()
 			return nil
 		})
 		if err != nil {
-			fmt.Printf("no way to update the game, will be updated eventually")
+			logger.WithError(err).WithField(domain.LogFieldGameID, g.id).Warn("unable to update the game, it will be updated eventually")
 		}
 	}
 }
+
+// publishDeleted notifies consumers keeping data of the game, e.g. the round history, that the game is gone.
+func (s *Service) publishDeleted(ctx context.Context, id string) {
+	e := events.NewDomainEventBuilder(events.EventTypeGameDeleted).ForAggregate(id).Build()
+	if err := s.eventBus.Publish(ctx, e); err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField(domain.LogFieldGameID, id).Error("unable to publish a game event")
+	}
+}
@@ -1,13 +1,16 @@
 package games_test
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"planningpoker/internal/domain/events"
 	"planningpoker/internal/domain/games"
 	"planningpoker/test"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -18,27 +21,36 @@ func TestNewService(t *testing.T) {
 	testCases := map[string]struct {
 		gameRepo games.GameRepository
 		eventBus events.EventBus
+		logger   *logrus.Entry
 		expError string
 	}{
 		"success": {
 			gameRepo: gamesRepoStub{},
 			eventBus: eventBusStub{},
+			logger:   test.NewLogger(),
 			expError: "",
 		},
 		"fail on no game repo": {
 			eventBus: eventBusStub{},
+			logger:   test.NewLogger(),
 			expError: "games repository should be provided",
 		},
 		"fail on no event bus": {
 			gameRepo: gamesRepoStub{},
+			logger:   test.NewLogger(),
 			expError: "event bus should be provided",
 		},
+		"fail on no logger": {
+			gameRepo: gamesRepoStub{},
+			eventBus: eventBusStub{},
+			expError: "logger should be provided",
+		},
 	}
 	for name, tt := range testCases {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			srv, err := games.NewService(tt.gameRepo, tt.eventBus)
+			srv, err := games.NewService(tt.gameRepo, tt.eventBus, games.Limits{}, tt.logger)
 
 			if tt.expError != "" {
 				assert.EqualError(t, err, tt.expError)
@@ -56,12 +68,27 @@ func TestGamesService_Create(t *testing.T) {
 
 	testCases := map[string]struct {
 		gameRepo games.GameRepository
+		limits   games.Limits
 		expError string
 	}{
 		"success": {
 			gameRepo: gamesRepoStub{},
 			expError: "",
 		},
+		"success under the games limit": {
+			gameRepo: gamesRepoStub{facilitatedCount: 1},
+			limits:   games.Limits{MaxGamesPerUser: 2},
+		},
+		"fail on too many games": {
+			gameRepo: gamesRepoStub{facilitatedCount: 2},
+			limits:   games.Limits{MaxGamesPerUser: 2},
+			expError: "user has too many active games",
+		},
+		"fail on facilitated games counting error": {
+			gameRepo: gamesRepoStub{countFacilitatedErr: errors.New("failed")},
+			limits:   games.Limits{MaxGamesPerUser: 2},
+			expError: "facilitated games counting: failed",
+		},
 		"fail on repo error": {
 			gameRepo: gamesRepoStub{saveError: errors.New("save failed")},
 			expError: "save failed",
@@ -72,13 +99,13 @@ func TestGamesService_Create(t *testing.T) {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			srv, err := games.NewService(tt.gameRepo, eventBusStub{})
+			srv, err := games.NewService(tt.gameRepo, eventBusStub{}, tt.limits, test.NewLogger())
 			require.NoError(t, err)
 
 			cmd, err := games.NewCreateGameCommand("foo", "http://example.com", test.User1, test.NewTestDeck(t), true)
 			require.NoError(t, err)
 
-			id, err := srv.Create(*cmd)
+			id, err := srv.Create(context.Background(), *cmd)
 
 			if tt.expError != "" {
 				assert.EqualError(t, err, tt.expError)
@@ -112,13 +139,13 @@ func TestGamesService_Update(t *testing.T) {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			srv, err := games.NewService(tt.gameRepo, eventBusStub{})
+			srv, err := games.NewService(tt.gameRepo, eventBusStub{}, games.Limits{}, test.NewLogger())
 			require.NoError(t, err)
 
 			cmd, err := games.NewUpdateGameCommand("anything", "new name", "https://ex.com", test.User1)
 			require.NoError(t, err)
 
-			err = srv.Update(*cmd)
+			err = srv.Update(context.Background(), *cmd)
 
 			if tt.expError != "" {
 				assert.EqualError(t, err, tt.expError)
@@ -150,13 +177,13 @@ func TestGamesService_Restart(t *testing.T) {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			srv, err := games.NewService(tt.gameRepo, eventBusStub{})
+			srv, err := games.NewService(tt.gameRepo, eventBusStub{}, games.Limits{}, test.NewLogger())
 			require.NoError(t, err)
 
 			cmd, err := games.NewRestartGameCommand("anything", test.User1)
 			require.NoError(t, err)
 
-			err = srv.Restart(*cmd)
+			err = srv.Restart(context.Background(), *cmd)
 
 			if tt.expError != "" {
 				assert.EqualError(t, err, tt.expError)
@@ -190,13 +217,13 @@ func TestGamesService_Vote(t *testing.T) {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			srv, err := games.NewService(tt.gameRepo, eventBusStub{})
+			srv, err := games.NewService(tt.gameRepo, eventBusStub{}, games.Limits{}, test.NewLogger())
 			require.NoError(t, err)
 
 			cmd, err := games.NewVoteCommand("anything", test.User1, *card, games.ConfidenceNormal)
 			require.NoError(t, err)
 
-			err = srv.Vote(*cmd)
+			err = srv.Vote(context.Background(), *cmd)
 
 			if tt.expError != "" {
 				assert.EqualError(t, err, tt.expError)
@@ -228,13 +255,13 @@ func TestGamesService_UnVote(t *testing.T) {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			srv, err := games.NewService(tt.gameRepo, eventBusStub{})
+			srv, err := games.NewService(tt.gameRepo, eventBusStub{}, games.Limits{}, test.NewLogger())
 			require.NoError(t, err)
 
 			cmd, err := games.NewUnVoteCommand("anything", test.User1)
 			require.NoError(t, err)
 
-			err = srv.UnVote(*cmd)
+			err = srv.UnVote(context.Background(), *cmd)
 
 			if tt.expError != "" {
 				assert.EqualError(t, err, tt.expError)
@@ -262,13 +289,13 @@ func TestGamesService_Leave(t *testing.T) {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			srv, err := games.NewService(tt.gameRepo, eventBusStub{})
+			srv, err := games.NewService(tt.gameRepo, eventBusStub{}, games.Limits{}, test.NewLogger())
 			require.NoError(t, err)
 
 			cmd, err := games.NewLeaveGameCommand("anything", test.User1)
 			require.NoError(t, err)
 
-			err = srv.Leave(*cmd)
+			err = srv.Leave(context.Background(), *cmd)
 
 			if tt.expError != "" {
 				assert.EqualError(t, err, tt.expError)
@@ -284,25 +311,35 @@ func TestGamesService_Join(t *testing.T) {
 
 	testCases := map[string]struct {
 		gameRepo games.GameRepository
+		limits   games.Limits
 		expError string
 	}{
 		"success": {
 			gameRepo: gamesRepoStub{game: newTestServiceGame(t).UserJoins(test.User1).Instance()},
 			expError: "",
 		},
+		"success on rejoin to the full game": {
+			gameRepo: gamesRepoStub{game: newTestServiceGame(t).UserJoins(test.User2).Instance()},
+			limits:   games.Limits{MaxPlayersPerGame: 1},
+		},
+		"fail on the full game": {
+			gameRepo: gamesRepoStub{game: newTestServiceGame(t).UserJoins(test.User1).Instance()},
+			limits:   games.Limits{MaxPlayersPerGame: 1},
+			expError: "game has the maximal number of players",
+		},
 	}
 
 	for name, tt := range testCases {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			srv, err := games.NewService(tt.gameRepo, eventBusStub{})
+			srv, err := games.NewService(tt.gameRepo, eventBusStub{}, tt.limits, test.NewLogger())
 			require.NoError(t, err)
 
 			cmd, err := games.NewJoinGameCommand("anything", test.User2)
 			require.NoError(t, err)
 
-			err = srv.Join(*cmd)
+			err = srv.Join(context.Background(), *cmd)
 
 			if tt.expError != "" {
 				assert.EqualError(t, err, tt.expError)
@@ -334,13 +371,146 @@ func TestGamesService_Reveal(t *testing.T) {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			srv, err := games.NewService(tt.gameRepo, eventBusStub{})
+			srv, err := games.NewService(tt.gameRepo, eventBusStub{}, games.Limits{}, test.NewLogger())
 			require.NoError(t, err)
 
 			cmd, err := games.NewRevealCardsCommand("anything", test.User1)
 			require.NoError(t, err)
 
-			err = srv.Reveal(*cmd)
+			err = srv.Reveal(context.Background(), *cmd)
+
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGamesService_Chat(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		gameRepo games.GameRepository
+		expError string
+	}{
+		"success": {
+			gameRepo: gamesRepoStub{game: newTestServiceGame(t).UserJoins(test.User1).Instance()},
+		},
+		"fail on not a player": {
+			gameRepo: gamesRepoStub{game: newTestServiceGame(t).Instance()},
+			expError: "user is not a player",
+		},
+		"fail on missing game": {
+			gameRepo: gamesRepoStub{},
+			expError: "game not found",
+		},
+		"fail on repository error": {
+			gameRepo: gamesRepoStub{getErr: errors.New("error")},
+			expError: "game fetching: error",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			srv, err := games.NewService(tt.gameRepo, eventBusStub{}, games.Limits{}, test.NewLogger())
+			require.NoError(t, err)
+
+			cmd, err := games.NewChatMessageCommand("anything", test.User1, "hello")
+			require.NoError(t, err)
+
+			msg, err := srv.Chat(context.Background(), *cmd)
+
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "hello", msg.Text)
+		})
+	}
+}
+
+func TestGamesService_Spectate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		gameRepo games.GameRepository
+		passcode string
+		expError string
+	}{
+		"success": {
+			gameRepo: gamesRepoStub{game: test.NewProtectedGame(t, test.User1, "secret")},
+			passcode: "secret",
+		},
+		"fail on wrong passcode": {
+			gameRepo: gamesRepoStub{game: test.NewProtectedGame(t, test.User1, "secret")},
+			passcode: "wrong",
+			expError: "wrong game passcode",
+		},
+		"fail on missing game": {
+			gameRepo: gamesRepoStub{},
+			expError: "game not found",
+		},
+		"fail on repository error": {
+			gameRepo: gamesRepoStub{getErr: errors.New("error")},
+			expError: "game fetching: error",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			srv, err := games.NewService(tt.gameRepo, eventBusStub{}, games.Limits{}, test.NewLogger())
+			require.NoError(t, err)
+
+			cmd, err := games.NewJoinGameCommand("anything", test.User2)
+			require.NoError(t, err)
+			cmd.Passcode = tt.passcode
+
+			err = srv.Spectate(context.Background(), *cmd)
+
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGamesService_Rearrange(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		gameRepo games.GameRepository
+		expError string
+	}{
+		"success": {
+			gameRepo: gamesRepoStub{game: test.NewTestGame(t, test.NewFacilitatedGame(t, test.User1)).UserJoins(test.User1).Instance()},
+			expError: "",
+		},
+		"fail on error": {
+			gameRepo: gamesRepoStub{game: newTestServiceGame(t).UserJoins(test.User1).Instance()},
+			expError: "user is not a facilitator",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			srv, err := games.NewService(tt.gameRepo, eventBusStub{}, games.Limits{}, test.NewLogger())
+			require.NoError(t, err)
+
+			cmd, err := games.NewRearrangeSeatsCommand("anything", test.User1, []int{0})
+			require.NoError(t, err)
+
+			err = srv.Rearrange(context.Background(), *cmd)
 
 			if tt.expError != "" {
 				assert.EqualError(t, err, tt.expError)
@@ -351,37 +521,150 @@ func TestGamesService_Reveal(t *testing.T) {
 	}
 }
 
+func TestGamesService_CleanupIdle(t *testing.T) {
+	t.Parallel()
+
+	idleFor := time.Hour
+	longAgo := time.Now().Add(-2 * idleFor)
+
+	testCases := map[string]struct {
+		gameRepo gamesRepoStub
+		expState string
+		expError string
+	}{
+		"success archive idle game": {
+			gameRepo: gamesRepoStub{
+				game:        newIdleGame(t, games.GameStateStarted, longAgo),
+				idleGameIDs: []string{"id"},
+			},
+			expState: games.GameStateArchived,
+		},
+		"success skip game with recent activity": {
+			gameRepo: gamesRepoStub{
+				game:        newIdleGame(t, games.GameStateStarted, time.Now()),
+				idleGameIDs: []string{"id"},
+			},
+			expState: games.GameStateStarted,
+		},
+		"success delete archived game": {
+			gameRepo: gamesRepoStub{
+				game:        newIdleGame(t, games.GameStateArchived, longAgo),
+				idleGameIDs: []string{"id"},
+				deleteErr:   errors.New("delete failed"),
+			},
+			expState: games.GameStateArchived,
+			expError: "game deletion: delete failed",
+		},
+		"success keep archived team game": {
+			gameRepo: gamesRepoStub{
+				game: games.NewRaw("id", "name", "", test.NewTestDeck(t), map[string]*games.Player{},
+					games.GameStateArchived, false, "", longAgo, nil, "team-id", nil, 0, false, false),
+				idleGameIDs: []string{"id"},
+				deleteErr:   errors.New("delete failed"),
+			},
+			expState: games.GameStateArchived,
+		},
+		"fail on idle games fetching": {
+			gameRepo: gamesRepoStub{
+				game:       newIdleGame(t, games.GameStateStarted, longAgo),
+				getIdleErr: errors.New("fetch failed"),
+			},
+			expState: games.GameStateStarted,
+			expError: "idle games fetching: fetch failed",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			srv, err := games.NewService(tt.gameRepo, eventBusStub{}, games.Limits{}, test.NewLogger())
+			require.NoError(t, err)
+
+			err = srv.CleanupIdle(context.Background(), idleFor)
+
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expState, tt.gameRepo.game.State())
+		})
+	}
+}
+
+func TestGamesService_CleanupIdlePublishesDeleted(t *testing.T) {
+	t.Parallel()
+
+	bus := &test.EventsRecorder{}
+	repo := gamesRepoStub{
+		game:        newIdleGame(t, games.GameStateArchived, time.Now().Add(-2*time.Hour)),
+		idleGameIDs: []string{"id"},
+	}
+	srv, err := games.NewService(repo, bus, games.Limits{}, test.NewLogger())
+	require.NoError(t, err)
+
+	require.NoError(t, srv.CleanupIdle(context.Background(), time.Hour))
+
+	list := bus.Events()
+	require.Len(t, list, 1)
+	assert.Equal(t, events.EventTypeGameDeleted, list[0].EventType())
+	assert.Equal(t, "id", list[0].AggregateID())
+}
+
+func newIdleGame(t *testing.T, state string, lastActivityAt time.Time) *games.Game {
+	return games.NewRaw("id", "name", "", test.NewTestDeck(t), map[string]*games.Player{}, state, false, "", lastActivityAt, nil, "", nil, 0, false, false)
+}
+
 type gamesRepoStub struct {
 	game              *games.Game
 	getErr            error
 	saveError         error
 	activeGames       []games.Game
 	getActiveGamesErr error
+	deleteErr         error
+	idleGameIDs       []string
+	getIdleErr        error
+
+	facilitatedCount    int
+	countFacilitatedErr error
 }
 
-func (g gamesRepoStub) ModifyExclusively(_ string, cb func(game *games.Game) error) error {
+func (g gamesRepoStub) ModifyExclusively(_ context.Context, _ string, cb func(game *games.Game) error) error {
 	return cb(g.game)
 }
 
-func (g gamesRepoStub) Get(string) (*games.Game, error) {
+func (g gamesRepoStub) Get(context.Context, string) (*games.Game, error) {
 	return g.game, g.getErr
 }
 
-func (g gamesRepoStub) Save(*games.Game) error {
+func (g gamesRepoStub) Save(context.Context, *games.Game) error {
 	return g.saveError
 }
 
-func (g gamesRepoStub) GetActiveGamesByPlayerID(string) ([]games.Game, error) {
+func (g gamesRepoStub) Delete(context.Context, string) error {
+	return g.deleteErr
+}
+
+func (g gamesRepoStub) GetActiveGamesByPlayerID(context.Context, string) ([]games.Game, error) {
 	return g.activeGames, g.getActiveGamesErr
 }
 
+func (g gamesRepoStub) CountGamesByFacilitatorID(context.Context, string) (int, error) {
+	return g.facilitatedCount, g.countFacilitatedErr
+}
+
+func (g gamesRepoStub) GetIdleGameIDs(context.Context, time.Time) ([]string, error) {
+	return g.idleGameIDs, g.getIdleErr
+}
+
 func newTestServiceGame(t *testing.T) *test.Game {
 	return test.NewTestGame(t, test.NewSimpleGame(t, true))
 }
 
 type eventBusStub struct{}
 
-func (e eventBusStub) Publish(events.DomainEvent) error {
+func (e eventBusStub) Publish(context.Context, events.DomainEvent) error {
 	return nil
 }
 
@@ -0,0 +1,15 @@
+package domain
+
+// Log field names of aggregate IDs, infra logging uses the same names, so entries can be correlated by them.
+const (
+	// LogFieldGameID is a game ID field.
+	LogFieldGameID = "game_id"
+	// LogFieldUserID is a user ID field.
+	LogFieldUserID = "user_id"
+	// LogFieldTeamID is a team ID field.
+	LogFieldTeamID = "team_id"
+	// LogFieldRoomID is a room ID field.
+	LogFieldRoomID = "room_id"
+	// LogFieldBatchID is a voting batch ID field.
+	LogFieldBatchID = "batch_id"
+)
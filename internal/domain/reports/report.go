@@ -0,0 +1,229 @@
+// Package reports contains estimation reports built from the history of revealed rounds.
+package reports
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"planningpoker/internal/domain/games"
+)
+
+// OutlierDistance is a distance in deck cards from the round median, votes further than that are outliers.
+const OutlierDistance = 1.0
+
+// Session is a game which rounds are reported.
+type Session struct {
+	GameID string
+	Name   string
+}
+
+// Report is an estimation report of a team or a room.
+type Report struct {
+	// Sessions are reported sessions with at least one revealed round ordered by the first reveal.
+	Sessions []SessionReport
+	Tickets  int
+	// FirstRoundConsensusRate is a share of tickets which reached consensus in their first round, from 0 to 1.
+	FirstRoundConsensusRate float64
+	// Players are ordered by the number of rounds they voted in, the most active first.
+	// Rounds of anonymous games are not counted for players.
+	Players []PlayerReport
+}
+
+// SessionReport is a summary of a single session.
+type SessionReport struct {
+	GameID  string
+	Name    string
+	Rounds  int
+	Tickets int
+	// Points is a sum of ticket estimates, only numeric estimates are summed up.
+	Points        float64
+	FirstRevealAt time.Time
+	LastRevealAt  time.Time
+}
+
+// PlayerReport is a summary of the player votes.
+type PlayerReport struct {
+	UserID string
+	Rounds int
+	// AverageSpread is an average distance in deck cards between the player vote and the round median.
+	AverageSpread float64
+	// OutlierRate is a share of rounds where the vote was further than OutlierDistance from the median, from 0 to 1.
+	OutlierRate float64
+}
+
+type ticket struct {
+	name string
+	url  string
+}
+
+type playerStats struct {
+	rounds   int
+	spread   float64
+	outliers int
+}
+
+// Build creates the report of the sessions from their rounds, rounds of other games are ignored.
+// Rounds of a session are grouped into tickets by the ticket name and URL, the first round is the earliest one
+// and the ticket estimate is the most voted card of the latest one.
+func Build(sessions []Session, rounds []games.Round) Report {
+	byGame := make(map[string][]games.Round)
+	for _, r := range rounds {
+		byGame[r.GameID] = append(byGame[r.GameID], r)
+	}
+
+	report := Report{Sessions: make([]SessionReport, 0, len(sessions)), Players: make([]PlayerReport, 0)}
+	players := make(map[string]*playerStats)
+	firstRounds, consensuses := 0, 0
+
+	for _, s := range sessions {
+		list := byGame[s.GameID]
+		if len(list) == 0 {
+			continue
+		}
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].RevealedAt.Before(list[j].RevealedAt)
+		})
+
+		sr := SessionReport{
+			GameID:        s.GameID,
+			Name:          s.Name,
+			Rounds:        len(list),
+			FirstRevealAt: list[0].RevealedAt,
+			LastRevealAt:  list[len(list)-1].RevealedAt,
+		}
+
+		tickets := make(map[ticket][]games.Round)
+		order := make([]ticket, 0)
+		for _, r := range list {
+			key := ticket{name: r.TicketName, url: r.TicketURL}
+			if _, ok := tickets[key]; !ok {
+				order = append(order, key)
+			}
+			tickets[key] = append(tickets[key], r)
+
+			// votes of anonymous games are never attributed to players
+			if r.Anonymous {
+				continue
+			}
+			a := analyze(r)
+			for uid, pos := range a.positions {
+				if players[uid] == nil {
+					players[uid] = &playerStats{}
+				}
+				dist := math.Abs(pos - a.median)
+				players[uid].rounds++
+				players[uid].spread += dist
+				if dist > OutlierDistance {
+					players[uid].outliers++
+				}
+			}
+		}
+
+		for _, key := range order {
+			list := tickets[key]
+			sr.Tickets++
+
+			if first := analyze(list[0]); len(first.positions) > 0 {
+				firstRounds++
+				if first.consensus {
+					consensuses++
+				}
+			}
+
+			if points, ok := estimate(list[len(list)-1]); ok {
+				sr.Points += points
+			}
+		}
+
+		report.Tickets += sr.Tickets
+		report.Sessions = append(report.Sessions, sr)
+	}
+
+	sort.SliceStable(report.Sessions, func(i, j int) bool {
+		return report.Sessions[i].FirstRevealAt.Before(report.Sessions[j].FirstRevealAt)
+	})
+
+	if firstRounds > 0 {
+		report.FirstRoundConsensusRate = float64(consensuses) / float64(firstRounds)
+	}
+
+	for uid, s := range players {
+		report.Players = append(report.Players, PlayerReport{
+			UserID:        uid,
+			Rounds:        s.rounds,
+			AverageSpread: s.spread / float64(s.rounds),
+			OutlierRate:   float64(s.outliers) / float64(s.rounds),
+		})
+	}
+	sort.Slice(report.Players, func(i, j int) bool {
+		if report.Players[i].Rounds != report.Players[j].Rounds {
+			return report.Players[i].Rounds > report.Players[j].Rounds
+		}
+		return report.Players[i].UserID < report.Players[j].UserID
+	})
+
+	return report
+}
+
+type analysis struct {
+	// positions are deck positions of the counted votes by user IDs.
+	positions map[string]float64
+	median    float64
+	consensus bool
+}
+
+// analyze locates the counted round votes in the deck, abstains and cards missing in the deck are skipped.
+func analyze(r games.Round) analysis {
+	index := make(map[games.Card]int, len(r.Cards))
+	for i, c := range r.Cards {
+		index[c] = i
+	}
+
+	a := analysis{positions: make(map[string]float64)}
+	sorted := make([]float64, 0, len(r.Votes))
+	for _, v := range r.Votes {
+		pos, ok := index[v.Card]
+		if !ok || v.Card == games.AbstainCard {
+			continue
+		}
+		a.positions[v.UserID] = float64(pos)
+		sorted = append(sorted, float64(pos))
+	}
+
+	if len(sorted) == 0 {
+		return a
+	}
+
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	a.median = sorted[mid]
+	if len(sorted)%2 == 0 {
+		a.median = (sorted[mid-1] + sorted[mid]) / 2
+	}
+	a.consensus = sorted[0] == sorted[len(sorted)-1]
+
+	return a
+}
+
+// estimate returns the numeric value of the most voted card, the higher card wins a tie.
+func estimate(r games.Round) (float64, bool) {
+	a := analyze(r)
+	if len(a.positions) == 0 {
+		return 0, false
+	}
+
+	counts := make(map[int]int)
+	for _, pos := range a.positions {
+		counts[int(pos)]++
+	}
+
+	best := -1
+	for pos, n := range counts {
+		if best < 0 || n > counts[best] || (n == counts[best] && pos > best) {
+			best = pos
+		}
+	}
+
+	return r.Cards[best].Value()
+}
@@ -0,0 +1,117 @@
+package reports_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/reports"
+	"planningpoker/test"
+)
+
+var (
+	fibonacci = []games.Card{"1", "2", "3", "5", "8", "?"}
+	tShirts   = []games.Card{"S", "M", "L"}
+)
+
+func newRound(gameID, ticket string, cards []games.Card, at time.Time, votes ...string) games.Round {
+	users := []string{test.User1, test.User2, test.User3}
+	r := games.Round{GameID: gameID, TicketName: ticket, Cards: cards, RevealedAt: at}
+	for i, v := range votes {
+		if v != "" {
+			r.Votes = append(r.Votes, games.RoundVote{UserID: users[i], Card: games.Card(v)})
+		}
+	}
+
+	return r
+}
+
+func anonymousRound(gameID, ticket string, cards []games.Card, at time.Time, votes ...string) games.Round {
+	r := newRound(gameID, ticket, cards, at, votes...)
+	r.Anonymous = true
+
+	return r
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	at := func(minutes int) time.Time {
+		return now.Add(time.Duration(minutes) * time.Minute)
+	}
+
+	sessions := []reports.Session{
+		{GameID: "g2", Name: "second"},
+		{GameID: "g1", Name: "first"},
+		{GameID: "g3", Name: "no rounds"},
+	}
+	rounds := []games.Round{
+		newRound("g1", "A", fibonacci, at(1), "3", "3", "?"),
+		// the re-vote of B is appended before its first round and still is the latest one
+		newRound("g1", "B", fibonacci, at(3), "3", "5", "5"),
+		newRound("g1", "B", fibonacci, at(2), "1", "8", "2"),
+		newRound("g2", "C", tShirts, at(4), "M", "M"),
+		newRound("unknown", "D", fibonacci, at(5), "1", "8", "8"),
+		anonymousRound("g2", "E", tShirts, at(6), "S", "L"),
+	}
+
+	report := reports.Build(sessions, rounds)
+
+	require.Len(t, report.Sessions, 2)
+	assert.Equal(t, reports.SessionReport{
+		GameID: "g1", Name: "first", Rounds: 3, Tickets: 2, Points: 8, FirstRevealAt: at(1), LastRevealAt: at(3),
+	}, report.Sessions[0])
+	assert.Equal(t, reports.SessionReport{
+		GameID: "g2", Name: "second", Rounds: 2, Tickets: 2, Points: 0, FirstRevealAt: at(4), LastRevealAt: at(6),
+	}, report.Sessions[1])
+
+	assert.Equal(t, 4, report.Tickets)
+	assert.InDelta(t, 2.0/4, report.FirstRoundConsensusRate, 0.001)
+
+	assert.Equal(t, []reports.PlayerReport{
+		{UserID: test.User1, Rounds: 4, AverageSpread: 0.5, OutlierRate: 0},
+		{UserID: test.User2, Rounds: 4, AverageSpread: 0.75, OutlierRate: 0.25},
+		{UserID: test.User3, Rounds: 2, AverageSpread: 0, OutlierRate: 0},
+	}, report.Players)
+}
+
+func TestBuild_Points(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	testCases := map[string]struct {
+		cards     []games.Card
+		votes     []string
+		expPoints float64
+	}{
+		"most voted card":            {cards: fibonacci, votes: []string{"3", "5", "3"}, expPoints: 3},
+		"higher card wins a tie":     {cards: fibonacci, votes: []string{"3", "5"}, expPoints: 5},
+		"abstains are not counted":   {cards: fibonacci, votes: []string{"?", "?", "2"}, expPoints: 2},
+		"nothing on abstains only":   {cards: fibonacci, votes: []string{"?", "?"}, expPoints: 0},
+		"nothing on no votes":        {cards: fibonacci, expPoints: 0},
+		"half card":                  {cards: []games.Card{"0", "½", "1"}, votes: []string{"½"}, expPoints: 0.5},
+		"nothing on non numeric":     {cards: tShirts, votes: []string{"L"}, expPoints: 0},
+		"cards out of deck skipped":  {cards: fibonacci, votes: []string{"13", "1"}, expPoints: 1},
+		"decimal cards are numbers":  {cards: []games.Card{"0.5", "1"}, votes: []string{"0.5"}, expPoints: 0.5},
+		"zero is a numeric estimate": {cards: []games.Card{"0", "1"}, votes: []string{"0", "0"}, expPoints: 0},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			report := reports.Build(
+				[]reports.Session{{GameID: "g"}},
+				[]games.Round{newRound("g", "ticket", tt.cards, now, tt.votes...)},
+			)
+
+			require.Len(t, report.Sessions, 1)
+			assert.Equal(t, tt.expPoints, report.Sessions[0].Points)
+		})
+	}
+}
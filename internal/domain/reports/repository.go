@@ -0,0 +1,34 @@
+package reports
+
+import (
+	"context"
+
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/rooms"
+	"planningpoker/internal/domain/teams"
+)
+
+// RoundRepository is a contract to persist the history of revealed rounds, it may outlive the games.
+type RoundRepository interface {
+	AppendRound(ctx context.Context, round games.Round) error
+	DeleteRounds(ctx context.Context, gameID string) error
+	// GetRoundsByGameIDs returns rounds of all the games in the order of appending.
+	GetRoundsByGameIDs(ctx context.Context, gameIDs []string) ([]games.Round, error)
+}
+
+// TeamRepository is a contract to fetch teams.
+type TeamRepository interface {
+	Get(ctx context.Context, id string) (*teams.Team, error)
+}
+
+// RoomRepository is a contract to fetch rooms.
+type RoomRepository interface {
+	GetBySlug(ctx context.Context, slug string) (*rooms.Room, error)
+	// GetBySessionGameID returns the room which remembers the game as its session, nil if there is no such room.
+	GetBySessionGameID(ctx context.Context, gameID string) (*rooms.Room, error)
+}
+
+// GameRepository is a contract to fetch team games.
+type GameRepository interface {
+	GetGamesByTeamID(ctx context.Context, teamID string) ([]games.Game, error)
+}
@@ -0,0 +1,185 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"planningpoker/internal/domain"
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/rooms"
+	"planningpoker/internal/domain/teams"
+)
+
+// Service records revealed rounds published with domain events and builds reports of teams and rooms.
+// Only rounds of team games and room sessions are recorded, nobody can read reports of other games.
+type Service struct {
+	roundsRepo RoundRepository
+	teamsRepo  TeamRepository
+	roomsRepo  RoomRepository
+	gamesRepo  GameRepository
+	logger     *logrus.Entry
+}
+
+// NewService creates a new reports service instance.
+func NewService(
+	rr RoundRepository,
+	tr TeamRepository,
+	rmr RoomRepository,
+	gr GameRepository,
+	eventBus events.EventBus,
+	logger *logrus.Entry,
+) (*Service, error) {
+	if rr == nil {
+		return nil, errors.New("rounds repository should be provided")
+	}
+	if tr == nil {
+		return nil, errors.New("teams repository should be provided")
+	}
+	if rmr == nil {
+		return nil, errors.New("rooms repository should be provided")
+	}
+	if gr == nil {
+		return nil, errors.New("games repository should be provided")
+	}
+	if eventBus == nil {
+		return nil, errors.New("event bus should be provided")
+	}
+	if logger == nil {
+		return nil, errors.New("logger should be provided")
+	}
+
+	srv := &Service{
+		roundsRepo: rr,
+		teamsRepo:  tr,
+		roomsRepo:  rmr,
+		gamesRepo:  gr,
+		logger:     logger,
+	}
+
+	eventBus.Subscribe(srv.processRoundRevealed, events.EventTypeRoundRevealed)
+	eventBus.Subscribe(srv.processGameDeleted, events.EventTypeGameDeleted)
+	eventBus.Subscribe(srv.processSessionForgotten, events.EventTypeRoomSessionForgotten)
+
+	return srv, nil
+}
+
+// TeamReport returns the report of all team games, only members can read it.
+func (s *Service) TeamReport(ctx context.Context, teamID, userID string) (*Report, error) {
+	team, err := s.teamsRepo.Get(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("get team: %w", err)
+	}
+	if team == nil {
+		return nil, teams.ErrTeamNotFound
+	}
+	if !team.IsMember(userID) {
+		return nil, teams.ErrNotAMember
+	}
+
+	list, err := s.gamesRepo.GetGamesByTeamID(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("get team games: %w", err)
+	}
+
+	sessions := make([]Session, len(list))
+	for i, g := range list {
+		sessions[i] = Session{GameID: g.ID(), Name: g.Name()}
+	}
+
+	return s.build(ctx, sessions)
+}
+
+// RoomReport returns the report of the room sessions, only users who have opened the room can read it.
+func (s *Service) RoomReport(ctx context.Context, slug, userID string) (*Report, error) {
+	room, err := s.roomsRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("get room: %w", err)
+	}
+	if room == nil {
+		return nil, rooms.ErrRoomNotFound
+	}
+	if !room.IsMember(userID) {
+		return nil, rooms.ErrNotAMember
+	}
+
+	sessions := make([]Session, len(room.Sessions()))
+	for i, session := range room.Sessions() {
+		sessions[i] = Session{GameID: session.GameID, Name: room.Name()}
+	}
+
+	return s.build(ctx, sessions)
+}
+
+func (s *Service) build(ctx context.Context, sessions []Session) (*Report, error) {
+	ids := make([]string, len(sessions))
+	for i, session := range sessions {
+		ids[i] = session.GameID
+	}
+
+	rounds, err := s.roundsRepo.GetRoundsByGameIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("get rounds: %w", err)
+	}
+
+	report := Build(sessions, rounds)
+
+	return &report, nil
+}
+
+func (s *Service) processRoundRevealed(ctx context.Context, e events.DomainEvent) {
+	round, ok := e.Payload().(games.Round)
+	if !ok {
+		return
+	}
+	logger := s.logger.WithContext(ctx).WithField(domain.LogFieldGameID, round.GameID)
+
+	if round.TeamID == "" {
+		room, err := s.roomsRepo.GetBySessionGameID(ctx, round.GameID)
+		if err != nil {
+			logger.WithError(err).Error("unable to get the room of the game")
+			return
+		}
+		if room == nil {
+			return
+		}
+	}
+
+	if err := s.roundsRepo.AppendRound(ctx, round); err != nil {
+		logger.WithError(err).Error("unable to append the round")
+	}
+}
+
+// processGameDeleted drops rounds of the deleted game, unless a room still reports them as its session.
+func (s *Service) processGameDeleted(ctx context.Context, e events.DomainEvent) {
+	logger := s.logger.WithContext(ctx).WithField(domain.LogFieldGameID, e.AggregateID())
+
+	room, err := s.roomsRepo.GetBySessionGameID(ctx, e.AggregateID())
+	if err != nil {
+		logger.WithError(err).Error("unable to get the room of the game")
+		return
+	}
+	if room != nil {
+		return
+	}
+
+	if err := s.roundsRepo.DeleteRounds(ctx, e.AggregateID()); err != nil {
+		logger.WithError(err).Error("unable to delete rounds")
+	}
+}
+
+// processSessionForgotten drops rounds of the session the room does not report anymore.
+func (s *Service) processSessionForgotten(ctx context.Context, e events.DomainEvent) {
+	session, ok := e.Payload().(rooms.Session)
+	if !ok {
+		return
+	}
+
+	if err := s.roundsRepo.DeleteRounds(ctx, session.GameID); err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField(domain.LogFieldGameID, session.GameID).
+			Error("unable to delete rounds")
+	}
+}
@@ -0,0 +1,396 @@
+package reports_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/reports"
+	"planningpoker/internal/domain/rooms"
+	"planningpoker/internal/domain/teams"
+	"planningpoker/test"
+)
+
+func TestNewService(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		roundsRepo reports.RoundRepository
+		teamsRepo  reports.TeamRepository
+		roomsRepo  reports.RoomRepository
+		gamesRepo  reports.GameRepository
+		eventBus   events.EventBus
+		logger     *logrus.Entry
+		expError   string
+	}{
+		"success": {
+			roundsRepo: &roundsRepoStub{},
+			teamsRepo:  teamsRepoStub{},
+			roomsRepo:  roomsRepoStub{},
+			gamesRepo:  gamesRepoStub{},
+			eventBus:   &eventBusStub{},
+			logger:     test.NewLogger(),
+		},
+		"fail on no rounds repo": {
+			teamsRepo: teamsRepoStub{},
+			roomsRepo: roomsRepoStub{},
+			gamesRepo: gamesRepoStub{},
+			eventBus:  &eventBusStub{},
+			logger:    test.NewLogger(),
+			expError:  "rounds repository should be provided",
+		},
+		"fail on no teams repo": {
+			roundsRepo: &roundsRepoStub{},
+			roomsRepo:  roomsRepoStub{},
+			gamesRepo:  gamesRepoStub{},
+			eventBus:   &eventBusStub{},
+			logger:     test.NewLogger(),
+			expError:   "teams repository should be provided",
+		},
+		"fail on no rooms repo": {
+			roundsRepo: &roundsRepoStub{},
+			teamsRepo:  teamsRepoStub{},
+			gamesRepo:  gamesRepoStub{},
+			eventBus:   &eventBusStub{},
+			logger:     test.NewLogger(),
+			expError:   "rooms repository should be provided",
+		},
+		"fail on no games repo": {
+			roundsRepo: &roundsRepoStub{},
+			teamsRepo:  teamsRepoStub{},
+			roomsRepo:  roomsRepoStub{},
+			eventBus:   &eventBusStub{},
+			logger:     test.NewLogger(),
+			expError:   "games repository should be provided",
+		},
+		"fail on no event bus": {
+			roundsRepo: &roundsRepoStub{},
+			teamsRepo:  teamsRepoStub{},
+			roomsRepo:  roomsRepoStub{},
+			gamesRepo:  gamesRepoStub{},
+			logger:     test.NewLogger(),
+			expError:   "event bus should be provided",
+		},
+		"fail on no logger": {
+			roundsRepo: &roundsRepoStub{},
+			teamsRepo:  teamsRepoStub{},
+			roomsRepo:  roomsRepoStub{},
+			gamesRepo:  gamesRepoStub{},
+			eventBus:   &eventBusStub{},
+			expError:   "logger should be provided",
+		},
+	}
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			srv, err := reports.NewService(tt.roundsRepo, tt.teamsRepo, tt.roomsRepo, tt.gamesRepo, tt.eventBus, tt.logger)
+
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				assert.Nil(t, srv)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, srv)
+			}
+		})
+	}
+}
+
+func TestService_RecordsRounds(t *testing.T) {
+	t.Parallel()
+
+	room := test.NewRoom(t, "team", test.User1)
+
+	testCases := map[string]struct {
+		teamID    string
+		session   bool
+		expRounds int
+	}{
+		"success team game": {
+			teamID:    "team-id",
+			expRounds: 1,
+		},
+		"success room session": {
+			session:   true,
+			expRounds: 1,
+		},
+		"success skip other game": {},
+	}
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			game := test.NewTestGame(t, test.NewSimpleGame(t, true)).
+				UserJoins(test.User1).
+				UserVotes(test.User1, "XS").
+				UserReveals(test.User1).
+				Instance()
+			roomsRepo := roomsRepoStub{}
+			if tt.session {
+				roomsRepo.room = room
+				roomsRepo.sessionGameID = game.ID()
+			}
+			repo := &roundsRepoStub{}
+			bus := &eventBusStub{}
+			_, err := reports.NewService(repo, teamsRepoStub{}, roomsRepo, gamesRepoStub{}, bus, test.NewLogger())
+			require.NoError(t, err)
+
+			for _, e := range game.GetEvents() {
+				if e.EventType() == events.EventTypeRoundRevealed {
+					round := e.Payload().(games.Round)
+					round.TeamID = tt.teamID
+					bus.publish(events.NewDomainEventBuilder(e.EventType()).ForAggregate(game.ID()).WithPayload(round).Build())
+				}
+			}
+			bus.publish(events.NewDomainEventBuilder(events.EventTypeRoundRevealed).Build())
+
+			require.Len(t, repo.rounds, tt.expRounds)
+			if tt.expRounds > 0 {
+				assert.Equal(t, game.ID(), repo.rounds[0].GameID)
+				assert.Equal(t, []games.RoundVote{{UserID: test.User1, Card: "XS", Confidence: games.ConfidenceNormal}},
+					repo.rounds[0].Votes)
+			}
+		})
+	}
+}
+
+func TestService_DeletesRounds(t *testing.T) {
+	t.Parallel()
+
+	room := test.NewRoom(t, "team", test.User1)
+	round := newRound("game-id", "ticket", fibonacci, time.Now(), "3")
+
+	testCases := map[string]struct {
+		roomsRepo  roomsRepoStub
+		event      events.DomainEvent
+		expDeleted []string
+	}{
+		"success delete rounds of deleted game": {
+			event:      events.NewDomainEventBuilder(events.EventTypeGameDeleted).ForAggregate("game-id").Build(),
+			expDeleted: []string{"game-id"},
+		},
+		"success keep rounds of room session": {
+			roomsRepo: roomsRepoStub{room: room, sessionGameID: "game-id"},
+			event:     events.NewDomainEventBuilder(events.EventTypeGameDeleted).ForAggregate("game-id").Build(),
+		},
+		"success delete rounds of forgotten session": {
+			event: events.NewDomainEventBuilder(events.EventTypeRoomSessionForgotten).ForAggregate(room.ID()).
+				WithPayload(rooms.Session{GameID: "game-id"}).Build(),
+			expDeleted: []string{"game-id"},
+		},
+	}
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			repo := &roundsRepoStub{rounds: []games.Round{round}}
+			bus := &eventBusStub{}
+			_, err := reports.NewService(repo, teamsRepoStub{}, tt.roomsRepo, gamesRepoStub{}, bus, test.NewLogger())
+			require.NoError(t, err)
+
+			bus.publish(tt.event)
+
+			assert.Equal(t, tt.expDeleted, repo.deleted)
+		})
+	}
+}
+
+func TestService_TeamReport(t *testing.T) {
+	t.Parallel()
+
+	team := test.NewTeam(t, test.User1)
+	teamGame := games.NewRaw("team-game", "sprint", "", test.NewTestDeck(t), map[string]*games.Player{},
+		games.GameStateFinished, false, "", time.Now(), nil, team.ID(), nil, 0, false, false)
+	rounds := []games.Round{
+		newRound("team-game", "ticket", fibonacci, time.Now(), "3", "3"),
+		newRound("other-game", "ticket", fibonacci, time.Now(), "3", "5"),
+	}
+
+	testCases := map[string]struct {
+		teamsRepo   teamsRepoStub
+		gamesRepo   gamesRepoStub
+		roundsRepo  *roundsRepoStub
+		userID      string
+		expError    string
+		expSessions []string
+	}{
+		"success": {
+			teamsRepo:   teamsRepoStub{team: team},
+			gamesRepo:   gamesRepoStub{list: []games.Game{*teamGame}},
+			roundsRepo:  &roundsRepoStub{rounds: rounds},
+			userID:      test.User1,
+			expSessions: []string{"sprint"},
+		},
+		"fail on not a member": {
+			teamsRepo:  teamsRepoStub{team: team},
+			roundsRepo: &roundsRepoStub{},
+			userID:     test.User2,
+			expError:   "user is not a team member",
+		},
+		"fail on unknown team": {
+			roundsRepo: &roundsRepoStub{},
+			userID:     test.User1,
+			expError:   "team not found",
+		},
+		"fail on teams repo error": {
+			teamsRepo:  teamsRepoStub{err: errors.New("failed")},
+			roundsRepo: &roundsRepoStub{},
+			userID:     test.User1,
+			expError:   "get team: failed",
+		},
+		"fail on games repo error": {
+			teamsRepo:  teamsRepoStub{team: team},
+			gamesRepo:  gamesRepoStub{err: errors.New("failed")},
+			roundsRepo: &roundsRepoStub{},
+			userID:     test.User1,
+			expError:   "get team games: failed",
+		},
+		"fail on rounds repo error": {
+			teamsRepo:  teamsRepoStub{team: team},
+			roundsRepo: &roundsRepoStub{err: errors.New("failed")},
+			userID:     test.User1,
+			expError:   "get rounds: failed",
+		},
+	}
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			srv, err := reports.NewService(tt.roundsRepo, tt.teamsRepo, roomsRepoStub{}, tt.gamesRepo, &eventBusStub{},
+				test.NewLogger())
+			require.NoError(t, err)
+
+			report, err := srv.TeamReport(context.Background(), team.ID(), tt.userID)
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				assert.Nil(t, report)
+				return
+			}
+
+			require.NoError(t, err)
+			names := make([]string, len(report.Sessions))
+			for i, s := range report.Sessions {
+				names[i] = s.Name
+			}
+			assert.Equal(t, tt.expSessions, names)
+			assert.Equal(t, 1.0, report.FirstRoundConsensusRate)
+		})
+	}
+}
+
+func TestService_RoomReport(t *testing.T) {
+	t.Parallel()
+
+	room := test.NewRoom(t, "team", test.User1)
+	room.StartSession("first")
+	room.StartSession("second")
+	now := time.Now()
+	srv, err := reports.NewService(&roundsRepoStub{rounds: []games.Round{
+		newRound("second", "ticket", fibonacci, now.Add(time.Minute), "5"),
+		newRound("first", "ticket", fibonacci, now, "3"),
+	}}, teamsRepoStub{}, roomsRepoStub{room: room}, gamesRepoStub{}, &eventBusStub{}, test.NewLogger())
+	require.NoError(t, err)
+
+	_, err = srv.RoomReport(context.Background(), "unknown", test.User1)
+	assert.ErrorIs(t, err, rooms.ErrRoomNotFound)
+
+	_, err = srv.RoomReport(context.Background(), room.Slug(), test.User2)
+	assert.ErrorIs(t, err, rooms.ErrNotAMember)
+
+	report, err := srv.RoomReport(context.Background(), room.Slug(), test.User1)
+	require.NoError(t, err)
+	require.Len(t, report.Sessions, 2)
+	assert.Equal(t, "first", report.Sessions[0].GameID)
+	assert.Equal(t, room.Name(), report.Sessions[0].Name)
+	assert.Equal(t, 3.0, report.Sessions[0].Points)
+	assert.Equal(t, "second", report.Sessions[1].GameID)
+	assert.Equal(t, 5.0, report.Sessions[1].Points)
+}
+
+type roundsRepoStub struct {
+	rounds  []games.Round
+	deleted []string
+	err     error
+}
+
+func (r *roundsRepoStub) AppendRound(_ context.Context, round games.Round) error {
+	r.rounds = append(r.rounds, round)
+	return r.err
+}
+
+func (r *roundsRepoStub) DeleteRounds(_ context.Context, gameID string) error {
+	r.deleted = append(r.deleted, gameID)
+	return r.err
+}
+
+func (r *roundsRepoStub) GetRoundsByGameIDs(context.Context, []string) ([]games.Round, error) {
+	return r.rounds, r.err
+}
+
+type teamsRepoStub struct {
+	team *teams.Team
+	err  error
+}
+
+func (r teamsRepoStub) Get(context.Context, string) (*teams.Team, error) {
+	return r.team, r.err
+}
+
+type roomsRepoStub struct {
+	room          *rooms.Room
+	sessionGameID string
+}
+
+func (r roomsRepoStub) GetBySlug(_ context.Context, slug string) (*rooms.Room, error) {
+	if r.room == nil || r.room.Slug() != slug {
+		return nil, nil
+	}
+	return r.room, nil
+}
+
+func (r roomsRepoStub) GetBySessionGameID(_ context.Context, gameID string) (*rooms.Room, error) {
+	if r.room == nil || r.sessionGameID != gameID {
+		return nil, nil
+	}
+	return r.room, nil
+}
+
+type gamesRepoStub struct {
+	list []games.Game
+	err  error
+}
+
+func (r gamesRepoStub) GetGamesByTeamID(context.Context, string) ([]games.Game, error) {
+	return r.list, r.err
+}
+
+type eventBusStub struct {
+	consumers map[string]events.Consumer
+}
+
+func (b *eventBusStub) Publish(context.Context, events.DomainEvent) error {
+	return nil
+}
+
+func (b *eventBusStub) Subscribe(consumer events.Consumer, eventTypes ...string) {
+	if b.consumers == nil {
+		b.consumers = make(map[string]events.Consumer)
+	}
+	for _, t := range eventTypes {
+		b.consumers[t] = consumer
+	}
+}
+
+func (b *eventBusStub) publish(e events.DomainEvent) {
+	if consumer, ok := b.consumers[e.EventType()]; ok {
+		consumer(context.Background(), e)
+	}
+}
@@ -0,0 +1,116 @@
+package rooms
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxNameLength is the maximal room name length in characters.
+	MaxNameLength = 64
+	// MinSlugLength is the minimal room slug length.
+	MinSlugLength = 3
+	// MaxSlugLength is the maximal room slug length.
+	MaxSlugLength = 64
+)
+
+// slugPattern allows lowercase latin letters and digits split by single dashes, e.g. "payments-team".
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// CreateRoomCommand is a room creation command.
+type CreateRoomCommand struct {
+	UserID   string
+	Slug     string
+	Name     string
+	Settings Settings
+}
+
+// NewCreateRoomCommand creates a new command instance, the slug is lowercased and the name is trimmed.
+func NewCreateRoomCommand(slug, name, userID string, settings Settings) (*CreateRoomCommand, error) {
+	slug, err := NormalizeSlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err = validateName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateRoomCommand{
+		UserID:   userID,
+		Slug:     slug,
+		Name:     name,
+		Settings: settings,
+	}, nil
+}
+
+// UpdateRoomCommand is a room name and settings update command.
+type UpdateRoomCommand struct {
+	Slug     string
+	UserID   string
+	Name     string
+	Settings Settings
+}
+
+// NewUpdateRoomCommand creates a new command instance, the name is trimmed.
+func NewUpdateRoomCommand(slug, userID, name string, settings Settings) (*UpdateRoomCommand, error) {
+	slug, err := NormalizeSlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err = validateName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateRoomCommand{
+		Slug:     slug,
+		UserID:   userID,
+		Name:     name,
+		Settings: settings,
+	}, nil
+}
+
+// OpenRoomCommand is a command to enter the room and get its current session.
+type OpenRoomCommand struct {
+	Slug   string
+	UserID string
+}
+
+// NewOpenRoomCommand creates a new command instance.
+func NewOpenRoomCommand(slug, userID string) (*OpenRoomCommand, error) {
+	slug, err := NormalizeSlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OpenRoomCommand{
+		Slug:   slug,
+		UserID: userID,
+	}, nil
+}
+
+// NormalizeSlug trims and lowercases the slug and checks it is a valid room address.
+func NormalizeSlug(slug string) (string, error) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	if len(slug) < MinSlugLength || len(slug) > MaxSlugLength || !slugPattern.MatchString(slug) {
+		return "", ErrInvalidSlug
+	}
+
+	return slug, nil
+}
+
+func validateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrEmptyRoomName
+	}
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return "", ErrRoomNameTooLong
+	}
+
+	return name, nil
+}
@@ -0,0 +1,25 @@
+package rooms
+
+import "planningpoker/internal/domain"
+
+var (
+	// ErrRoomNotFound is returned when there is no room with the slug.
+	ErrRoomNotFound = domain.NewError(domain.KindNotFound, "room_not_found", "room not found")
+	// ErrSlugTaken is returned when another room already has the slug.
+	ErrSlugTaken = domain.NewError(domain.KindConflict, "slug_taken", "room slug is already taken")
+	// ErrNotAMember is returned when the action is allowed for users who opened the room only.
+	ErrNotAMember = domain.NewError(domain.KindForbidden, "not_a_member", "user is not a room member")
+	// ErrNotAnOwner is returned when the action is allowed for the room owner only.
+	ErrNotAnOwner = domain.NewError(domain.KindForbidden, "not_an_owner", "user is not the room owner")
+	// ErrInvalidSlug is returned when the slug is not a valid room address.
+	ErrInvalidSlug = domain.NewError(
+		domain.KindValidation, "invalid_slug",
+		"room slug should be 3 to 64 lowercase letters or digits, optionally split by dashes",
+	)
+	// ErrEmptyRoomName is returned when the room name is not provided.
+	ErrEmptyRoomName = domain.NewError(domain.KindValidation, "invalid_room_name", "room name should be provided")
+	// ErrRoomNameTooLong is returned when the room name is longer than MaxNameLength.
+	ErrRoomNameTooLong = domain.NewError(
+		domain.KindValidation, "invalid_room_name", "room name should be at most 64 characters long",
+	)
+)
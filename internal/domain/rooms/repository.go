@@ -0,0 +1,28 @@
+package rooms
+
+import (
+	"context"
+
+	"planningpoker/internal/domain/games"
+)
+
+// Repository is a repository contract to fetch/persist rooms.
+type Repository interface {
+	ModifyExclusively(ctx context.Context, id string, cb func(room *Room) error) error
+	Get(ctx context.Context, id string) (*Room, error)
+	// Save persists the room, it fails with ErrSlugTaken if another room has the same slug.
+	Save(ctx context.Context, room *Room) error
+	// GetBySlug returns the room with the slug, nil if there is no such room.
+	GetBySlug(ctx context.Context, slug string) (*Room, error)
+	GetRoomsByMemberID(ctx context.Context, userID string) ([]Room, error)
+}
+
+// GameRepository is a contract to fetch room session games.
+type GameRepository interface {
+	Get(ctx context.Context, id string) (*games.Game, error)
+}
+
+// GamesService is a contract to create session games.
+type GamesService interface {
+	Create(ctx context.Context, cmd games.CreateGameCommand) (string, error)
+}
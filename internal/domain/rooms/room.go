@@ -0,0 +1,205 @@
+// Package rooms contains domain level persistent rooms logic.
+package rooms
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"planningpoker/internal/domain"
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/games"
+)
+
+// MaxSessions is the maximal number of sessions the room remembers, older ones are forgotten.
+const MaxSessions = 100
+
+// Room is a domain aggregate of a long-lived named place hosting game sessions one after another.
+type Room struct {
+	domain.BaseAggregate
+	id      string
+	slug    string
+	name    string
+	ownerID string
+	// members are user IDs mapped to the time they opened the room for the first time.
+	members   map[string]time.Time
+	settings  Settings
+	sessions  []Session
+	createdAt time.Time
+}
+
+// Settings are defaults of games started in the room.
+type Settings struct {
+	CardsDeck         games.CardsDeck
+	EveryoneCanReveal bool
+}
+
+// Session is a value object of a game hosted by the room.
+type Session struct {
+	GameID    string
+	StartedAt time.Time
+}
+
+// NewRoom creates a new room, the creator becomes its owner.
+func NewRoom(cmd CreateRoomCommand) *Room {
+	r := &Room{
+		id:        strings.ReplaceAll(uuid.New().String(), "-", ""),
+		slug:      cmd.Slug,
+		name:      cmd.Name,
+		ownerID:   cmd.UserID,
+		members:   make(map[string]time.Time),
+		settings:  cmd.Settings,
+		sessions:  make([]Session, 0),
+		createdAt: time.Now(),
+	}
+	r.members[cmd.UserID] = r.createdAt
+	r.setChanged()
+
+	return r
+}
+
+// NewRaw instantiates a room aggregate from raw data.
+// It should never be used in any logic except aggregate hydration from any serialized format (db, etc...)
+func NewRaw(
+	id, slug, name, ownerID string,
+	members map[string]time.Time,
+	settings Settings,
+	sessions []Session,
+	createdAt time.Time,
+) *Room {
+	return &Room{
+		id:        id,
+		slug:      slug,
+		name:      name,
+		ownerID:   ownerID,
+		members:   members,
+		settings:  settings,
+		sessions:  sessions,
+		createdAt: createdAt,
+	}
+}
+
+// ID returns the room ID.
+func (r Room) ID() string {
+	return r.id
+}
+
+// Slug returns the unique human-readable room address.
+func (r Room) Slug() string {
+	return r.slug
+}
+
+// Name returns the room name.
+func (r Room) Name() string {
+	return r.name
+}
+
+// OwnerID returns the ID of the user who manages the room.
+func (r Room) OwnerID() string {
+	return r.ownerID
+}
+
+// Members returns user IDs of everyone who opened the room mapped to the time they did it first.
+func (r Room) Members() map[string]time.Time {
+	return r.members
+}
+
+// IsMember checks if the user has ever opened the room.
+func (r Room) IsMember(uid string) bool {
+	_, ok := r.members[uid]
+	return ok
+}
+
+// MemberIDs returns user IDs of all members ordered by the time they joined.
+func (r Room) MemberIDs() []string {
+	ids := make([]string, 0, len(r.members))
+	for id := range r.members {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		ti, tj := r.members[ids[i]], r.members[ids[j]]
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
+
+// Settings returns defaults of the room games.
+func (r Room) Settings() Settings {
+	return r.settings
+}
+
+// Sessions returns the room sessions, the most recent is the last one.
+func (r Room) Sessions() []Session {
+	return r.sessions
+}
+
+// CurrentGameID returns the ID of the most recent session game, empty if no session was started yet.
+func (r Room) CurrentGameID() string {
+	if len(r.sessions) == 0 {
+		return ""
+	}
+
+	return r.sessions[len(r.sessions)-1].GameID
+}
+
+// CreatedAt returns the time of the room creation.
+func (r Room) CreatedAt() time.Time {
+	return r.createdAt
+}
+
+// IsOwner checks if specific user manages the room.
+func (r Room) IsOwner(uid string) bool {
+	return r.ownerID == uid
+}
+
+// Update changes the room name and settings, only the owner can do that.
+// Sessions which are already started keep their settings.
+func (r *Room) Update(cmd UpdateRoomCommand) error {
+	if !r.IsOwner(cmd.UserID) {
+		return ErrNotAnOwner
+	}
+
+	r.name = cmd.Name
+	r.settings = cmd.Settings
+	r.setChanged()
+
+	return nil
+}
+
+// Enter adds the user to the room members, entering twice is not an error.
+func (r *Room) Enter(uid string) {
+	if _, ok := r.members[uid]; ok {
+		return
+	}
+
+	r.members[uid] = time.Now()
+	r.setChanged()
+}
+
+// NewGameCommand creates a command of a session game creation, the game inherits the room settings.
+func (r Room) NewGameCommand(uid string) (*games.CreateGameCommand, error) {
+	return games.NewCreateGameCommand(r.name, "", uid, r.settings.CardsDeck, r.settings.EveryoneCanReveal)
+}
+
+// StartSession makes the game the current room session, the oldest sessions are forgotten over MaxSessions.
+func (r *Room) StartSession(gameID string) {
+	r.sessions = append(r.sessions, Session{GameID: gameID, StartedAt: time.Now()})
+	if len(r.sessions) > MaxSessions {
+		for _, s := range r.sessions[:len(r.sessions)-MaxSessions] {
+			r.AddEvent(events.NewDomainEventBuilder(events.EventTypeRoomSessionForgotten).ForAggregate(r.id).WithPayload(s).Build())
+		}
+		r.sessions = r.sessions[len(r.sessions)-MaxSessions:]
+	}
+	r.setChanged()
+}
+
+func (r *Room) setChanged() {
+	r.AddEvent(events.NewDomainEventBuilder(events.EventTypeRoomUpdated).ForAggregate(r.id).Build())
+}
@@ -0,0 +1,122 @@
+package rooms_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/rooms"
+	"planningpoker/test"
+)
+
+func TestNewRoom(t *testing.T) {
+	t.Parallel()
+
+	room := test.NewRoom(t, "payments-team", test.User1)
+
+	assert.NotEmpty(t, room.ID())
+	assert.Equal(t, "payments-team", room.Slug())
+	assert.True(t, room.IsOwner(test.User1))
+	assert.Equal(t, []string{test.User1}, room.MemberIDs())
+	assert.Empty(t, room.CurrentGameID())
+	require.Len(t, room.GetEvents(), 1)
+	assert.Equal(t, events.EventTypeRoomUpdated, room.GetEvents()[0].EventType())
+}
+
+func TestNewCreateRoomCommand(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		slug     string
+		name     string
+		expSlug  string
+		expName  string
+		expError string
+	}{
+		"success":                         {slug: "payments-team", name: "Payments", expSlug: "payments-team", expName: "Payments"},
+		"success on normalized slug":      {slug: " Team-42 ", name: " Room ", expSlug: "team-42", expName: "Room"},
+		"fail on too short slug":          {slug: "ab", name: "Room", expError: rooms.ErrInvalidSlug.Error()},
+		"fail on too long slug":           {slug: strings.Repeat("a", 65), name: "Room", expError: rooms.ErrInvalidSlug.Error()},
+		"fail on spaces in slug":          {slug: "my team", name: "Room", expError: rooms.ErrInvalidSlug.Error()},
+		"fail on leading dash in slug":    {slug: "-team", name: "Room", expError: rooms.ErrInvalidSlug.Error()},
+		"fail on double dash in slug":     {slug: "my--team", name: "Room", expError: rooms.ErrInvalidSlug.Error()},
+		"fail on non latin slug":          {slug: "команда", name: "Room", expError: rooms.ErrInvalidSlug.Error()},
+		"fail on blank name":              {slug: "team", name: " ", expError: "room name should be provided"},
+		"fail on too long name":           {slug: "team", name: strings.Repeat("a", 65), expError: "room name should be at most 64 characters long"},
+		"success on slug of digits only":  {slug: "2024", name: "Room", expSlug: "2024", expName: "Room"},
+		"success on slug of maximal size": {slug: strings.Repeat("a", 64), name: "Room", expSlug: strings.Repeat("a", 64), expName: "Room"},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, err := rooms.NewCreateRoomCommand(tt.slug, tt.name, test.User1, rooms.Settings{})
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				assert.Nil(t, cmd)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expSlug, cmd.Slug)
+			assert.Equal(t, tt.expName, cmd.Name)
+		})
+	}
+}
+
+func TestRoom_Update(t *testing.T) {
+	t.Parallel()
+
+	room := test.NewRoom(t, "team", test.User1)
+	room.Enter(test.User2)
+	settings := rooms.Settings{CardsDeck: test.NewTestDeck(t), EveryoneCanReveal: true}
+
+	cmd, err := rooms.NewUpdateRoomCommand(room.Slug(), test.User2, "new", settings)
+	require.NoError(t, err)
+	assert.ErrorIs(t, room.Update(*cmd), rooms.ErrNotAnOwner)
+
+	cmd.UserID = test.User1
+	require.NoError(t, room.Update(*cmd))
+	assert.Equal(t, "new", room.Name())
+	assert.Equal(t, settings, room.Settings())
+
+	gameCmd, err := room.NewGameCommand(test.User2)
+	require.NoError(t, err)
+	assert.Equal(t, "new", gameCmd.Name)
+	assert.Equal(t, test.User2, gameCmd.UserID)
+	assert.Equal(t, settings.CardsDeck, gameCmd.CardsDeck)
+	assert.True(t, gameCmd.EveryoneCanReveal)
+}
+
+func TestRoom_Sessions(t *testing.T) {
+	t.Parallel()
+
+	room := test.NewRoom(t, "team", test.User1)
+	room.Enter(test.User2)
+	room.Enter(test.User2)
+	assert.Equal(t, []string{test.User1, test.User2}, room.MemberIDs())
+	assert.True(t, room.IsMember(test.User2))
+	assert.False(t, room.IsMember(test.User3))
+
+	for i := 0; i < rooms.MaxSessions+1; i++ {
+		room.StartSession(fmt.Sprintf("game-%d", i))
+	}
+
+	assert.Len(t, room.Sessions(), rooms.MaxSessions)
+	assert.Equal(t, "game-1", room.Sessions()[0].GameID)
+	assert.Equal(t, fmt.Sprintf("game-%d", rooms.MaxSessions), room.CurrentGameID())
+
+	forgotten := make([]string, 0)
+	for _, e := range room.GetEvents() {
+		if e.EventType() == events.EventTypeRoomSessionForgotten {
+			forgotten = append(forgotten, e.Payload().(rooms.Session).GameID)
+		}
+	}
+	assert.Equal(t, []string{"game-0"}, forgotten)
+}
@@ -0,0 +1,132 @@
+package rooms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"planningpoker/internal/domain/games"
+)
+
+// Service is a rooms related application service.
+type Service struct {
+	roomsRepo    Repository
+	gamesRepo    GameRepository
+	gamesService GamesService
+}
+
+// NewService creates a new rooms service instance.
+func NewService(rr Repository, gr GameRepository, gs GamesService) (*Service, error) {
+	if rr == nil {
+		return nil, errors.New("rooms repository should be provided")
+	}
+	if gr == nil {
+		return nil, errors.New("games repository should be provided")
+	}
+	if gs == nil {
+		return nil, errors.New("games service should be provided")
+	}
+
+	return &Service{
+		roomsRepo:    rr,
+		gamesRepo:    gr,
+		gamesService: gs,
+	}, nil
+}
+
+// Create creates a new room owned by the user.
+func (s *Service) Create(ctx context.Context, cmd CreateRoomCommand) (*Room, error) {
+	room := NewRoom(cmd)
+	if err := s.roomsRepo.Save(ctx, room); err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// Get returns the room by its slug, anyone who knows the slug can see the room.
+func (s *Service) Get(ctx context.Context, slug string) (*Room, error) {
+	room, err := s.roomsRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("get room: %w", err)
+	}
+	if room == nil {
+		return nil, ErrRoomNotFound
+	}
+
+	return room, nil
+}
+
+// ListByMember returns all rooms the user opened ordered by name.
+func (s *Service) ListByMember(ctx context.Context, userID string) ([]Room, error) {
+	list, err := s.roomsRepo.GetRoomsByMemberID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Name() != list[j].Name() {
+			return list[i].Name() < list[j].Name()
+		}
+		return list[i].Slug() < list[j].Slug()
+	})
+
+	return list, nil
+}
+
+// Update changes the room name and settings.
+func (s *Service) Update(ctx context.Context, cmd UpdateRoomCommand) error {
+	room, err := s.Get(ctx, cmd.Slug)
+	if err != nil {
+		return err
+	}
+
+	return s.roomsRepo.ModifyExclusively(ctx, room.ID(), func(room *Room) error {
+		return room.Update(cmd)
+	})
+}
+
+// Open adds the user to the room members and returns the ID of the current session game.
+// A new session is started when the room has none or the current game is archived or gone.
+func (s *Service) Open(ctx context.Context, cmd OpenRoomCommand) (string, error) {
+	room, err := s.Get(ctx, cmd.Slug)
+	if err != nil {
+		return "", err
+	}
+
+	gameID := ""
+	// the room is locked while the session is checked, so concurrent visitors get the same game
+	err = s.roomsRepo.ModifyExclusively(ctx, room.ID(), func(room *Room) error {
+		room.Enter(cmd.UserID)
+
+		if current := room.CurrentGameID(); current != "" {
+			game, err := s.gamesRepo.Get(ctx, current)
+			if err != nil {
+				return fmt.Errorf("get current game: %w", err)
+			}
+			if game != nil && game.State() != games.GameStateArchived {
+				gameID = current
+				return nil
+			}
+		}
+
+		gameCmd, err := room.NewGameCommand(cmd.UserID)
+		if err != nil {
+			return err
+		}
+
+		gameID, err = s.gamesService.Create(ctx, *gameCmd)
+		if err != nil {
+			return err
+		}
+		room.StartSession(gameID)
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return gameID, nil
+}
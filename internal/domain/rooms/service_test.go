@@ -0,0 +1,228 @@
+package rooms_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/rooms"
+	"planningpoker/test"
+)
+
+func TestNewService(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		roomsRepo    rooms.Repository
+		gamesRepo    rooms.GameRepository
+		gamesService rooms.GamesService
+		expError     string
+	}{
+		"success": {
+			roomsRepo:    &roomsRepoStub{},
+			gamesRepo:    gamesRepoStub{},
+			gamesService: &gamesServiceStub{},
+		},
+		"fail on no rooms repo": {
+			gamesRepo:    gamesRepoStub{},
+			gamesService: &gamesServiceStub{},
+			expError:     "rooms repository should be provided",
+		},
+		"fail on no games repo": {
+			roomsRepo:    &roomsRepoStub{},
+			gamesService: &gamesServiceStub{},
+			expError:     "games repository should be provided",
+		},
+		"fail on no games service": {
+			roomsRepo: &roomsRepoStub{},
+			gamesRepo: gamesRepoStub{},
+			expError:  "games service should be provided",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			srv, err := rooms.NewService(tt.roomsRepo, tt.gamesRepo, tt.gamesService)
+
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				assert.Nil(t, srv)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, srv)
+			}
+		})
+	}
+}
+
+func TestService_Open(t *testing.T) {
+	t.Parallel()
+
+	newGame := func(id, state string) *games.Game {
+		return games.NewRaw(id, "", "", test.NewTestDeck(t), map[string]*games.Player{}, state, false, "",
+			time.Now(), nil, "", nil, 0, false, false)
+	}
+	withSession := func(gameID string) *rooms.Room {
+		room := test.NewRoom(t, "team", test.User1)
+		room.StartSession(gameID)
+		return room
+	}
+
+	testCases := map[string]struct {
+		room         *rooms.Room
+		repoErr      error
+		gamesRepo    gamesRepoStub
+		gamesService *gamesServiceStub
+		expGameID    string
+		expSessions  int
+		expError     string
+	}{
+		"success start first session": {
+			room:         test.NewRoom(t, "team", test.User1),
+			gamesService: &gamesServiceStub{id: "new"},
+			expGameID:    "new",
+			expSessions:  1,
+		},
+		"success join current session": {
+			room:         withSession("current"),
+			gamesRepo:    gamesRepoStub{game: newGame("current", games.GameStateFinished)},
+			gamesService: &gamesServiceStub{id: "new"},
+			expGameID:    "current",
+			expSessions:  1,
+		},
+		"success start new session on archived game": {
+			room:         withSession("current"),
+			gamesRepo:    gamesRepoStub{game: newGame("current", games.GameStateArchived)},
+			gamesService: &gamesServiceStub{id: "new"},
+			expGameID:    "new",
+			expSessions:  2,
+		},
+		"success start new session on deleted game": {
+			room:         withSession("current"),
+			gamesService: &gamesServiceStub{id: "new"},
+			expGameID:    "new",
+			expSessions:  2,
+		},
+		"fail on unknown room": {
+			gamesService: &gamesServiceStub{id: "new"},
+			expError:     "room not found",
+		},
+		"fail on rooms repo error": {
+			repoErr:      errors.New("failed"),
+			gamesService: &gamesServiceStub{id: "new"},
+			expError:     "get room: failed",
+		},
+		"fail on games repo error": {
+			room:         withSession("current"),
+			gamesRepo:    gamesRepoStub{err: errors.New("failed")},
+			gamesService: &gamesServiceStub{id: "new"},
+			expError:     "get current game: failed",
+		},
+		"fail on game creation error": {
+			room:         test.NewRoom(t, "team", test.User1),
+			gamesService: &gamesServiceStub{err: errors.New("too many games")},
+			expError:     "too many games",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			repo := &roomsRepoStub{room: tt.room, err: tt.repoErr}
+			srv, err := rooms.NewService(repo, tt.gamesRepo, tt.gamesService)
+			require.NoError(t, err)
+
+			gameID, err := srv.Open(context.Background(), rooms.OpenRoomCommand{Slug: "team", UserID: test.User2})
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expGameID, gameID)
+			assert.Equal(t, tt.expGameID, repo.room.CurrentGameID())
+			assert.Len(t, repo.room.Sessions(), tt.expSessions)
+			assert.Contains(t, repo.room.Members(), test.User2)
+		})
+	}
+}
+
+func TestService_Update(t *testing.T) {
+	t.Parallel()
+
+	room := test.NewRoom(t, "team", test.User1)
+	srv, err := rooms.NewService(&roomsRepoStub{room: room}, gamesRepoStub{}, &gamesServiceStub{})
+	require.NoError(t, err)
+
+	cmd, err := rooms.NewUpdateRoomCommand("team", test.User2, "new", room.Settings())
+	require.NoError(t, err)
+	assert.ErrorIs(t, srv.Update(context.Background(), *cmd), rooms.ErrNotAnOwner)
+
+	cmd.UserID = test.User1
+	require.NoError(t, srv.Update(context.Background(), *cmd))
+	assert.Equal(t, "new", room.Name())
+}
+
+type roomsRepoStub struct {
+	room *rooms.Room
+	err  error
+}
+
+func (r *roomsRepoStub) ModifyExclusively(_ context.Context, _ string, cb func(room *rooms.Room) error) error {
+	if r.room == nil {
+		return rooms.ErrRoomNotFound
+	}
+	return cb(r.room)
+}
+
+func (r *roomsRepoStub) Get(context.Context, string) (*rooms.Room, error) {
+	return r.room, r.err
+}
+
+func (r *roomsRepoStub) Save(_ context.Context, room *rooms.Room) error {
+	r.room = room
+	return r.err
+}
+
+func (r *roomsRepoStub) GetBySlug(_ context.Context, slug string) (*rooms.Room, error) {
+	if r.room == nil || r.room.Slug() != slug {
+		return nil, r.err
+	}
+	return r.room, r.err
+}
+
+func (r *roomsRepoStub) GetRoomsByMemberID(context.Context, string) ([]rooms.Room, error) {
+	if r.room == nil {
+		return nil, r.err
+	}
+	return []rooms.Room{*r.room}, r.err
+}
+
+type gamesRepoStub struct {
+	game *games.Game
+	err  error
+}
+
+func (r gamesRepoStub) Get(_ context.Context, id string) (*games.Game, error) {
+	if r.game == nil || r.game.ID() != id {
+		return nil, r.err
+	}
+	return r.game, r.err
+}
+
+type gamesServiceStub struct {
+	id  string
+	err error
+}
+
+func (s *gamesServiceStub) Create(context.Context, games.CreateGameCommand) (string, error) {
+	return s.id, s.err
+}
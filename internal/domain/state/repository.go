@@ -1,16 +1,18 @@
 package state
 
 import (
+	"context"
+
 	"planningpoker/internal/domain/games"
 	"planningpoker/internal/domain/users"
 )
 
 // GameRepository is a contract to fetch games data.
 type GameRepository interface {
-	Get(id string) (*games.Game, error)
+	Get(ctx context.Context, id string) (*games.Game, error)
 }
 
 // UsersRepository is a contract to fetch users data.
 type UsersRepository interface {
-	GetMany(ids []string) ([]users.User, error)
+	GetMany(ctx context.Context, ids []string) ([]users.User, error)
 }
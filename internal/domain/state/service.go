@@ -1,27 +1,40 @@
 package state
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"planningpoker/internal/domain"
 	"planningpoker/internal/domain/events"
 )
 
-// Publisher sends a message to a player.
+// Publisher sends game states to connected clients.
 type Publisher interface {
-	SendToPlayer(gameState GameState, userID string) error
+	// SendToGame sends the state shared by everyone watching the game, it is built once for all of them.
+	SendToGame(ctx context.Context, gameState GameState) error
+	// SendToPlayer sends the state fields known to the player only, e.g. their own vote.
+	SendToPlayer(ctx context.Context, gameState GameState, userID string) error
 }
 
+// tracerName is the name of the tracer of the service spans, it is the same as the one of the infrastructure spans.
+const tracerName = "planningpoker"
+
 // Service is a game state service.
 type Service struct {
 	gamesRepo GameRepository
 	usersRepo UsersRepository
 	publisher Publisher
+	logger    *logrus.Entry
 }
 
 // NewService creates a new game state service instance.
-func NewService(gr GameRepository, ur UsersRepository, pub Publisher, eventBus events.EventBus) (*Service, error) {
+func NewService(gr GameRepository, ur UsersRepository, pub Publisher, eventBus events.EventBus, logger *logrus.Entry) (*Service, error) {
 	if gr == nil {
 		return nil, errors.New("games repository should be provided")
 	}
@@ -34,11 +47,15 @@ func NewService(gr GameRepository, ur UsersRepository, pub Publisher, eventBus e
 	if pub == nil {
 		return nil, errors.New("publisher should be provided")
 	}
+	if logger == nil {
+		return nil, errors.New("logger should be provided")
+	}
 
 	srv := &Service{
 		gamesRepo: gr,
 		usersRepo: ur,
 		publisher: pub,
+		logger:    logger,
 	}
 
 	eventBus.Subscribe(srv.processGameUpdated, events.EventTypeGameUpdated)
@@ -47,18 +64,13 @@ func NewService(gr GameRepository, ur UsersRepository, pub Publisher, eventBus e
 }
 
 // GameState returns a current state of a game.
-func (s *Service) GameState(gameID string) (*GameState, error) {
-	game, err := s.gamesRepo.Get(gameID)
+func (s *Service) GameState(ctx context.Context, gameID string) (*GameState, error) {
+	game, err := s.gamesRepo.Get(ctx, gameID)
 	if err != nil || game == nil {
 		return nil, fmt.Errorf("get game: %w", err)
 	}
 
-	userIDs := make([]string, 0)
-	for id := range game.Players() {
-		userIDs = append(userIDs, id)
-	}
-
-	users, err := s.usersRepo.GetMany(userIDs)
+	users, err := s.usersRepo.GetMany(ctx, UserIDs(*game))
 	if err != nil {
 		return nil, err
 	}
@@ -68,15 +80,27 @@ func (s *Service) GameState(gameID string) (*GameState, error) {
 	return &state, nil
 }
 
-func (s *Service) processGameUpdated(e events.DomainEvent) {
-	gameState, err := s.GameState(e.AggregateID())
+func (s *Service) processGameUpdated(ctx context.Context, e events.DomainEvent) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "state.processGameUpdated",
+		trace.WithAttributes(attribute.String("game.id", e.AggregateID())))
+	defer span.End()
+
+	logger := s.logger.WithContext(ctx).WithField(domain.LogFieldGameID, e.AggregateID())
+
+	gameState, err := s.GameState(ctx, e.AggregateID())
 	if err != nil {
-		logrus.Errorf("failed to fetch game state %v", err)
+		logger.WithError(err).Error("unable to fetch the game state")
+		span.RecordError(err)
+		return
+	}
+
+	if err := s.publisher.SendToGame(ctx, *gameState); err != nil {
+		logger.WithError(err).Error("unable to send the state to the game")
 	}
 
 	for _, playerState := range gameState.Players {
-		if err := s.publisher.SendToPlayer(*gameState, playerState.UserID); err != nil {
-			logrus.Errorf("failed to send state to the player with ID=%s, %+v, %v", playerState.UserID, gameState, err)
+		if err := s.publisher.SendToPlayer(ctx, *gameState, playerState.UserID); err != nil {
+			logger.WithError(err).WithField(domain.LogFieldUserID, playerState.UserID).Error("unable to send the state to the player")
 		}
 	}
 }
@@ -1,12 +1,15 @@
 package state_test
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"planningpoker/internal/domain/events"
 	"planningpoker/internal/domain/state"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"planningpoker/internal/domain/games"
@@ -22,6 +25,7 @@ func TestNewService(t *testing.T) {
 		usersRepo state.UsersRepository
 		publisher state.Publisher
 		eventBus  events.EventBus
+		logger    *logrus.Entry
 		expError  string
 	}{
 		"success": {
@@ -29,38 +33,50 @@ func TestNewService(t *testing.T) {
 			usersRepo: usersRepoStub{},
 			publisher: publisherStub{},
 			eventBus:  eventBusStub{},
+			logger:    test.NewLogger(),
 			expError:  "",
 		},
 		"fail on no event bus": {
 			gameRepo:  gamesRepoStub{},
 			usersRepo: usersRepoStub{},
 			publisher: publisherStub{},
+			logger:    test.NewLogger(),
 			expError:  "event bus should be provided",
 		},
 		"fail on no publisher": {
 			gameRepo:  gamesRepoStub{},
 			usersRepo: usersRepoStub{},
 			eventBus:  eventBusStub{},
+			logger:    test.NewLogger(),
 			expError:  "publisher should be provided",
 		},
 		"fail on no game repo": {
 			usersRepo: usersRepoStub{},
 			publisher: publisherStub{},
 			eventBus:  eventBusStub{},
+			logger:    test.NewLogger(),
 			expError:  "games repository should be provided",
 		},
 		"fail on no user repo": {
 			gameRepo:  gamesRepoStub{},
 			publisher: publisherStub{},
 			eventBus:  eventBusStub{},
+			logger:    test.NewLogger(),
 			expError:  "users repository should be provided",
 		},
+		"fail on no logger": {
+			gameRepo:  gamesRepoStub{},
+			usersRepo: usersRepoStub{},
+			publisher: publisherStub{},
+			eventBus:  eventBusStub{},
+			expError:  "logger should be provided",
+		},
 	}
 	for name, tt := range testCases {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			srv, err := state.NewService(tt.gameRepo, tt.usersRepo, tt.publisher, tt.eventBus)
+			srv, err := state.NewService(tt.gameRepo, tt.usersRepo, tt.publisher, tt.eventBus, tt.logger)
 
 			if tt.expError != "" {
 				assert.EqualError(t, err, tt.expError)
@@ -107,10 +123,10 @@ func TestGamesService_GameState(t *testing.T) {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			srv, err := state.NewService(tt.gameRepo, tt.userRepo, publisherStub{}, eventBusStub{})
+			srv, err := state.NewService(tt.gameRepo, tt.userRepo, publisherStub{}, eventBusStub{}, test.NewLogger())
 			require.NoError(t, err)
 
-			st, err := srv.GameState("anything")
+			st, err := srv.GameState(context.Background(), "anything")
 
 			if tt.expError != "" {
 				assert.EqualError(t, err, tt.expError)
@@ -124,12 +140,76 @@ func TestGamesService_GameState(t *testing.T) {
 	}
 }
 
+func TestGamesService_GameStatePlayersOrder(t *testing.T) {
+	t.Parallel()
+
+	game := newTestServiceGame(t).UserJoins(test.User2).UserJoins(test.User1).UserJoins(test.User3).Instance()
+	srv, err := state.NewService(gamesRepoStub{game: game}, usersRepoStub{}, publisherStub{}, eventBusStub{}, test.NewLogger())
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		st, err := srv.GameState(context.Background(), "anything")
+		require.NoError(t, err)
+		require.Len(t, st.Players, 3)
+		assert.Equal(t, test.User2, st.Players[0].UserID)
+		assert.Equal(t, test.User1, st.Players[1].UserID)
+		assert.Equal(t, test.User3, st.Players[2].UserID)
+	}
+}
+
+func TestGamesService_GameStateAttempts(t *testing.T) {
+	t.Parallel()
+
+	game := newTestServiceGame(t).
+		UserJoins(test.User1).UserJoins(test.User2).
+		UserVotes(test.User1, "XS").UserVotes(test.User2, "S").
+		UserReveals(test.User1).UserLeaves(test.User2).UserRevotes(test.User1).
+		ShouldSucceed().
+		Instance()
+	srv, err := state.NewService(gamesRepoStub{game: game}, usersRepoStub{manyUsers: []users.User{
+		*users.NewRaw(test.User1, "Alex", time.Now()),
+		*users.NewRaw(test.User2, "Sam", time.Now()),
+	}}, publisherStub{}, eventBusStub{}, test.NewLogger())
+	require.NoError(t, err)
+
+	st, err := srv.GameState(context.Background(), "anything")
+	require.NoError(t, err)
+
+	// the player who left is still shown in the attempt they voted in
+	require.Len(t, st.Players, 1)
+	require.Len(t, st.Attempts, 1)
+	assert.Equal(t, 1, st.Attempts[0].Number)
+	assert.Equal(t, []state.AttemptVoteState{
+		{Handle: game.Attempts()[0].Votes[0].Handle, Name: "Alex", Card: "XS"},
+		{Handle: game.Attempts()[0].Votes[1].Handle, Name: "Sam", Card: "S"},
+	}, st.Attempts[0].Votes)
+	assert.Equal(t, []string{test.User1, test.User2}, state.UserIDs(*game))
+	// votes of the running attempt are not summarized
+	assert.Nil(t, st.Estimate)
+}
+
+func TestService_SendsSharedStateOnce(t *testing.T) {
+	t.Parallel()
+
+	game := newTestServiceGame(t).UserJoins(test.User1).UserJoins(test.User2).UserJoins(test.User3).Instance()
+	bus := subscribedBusStub{consumer: new(events.Consumer)}
+	publisher := &publisherRecorder{}
+	_, err := state.NewService(gamesRepoStub{game: game}, usersRepoStub{}, publisher, bus, test.NewLogger())
+	require.NoError(t, err)
+
+	e := events.NewDomainEventBuilder(events.EventTypeGameUpdated).ForAggregate(game.ID()).Build()
+	require.NoError(t, bus.Publish(context.Background(), e))
+
+	assert.Equal(t, []string{game.ID()}, publisher.games)
+	assert.Equal(t, []string{test.User1, test.User2, test.User3}, publisher.players)
+}
+
 type gamesRepoStub struct {
 	game   *games.Game
 	getErr error
 }
 
-func (g gamesRepoStub) Get(string) (*games.Game, error) {
+func (g gamesRepoStub) Get(context.Context, string) (*games.Game, error) {
 	return g.game, g.getErr
 }
 
@@ -138,7 +218,7 @@ type usersRepoStub struct {
 	manyUsers  []users.User
 }
 
-func (u usersRepoStub) GetMany([]string) ([]users.User, error) {
+func (u usersRepoStub) GetMany(context.Context, []string) ([]users.User, error) {
 	return u.manyUsers, u.getManyErr
 }
 
@@ -150,15 +230,49 @@ type publisherStub struct {
 	err error
 }
 
-func (p publisherStub) SendToPlayer(gameState state.GameState, userID string) error {
+func (p publisherStub) SendToGame(context.Context, state.GameState) error {
+	return p.err
+}
+
+func (p publisherStub) SendToPlayer(_ context.Context, gameState state.GameState, userID string) error {
 	return p.err
 }
 
+// publisherRecorder records game IDs and user IDs the states are sent to.
+type publisherRecorder struct {
+	games   []string
+	players []string
+}
+
+func (p *publisherRecorder) SendToGame(_ context.Context, gameState state.GameState) error {
+	p.games = append(p.games, gameState.GameID)
+	return nil
+}
+
+func (p *publisherRecorder) SendToPlayer(_ context.Context, _ state.GameState, userID string) error {
+	p.players = append(p.players, userID)
+	return nil
+}
+
 type eventBusStub struct{}
 
-func (e eventBusStub) Publish(event events.DomainEvent) error {
+func (e eventBusStub) Publish(_ context.Context, event events.DomainEvent) error {
 	return nil
 }
 
 func (e eventBusStub) Subscribe(consumer events.Consumer, eventTypes ...string) {
 }
+
+// subscribedBusStub keeps the subscribed consumer, so events can be consumed synchronously.
+type subscribedBusStub struct {
+	consumer *events.Consumer
+}
+
+func (e subscribedBusStub) Publish(ctx context.Context, event events.DomainEvent) error {
+	(*e.consumer)(ctx, event)
+	return nil
+}
+
+func (e subscribedBusStub) Subscribe(consumer events.Consumer, _ ...string) {
+	*e.consumer = consumer
+}
@@ -10,11 +10,31 @@ import (
 
 // PlayerState represents a player state.
 type PlayerState struct {
-	UserID     string
-	Name       string
-	VotedCard  *games.Card
-	Confidence string
-	CanReveal  bool
+	UserID      string
+	Handle      string
+	Name        string
+	VotedCard   *games.Card
+	Confidence  string
+	CanReveal   bool
+	Active      bool
+	Facilitator bool
+	Seat        int
+	// VotedCards are cards voted per dimension name in dimensional decks.
+	VotedCards map[string]games.Card
+}
+
+// AttemptVoteState represents a player card in an archived attempt.
+type AttemptVoteState struct {
+	Handle string
+	Name   string
+	Card   games.Card
+	Cards  map[string]games.Card
+}
+
+// AttemptState represents an archived voting attempt of the current ticket.
+type AttemptState struct {
+	Number int
+	Votes  []AttemptVoteState
 }
 
 // GameState represents a game state.
@@ -25,37 +45,99 @@ type GameState struct {
 	CardsDeck games.CardsDeck
 	Players   []PlayerState
 	State     string
+	// Protected is true when new players should provide the passcode to join the game.
+	Protected bool
+	// Attempts are archived attempts of the current ticket, the oldest first.
+	Attempts []AttemptState
+	// RevoteSuggested is true when the revealed votes spread is wider than the game re-vote threshold.
+	RevoteSuggested bool
+	// Estimate is a summary of the revealed votes per dimension, it is nil until the cards are revealed.
+	Estimate *games.Estimate
+	// Anonymous is true when revealed cards should never be shown next to player names.
+	Anonymous bool
+	// ChatDisabled is true when players can not send chat messages and reactions.
+	ChatDisabled bool
 }
 
-// NewStateForGame creates a new game state.
+// NewStateForGame creates a new game state, players are ordered by their seats.
 func NewStateForGame(game games.Game, gamers []users.User) GameState {
 	state := GameState{
-		GameID:    game.ID(),
-		CardsDeck: game.CardsDeck(),
-		Name:      game.Name(),
-		TicketURL: game.TicketURL(),
-		Players:   make([]PlayerState, 0, len(game.Players())),
-		State:     game.State(),
+		GameID:          game.ID(),
+		CardsDeck:       game.CardsDeck(),
+		Name:            game.Name(),
+		TicketURL:       game.TicketURL(),
+		Players:         make([]PlayerState, 0, len(game.Players())),
+		State:           game.State(),
+		Protected:       game.IsProtected(),
+		Attempts:        make([]AttemptState, 0, len(game.Attempts())),
+		RevoteSuggested: game.RevoteSuggested(),
+		Anonymous:       game.IsAnonymous(),
+		ChatDisabled:    game.IsChatDisabled(),
 	}
 
-	for uid, p := range game.Players() {
-		userName := "Unknown"
-		if u := findUserInListByID(uid, gamers); u != nil {
-			userName = u.Name()
-		}
+	for _, uid := range game.PlayerIDs() {
+		p := game.Players()[uid]
+		userName := userNameByID(uid, gamers)
 
 		state.Players = append(state.Players, PlayerState{
-			UserID:     uid,
-			Name:       userName,
-			VotedCard:  p.VotedCard,
-			Confidence: p.Confidence,
-			CanReveal:  p.CanReveal,
+			UserID:      uid,
+			Handle:      p.Handle,
+			Name:        userName,
+			VotedCard:   p.VotedCard,
+			VotedCards:  p.VotedCards,
+			Confidence:  p.Confidence,
+			CanReveal:   p.CanReveal,
+			Active:      p.Active,
+			Facilitator: game.IsFacilitator(uid),
+			Seat:        p.Seat,
 		})
 	}
 
+	if game.State() == games.GameStateFinished {
+		estimate := game.Estimate()
+		state.Estimate = &estimate
+	}
+
+	for _, a := range game.Attempts() {
+		attempt := AttemptState{Number: a.Number, Votes: make([]AttemptVoteState, len(a.Votes))}
+		for i, v := range a.Votes {
+			attempt.Votes[i] = AttemptVoteState{
+				Handle: v.Handle, Name: userNameByID(v.UserID, gamers), Card: v.Card, Cards: v.Cards,
+			}
+		}
+		state.Attempts = append(state.Attempts, attempt)
+	}
+
 	return state
 }
 
+// UserIDs returns IDs of users shown in the game state, these are players and voters of archived attempts.
+func UserIDs(game games.Game) []string {
+	ids := game.PlayerIDs()
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+
+	for _, a := range game.Attempts() {
+		for _, v := range a.Votes {
+			if !seen[v.UserID] {
+				seen[v.UserID] = true
+				ids = append(ids, v.UserID)
+			}
+		}
+	}
+
+	return ids
+}
+
+func userNameByID(id string, users []users.User) string {
+	if u := findUserInListByID(id, users); u != nil {
+		return u.Name()
+	}
+	return "Unknown"
+}
+
 func findUserInListByID(id string, users []users.User) *users.User {
 	for _, u := range users {
 		if u.ID() == id {
@@ -0,0 +1,155 @@
+package teams
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxNameLength is the maximal team name length in characters.
+const MaxNameLength = 64
+
+// CreateTeamCommand is a team creation command.
+type CreateTeamCommand struct {
+	UserID   string
+	Name     string
+	Settings Settings
+}
+
+// NewCreateTeamCommand creates a new command instance, the name is trimmed.
+func NewCreateTeamCommand(name, userID string, settings Settings) (*CreateTeamCommand, error) {
+	name, err := validateName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateTeamCommand{
+		UserID:   userID,
+		Name:     name,
+		Settings: settings,
+	}, nil
+}
+
+// UpdateTeamCommand is a team name and settings update command.
+type UpdateTeamCommand struct {
+	TeamID   string
+	UserID   string
+	Name     string
+	Settings Settings
+}
+
+// NewUpdateTeamCommand creates a new command instance, the name is trimmed.
+func NewUpdateTeamCommand(teamID, userID, name string, settings Settings) (*UpdateTeamCommand, error) {
+	name, err := validateName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateTeamCommand{
+		TeamID:   teamID,
+		UserID:   userID,
+		Name:     name,
+		Settings: settings,
+	}, nil
+}
+
+// JoinTeamCommand is a command to become a team member.
+type JoinTeamCommand struct {
+	JoinCode string
+	UserID   string
+}
+
+// NewJoinTeamCommand creates a new command instance.
+func NewJoinTeamCommand(joinCode, userID string) (*JoinTeamCommand, error) {
+	if joinCode == "" {
+		return nil, ErrWrongJoinCode
+	}
+
+	return &JoinTeamCommand{
+		JoinCode: joinCode,
+		UserID:   userID,
+	}, nil
+}
+
+// ChangeRoleCommand is a command to change the role of a member.
+type ChangeRoleCommand struct {
+	TeamID string
+	UserID string
+	// Handle is a public handle of the member whose role is changed.
+	Handle string
+	Role   string
+}
+
+// NewChangeRoleCommand creates a new command instance.
+func NewChangeRoleCommand(teamID, userID, handle, role string) (*ChangeRoleCommand, error) {
+	if role != RoleOwner && role != RoleMember {
+		return nil, ErrUnknownRole
+	}
+
+	return &ChangeRoleCommand{
+		TeamID: teamID,
+		UserID: userID,
+		Handle: handle,
+		Role:   role,
+	}, nil
+}
+
+// RemoveMemberCommand is a command to remove a member from the team.
+type RemoveMemberCommand struct {
+	TeamID string
+	UserID string
+	// Handle is a public handle of the removed member.
+	Handle string
+}
+
+// NewRemoveMemberCommand creates a new command instance.
+func NewRemoveMemberCommand(teamID, userID, handle string) (*RemoveMemberCommand, error) {
+	return &RemoveMemberCommand{
+		TeamID: teamID,
+		UserID: userID,
+		Handle: handle,
+	}, nil
+}
+
+// RotateJoinCodeCommand is a command to replace the team join code.
+type RotateJoinCodeCommand struct {
+	TeamID string
+	UserID string
+}
+
+// NewRotateJoinCodeCommand creates a new command instance.
+func NewRotateJoinCodeCommand(teamID, userID string) (*RotateJoinCodeCommand, error) {
+	return &RotateJoinCodeCommand{
+		TeamID: teamID,
+		UserID: userID,
+	}, nil
+}
+
+// CreateGameCommand is a command to create a game with the team settings.
+type CreateGameCommand struct {
+	TeamID    string
+	UserID    string
+	Name      string
+	TicketURL string
+}
+
+// NewCreateGameCommand creates a new command instance, the game data is validated on the game command creation.
+func NewCreateGameCommand(teamID, userID, name, ticketURL string) (*CreateGameCommand, error) {
+	return &CreateGameCommand{
+		TeamID:    teamID,
+		UserID:    userID,
+		Name:      name,
+		TicketURL: ticketURL,
+	}, nil
+}
+
+func validateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrEmptyTeamName
+	}
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return "", ErrTeamNameTooLong
+	}
+
+	return name, nil
+}
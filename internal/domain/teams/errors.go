@@ -0,0 +1,26 @@
+package teams
+
+import "planningpoker/internal/domain"
+
+var (
+	// ErrTeamNotFound is returned when the team does not exist.
+	ErrTeamNotFound = domain.NewError(domain.KindNotFound, "team_not_found", "team not found")
+	// ErrMemberNotFound is returned when the team has no member with the handle.
+	ErrMemberNotFound = domain.NewError(domain.KindNotFound, "member_not_found", "team member not found")
+	// ErrNotAMember is returned when the user tries to act in a team they did not join.
+	ErrNotAMember = domain.NewError(domain.KindForbidden, "not_a_member", "user is not a team member")
+	// ErrNotAnOwner is returned when the action is allowed for team owners only.
+	ErrNotAnOwner = domain.NewError(domain.KindForbidden, "not_an_owner", "user is not a team owner")
+	// ErrWrongJoinCode is returned when the user tries to join the team with an unknown or rotated code.
+	ErrWrongJoinCode = domain.NewError(domain.KindForbidden, "wrong_join_code", "wrong team join code")
+	// ErrLastOwner is returned when the action would leave the team without owners.
+	ErrLastOwner = domain.NewError(domain.KindConflict, "last_owner", "team should have at least one owner")
+	// ErrEmptyTeamName is returned when the team name is not provided.
+	ErrEmptyTeamName = domain.NewError(domain.KindValidation, "invalid_team_name", "team name should be provided")
+	// ErrTeamNameTooLong is returned when the team name is longer than MaxNameLength.
+	ErrTeamNameTooLong = domain.NewError(
+		domain.KindValidation, "invalid_team_name", "team name should be at most 64 characters long",
+	)
+	// ErrUnknownRole is returned when the role is neither owner nor member.
+	ErrUnknownRole = domain.NewError(domain.KindValidation, "invalid_role", "role should be owner or member")
+)
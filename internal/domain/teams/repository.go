@@ -0,0 +1,28 @@
+package teams
+
+import (
+	"context"
+
+	"planningpoker/internal/domain/games"
+)
+
+// Repository is a repository contract to fetch/persist teams.
+type Repository interface {
+	ModifyExclusively(ctx context.Context, id string, cb func(team *Team) error) error
+	Get(ctx context.Context, id string) (*Team, error)
+	Save(ctx context.Context, team *Team) error
+	// GetByJoinCode returns the team with the join code, nil if there is no such team.
+	GetByJoinCode(ctx context.Context, joinCode string) (*Team, error)
+	GetTeamsByMemberID(ctx context.Context, userID string) ([]Team, error)
+}
+
+// GameRepository is a contract to fetch team games.
+type GameRepository interface {
+	// GetGamesByTeamID returns all games of the team, both running and archived ones.
+	GetGamesByTeamID(ctx context.Context, teamID string) ([]games.Game, error)
+}
+
+// GamesService is a contract to create games.
+type GamesService interface {
+	Create(ctx context.Context, cmd games.CreateGameCommand) (string, error)
+}
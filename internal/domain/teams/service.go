@@ -0,0 +1,186 @@
+package teams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"planningpoker/internal/domain/games"
+)
+
+// Service is a teams related application service.
+type Service struct {
+	teamsRepo    Repository
+	gamesRepo    GameRepository
+	gamesService GamesService
+}
+
+// Games are team games split by their state, each list is ordered by the last activity, the most recent first.
+type Games struct {
+	Active []games.Game
+	// Past contains archived games.
+	Past []games.Game
+}
+
+// NewService creates a new teams service instance.
+func NewService(tr Repository, gr GameRepository, gs GamesService) (*Service, error) {
+	if tr == nil {
+		return nil, errors.New("teams repository should be provided")
+	}
+	if gr == nil {
+		return nil, errors.New("games repository should be provided")
+	}
+	if gs == nil {
+		return nil, errors.New("games service should be provided")
+	}
+
+	return &Service{
+		teamsRepo:    tr,
+		gamesRepo:    gr,
+		gamesService: gs,
+	}, nil
+}
+
+// Create creates a new team owned by the user.
+func (s *Service) Create(ctx context.Context, cmd CreateTeamCommand) (*Team, error) {
+	team := NewTeam(cmd)
+	if err := s.teamsRepo.Save(ctx, team); err != nil {
+		return nil, err
+	}
+
+	return team, nil
+}
+
+// Get returns the team, only members can see it.
+func (s *Service) Get(ctx context.Context, teamID, userID string) (*Team, error) {
+	team, err := s.teamsRepo.Get(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("get team: %w", err)
+	}
+	if team == nil {
+		return nil, ErrTeamNotFound
+	}
+	if !team.IsMember(userID) {
+		return nil, ErrNotAMember
+	}
+
+	return team, nil
+}
+
+// ListByMember returns all teams of the user ordered by name.
+func (s *Service) ListByMember(ctx context.Context, userID string) ([]Team, error) {
+	list, err := s.teamsRepo.GetTeamsByMemberID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Name() != list[j].Name() {
+			return list[i].Name() < list[j].Name()
+		}
+		return list[i].ID() < list[j].ID()
+	})
+
+	return list, nil
+}
+
+// Update changes the team name and settings.
+func (s *Service) Update(ctx context.Context, cmd UpdateTeamCommand) error {
+	return s.teamsRepo.ModifyExclusively(ctx, cmd.TeamID, func(team *Team) error {
+		return team.Update(cmd)
+	})
+}
+
+// Join adds the user to the team with the join code.
+func (s *Service) Join(ctx context.Context, cmd JoinTeamCommand) (*Team, error) {
+	found, err := s.teamsRepo.GetByJoinCode(ctx, cmd.JoinCode)
+	if err != nil {
+		return nil, fmt.Errorf("get team: %w", err)
+	}
+	if found == nil {
+		return nil, ErrWrongJoinCode
+	}
+
+	var joined *Team
+	err = s.teamsRepo.ModifyExclusively(ctx, found.ID(), func(team *Team) error {
+		// the code might be rotated since the team was found, the team checks it again
+		joined = team
+		return team.Join(cmd)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return joined, nil
+}
+
+// ChangeRole changes the role of a member.
+func (s *Service) ChangeRole(ctx context.Context, cmd ChangeRoleCommand) error {
+	return s.teamsRepo.ModifyExclusively(ctx, cmd.TeamID, func(team *Team) error {
+		return team.ChangeRole(cmd)
+	})
+}
+
+// RemoveMember removes a member from the team.
+func (s *Service) RemoveMember(ctx context.Context, cmd RemoveMemberCommand) error {
+	return s.teamsRepo.ModifyExclusively(ctx, cmd.TeamID, func(team *Team) error {
+		return team.RemoveMember(cmd)
+	})
+}
+
+// RotateJoinCode replaces the team join code and returns the new one.
+func (s *Service) RotateJoinCode(ctx context.Context, cmd RotateJoinCodeCommand) (string, error) {
+	code := ""
+	err := s.teamsRepo.ModifyExclusively(ctx, cmd.TeamID, func(team *Team) error {
+		if err := team.RotateJoinCode(cmd); err != nil {
+			return err
+		}
+		code = team.JoinCode()
+		return nil
+	})
+
+	return code, err
+}
+
+// CreateGame creates a game with the team settings and returns its ID.
+func (s *Service) CreateGame(ctx context.Context, cmd CreateGameCommand) (string, error) {
+	team, err := s.Get(ctx, cmd.TeamID, cmd.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	gameCmd, err := team.NewGameCommand(cmd)
+	if err != nil {
+		return "", err
+	}
+
+	return s.gamesService.Create(ctx, *gameCmd)
+}
+
+// Games returns the team games, only members can see them.
+func (s *Service) Games(ctx context.Context, teamID, userID string) (*Games, error) {
+	if _, err := s.Get(ctx, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	list, err := s.gamesRepo.GetGamesByTeamID(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("get team games: %w", err)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].LastActivityAt().After(list[j].LastActivityAt())
+	})
+
+	result := &Games{Active: make([]games.Game, 0), Past: make([]games.Game, 0)}
+	for _, g := range list {
+		if g.State() == games.GameStateArchived {
+			result.Past = append(result.Past, g)
+		} else {
+			result.Active = append(result.Active, g)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,232 @@
+package teams_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/teams"
+	"planningpoker/test"
+)
+
+func TestNewService(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		teamsRepo    teams.Repository
+		gamesRepo    teams.GameRepository
+		gamesService teams.GamesService
+		expError     string
+	}{
+		"success": {
+			teamsRepo:    &teamsRepoStub{},
+			gamesRepo:    gamesRepoStub{},
+			gamesService: &gamesServiceStub{},
+		},
+		"fail on no teams repo": {
+			gamesRepo:    gamesRepoStub{},
+			gamesService: &gamesServiceStub{},
+			expError:     "teams repository should be provided",
+		},
+		"fail on no games repo": {
+			teamsRepo:    &teamsRepoStub{},
+			gamesService: &gamesServiceStub{},
+			expError:     "games repository should be provided",
+		},
+		"fail on no games service": {
+			teamsRepo: &teamsRepoStub{},
+			gamesRepo: gamesRepoStub{},
+			expError:  "games service should be provided",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			srv, err := teams.NewService(tt.teamsRepo, tt.gamesRepo, tt.gamesService)
+
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				assert.Nil(t, srv)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, srv)
+			}
+		})
+	}
+}
+
+func TestService_Get(t *testing.T) {
+	t.Parallel()
+
+	team := test.NewTeam(t, test.User1)
+
+	testCases := map[string]struct {
+		teamsRepo *teamsRepoStub
+		userID    string
+		expError  string
+	}{
+		"success": {
+			teamsRepo: &teamsRepoStub{team: team},
+			userID:    test.User1,
+		},
+		"fail on not a member": {
+			teamsRepo: &teamsRepoStub{team: team},
+			userID:    test.User2,
+			expError:  "user is not a team member",
+		},
+		"fail on unknown team": {
+			teamsRepo: &teamsRepoStub{},
+			userID:    test.User1,
+			expError:  "team not found",
+		},
+		"fail on repo error": {
+			teamsRepo: &teamsRepoStub{err: errors.New("failed")},
+			userID:    test.User1,
+			expError:  "get team: failed",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			srv, err := teams.NewService(tt.teamsRepo, gamesRepoStub{}, &gamesServiceStub{})
+			require.NoError(t, err)
+
+			got, err := srv.Get(context.Background(), team.ID(), tt.userID)
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				assert.Nil(t, got)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, team.ID(), got.ID())
+		})
+	}
+}
+
+func TestService_Join(t *testing.T) {
+	t.Parallel()
+
+	team := test.NewTeam(t, test.User1)
+	srv, err := teams.NewService(&teamsRepoStub{team: team}, gamesRepoStub{}, &gamesServiceStub{})
+	require.NoError(t, err)
+
+	_, err = srv.Join(context.Background(), teams.JoinTeamCommand{JoinCode: "unknown", UserID: test.User2})
+	assert.ErrorIs(t, err, teams.ErrWrongJoinCode)
+
+	joined, err := srv.Join(context.Background(), teams.JoinTeamCommand{JoinCode: team.JoinCode(), UserID: test.User2})
+	require.NoError(t, err)
+	assert.True(t, joined.IsMember(test.User2))
+}
+
+func TestService_CreateGame(t *testing.T) {
+	t.Parallel()
+
+	team := test.NewTeam(t, test.User1)
+	gs := &gamesServiceStub{}
+	srv, err := teams.NewService(&teamsRepoStub{team: team}, gamesRepoStub{}, gs)
+	require.NoError(t, err)
+
+	_, err = srv.CreateGame(context.Background(), teams.CreateGameCommand{TeamID: team.ID(), UserID: test.User2})
+	assert.ErrorIs(t, err, teams.ErrNotAMember)
+
+	id, err := srv.CreateGame(context.Background(), teams.CreateGameCommand{
+		TeamID: team.ID(), UserID: test.User1, Name: "game",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "game-id", id)
+	assert.Equal(t, team.ID(), gs.cmd.TeamID)
+	assert.Equal(t, team.Settings().CardsDeck, gs.cmd.CardsDeck)
+}
+
+func TestService_Games(t *testing.T) {
+	t.Parallel()
+
+	team := test.NewTeam(t, test.User1)
+	now := time.Now()
+	newGame := func(id, state string, lastActivityAt time.Time) games.Game {
+		return *games.NewRaw(id, "", "", test.NewTestDeck(t), map[string]*games.Player{}, state, false, "",
+			lastActivityAt, nil, team.ID(), nil, 0, false, false)
+	}
+
+	srv, err := teams.NewService(&teamsRepoStub{team: team}, gamesRepoStub{list: []games.Game{
+		newGame("old", games.GameStateStarted, now.Add(-time.Hour)),
+		newGame("archived", games.GameStateArchived, now.Add(-2*time.Hour)),
+		newGame("recent", games.GameStateFinished, now),
+	}}, &gamesServiceStub{})
+	require.NoError(t, err)
+
+	_, err = srv.Games(context.Background(), team.ID(), test.User2)
+	assert.ErrorIs(t, err, teams.ErrNotAMember)
+
+	list, err := srv.Games(context.Background(), team.ID(), test.User1)
+	require.NoError(t, err)
+	require.Len(t, list.Active, 2)
+	assert.Equal(t, "recent", list.Active[0].ID())
+	assert.Equal(t, "old", list.Active[1].ID())
+	require.Len(t, list.Past, 1)
+	assert.Equal(t, "archived", list.Past[0].ID())
+}
+
+type teamsRepoStub struct {
+	team *teams.Team
+	err  error
+}
+
+func (r *teamsRepoStub) ModifyExclusively(_ context.Context, _ string, cb func(team *teams.Team) error) error {
+	if r.team == nil {
+		return teams.ErrTeamNotFound
+	}
+	return cb(r.team)
+}
+
+func (r *teamsRepoStub) Get(context.Context, string) (*teams.Team, error) {
+	return r.team, r.err
+}
+
+func (r *teamsRepoStub) Save(_ context.Context, team *teams.Team) error {
+	r.team = team
+	return r.err
+}
+
+func (r *teamsRepoStub) GetByJoinCode(_ context.Context, joinCode string) (*teams.Team, error) {
+	if r.team == nil || r.team.JoinCode() != joinCode {
+		return nil, r.err
+	}
+	return r.team, r.err
+}
+
+func (r *teamsRepoStub) GetTeamsByMemberID(context.Context, string) ([]teams.Team, error) {
+	if r.team == nil {
+		return nil, r.err
+	}
+	return []teams.Team{*r.team}, r.err
+}
+
+type gamesRepoStub struct {
+	list []games.Game
+}
+
+func (r gamesRepoStub) GetGamesByTeamID(context.Context, string) ([]games.Game, error) {
+	list := make([]games.Game, len(r.list))
+	copy(list, r.list)
+	return list, nil
+}
+
+type gamesServiceStub struct {
+	cmd games.CreateGameCommand
+}
+
+func (s *gamesServiceStub) Create(_ context.Context, cmd games.CreateGameCommand) (string, error) {
+	s.cmd = cmd
+	return "game-id", nil
+}
@@ -0,0 +1,289 @@
+// Package teams contains domain level teams logic.
+package teams
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"planningpoker/internal/domain"
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/games"
+)
+
+const (
+	// RoleOwner is a role of a member who manages the team settings and members.
+	RoleOwner = "owner"
+	// RoleMember is a role of a member who plays the team games.
+	RoleMember = "member"
+)
+
+// Team is a domain aggregate of users playing games together with shared settings.
+type Team struct {
+	domain.BaseAggregate
+	id        string
+	name      string
+	members   map[string]*Member
+	settings  Settings
+	joinCode  string
+	createdAt time.Time
+}
+
+// Member is an entity of a team member.
+type Member struct {
+	Role string
+	// Handle is a stable public member identifier within the team, unlike user ID it is safe to share.
+	Handle   string
+	JoinedAt time.Time
+}
+
+// Settings are defaults of games created by the team.
+type Settings struct {
+	CardsDeck         games.CardsDeck
+	EveryoneCanReveal bool
+}
+
+// NewTeam creates a new team, the creator becomes its owner.
+func NewTeam(cmd CreateTeamCommand) *Team {
+	t := &Team{
+		id:        newID(),
+		name:      cmd.Name,
+		members:   make(map[string]*Member),
+		settings:  cmd.Settings,
+		joinCode:  newID(),
+		createdAt: time.Now(),
+	}
+	t.members[cmd.UserID] = &Member{Role: RoleOwner, Handle: newMemberHandle(), JoinedAt: t.createdAt}
+	t.setChanged()
+
+	return t
+}
+
+// NewRaw instantiates a team aggregate from raw data.
+// It should never be used in any logic except aggregate hydration from any serialized format (db, etc...)
+func NewRaw(
+	id, name string, members map[string]*Member, settings Settings, joinCode string, createdAt time.Time,
+) *Team {
+	return &Team{
+		id:        id,
+		name:      name,
+		members:   members,
+		settings:  settings,
+		joinCode:  joinCode,
+		createdAt: createdAt,
+	}
+}
+
+// ID returns the team ID.
+func (t Team) ID() string {
+	return t.id
+}
+
+// Name returns the team name.
+func (t Team) Name() string {
+	return t.name
+}
+
+// Members returns all team members by user IDs.
+func (t Team) Members() map[string]*Member {
+	return t.members
+}
+
+// Settings returns defaults of the team games.
+func (t Team) Settings() Settings {
+	return t.settings
+}
+
+// JoinCode returns a secret code users join the team with, it should be shown to owners only.
+func (t Team) JoinCode() string {
+	return t.joinCode
+}
+
+// CreatedAt returns the time of the team creation.
+func (t Team) CreatedAt() time.Time {
+	return t.createdAt
+}
+
+// IsMember checks if specific user is a team member.
+func (t Team) IsMember(uid string) bool {
+	_, ok := t.members[uid]
+	return ok
+}
+
+// IsOwner checks if specific user is a team owner.
+func (t Team) IsOwner(uid string) bool {
+	m, ok := t.members[uid]
+	return ok && m.Role == RoleOwner
+}
+
+// MemberIDs returns user IDs of all members ordered by the time they joined.
+func (t Team) MemberIDs() []string {
+	ids := make([]string, 0, len(t.members))
+	for id := range t.members {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		ti, tj := t.members[ids[i]].JoinedAt, t.members[ids[j]].JoinedAt
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
+
+// Update changes the team name and settings, only owners can do that.
+// Games which are already created keep their settings.
+func (t *Team) Update(cmd UpdateTeamCommand) error {
+	if err := t.checkOwner(cmd.UserID); err != nil {
+		return err
+	}
+
+	t.name = cmd.Name
+	t.settings = cmd.Settings
+	t.setChanged()
+
+	return nil
+}
+
+// Join adds a new member to the team, joining twice is not an error.
+func (t *Team) Join(cmd JoinTeamCommand) error {
+	if cmd.JoinCode != t.joinCode {
+		return ErrWrongJoinCode
+	}
+
+	if t.IsMember(cmd.UserID) {
+		return nil
+	}
+
+	t.members[cmd.UserID] = &Member{Role: RoleMember, Handle: newMemberHandle(), JoinedAt: time.Now()}
+	t.setChanged()
+
+	return nil
+}
+
+// ChangeRole changes the role of the member, only owners can do that.
+func (t *Team) ChangeRole(cmd ChangeRoleCommand) error {
+	if err := t.checkOwner(cmd.UserID); err != nil {
+		return err
+	}
+
+	uid, m := t.memberByHandle(cmd.Handle)
+	if m == nil {
+		return ErrMemberNotFound
+	}
+
+	if m.Role == RoleOwner && cmd.Role != RoleOwner && t.isLastOwner(uid) {
+		return ErrLastOwner
+	}
+
+	m.Role = cmd.Role
+	t.setChanged()
+
+	return nil
+}
+
+// RemoveMember removes the member from the team, owners can remove anyone and members can remove themselves.
+func (t *Team) RemoveMember(cmd RemoveMemberCommand) error {
+	if !t.IsMember(cmd.UserID) {
+		return ErrNotAMember
+	}
+
+	uid, m := t.memberByHandle(cmd.Handle)
+	if m == nil {
+		return ErrMemberNotFound
+	}
+
+	if uid != cmd.UserID && !t.IsOwner(cmd.UserID) {
+		return ErrNotAnOwner
+	}
+
+	if t.isLastOwner(uid) {
+		return ErrLastOwner
+	}
+
+	delete(t.members, uid)
+	t.setChanged()
+
+	return nil
+}
+
+// RotateJoinCode replaces the join code, so the previous one can not be used anymore.
+func (t *Team) RotateJoinCode(cmd RotateJoinCodeCommand) error {
+	if err := t.checkOwner(cmd.UserID); err != nil {
+		return err
+	}
+
+	t.joinCode = newID()
+	t.setChanged()
+
+	return nil
+}
+
+// NewGameCommand creates a command of a team game creation, the game inherits the team settings.
+func (t Team) NewGameCommand(cmd CreateGameCommand) (*games.CreateGameCommand, error) {
+	if !t.IsMember(cmd.UserID) {
+		return nil, ErrNotAMember
+	}
+
+	gameCmd, err := games.NewCreateGameCommand(
+		cmd.Name, cmd.TicketURL, cmd.UserID, t.settings.CardsDeck, t.settings.EveryoneCanReveal,
+	)
+	if err != nil {
+		return nil, err
+	}
+	gameCmd.TeamID = t.id
+
+	return gameCmd, nil
+}
+
+func (t Team) checkOwner(uid string) error {
+	if !t.IsMember(uid) {
+		return ErrNotAMember
+	}
+	if !t.IsOwner(uid) {
+		return ErrNotAnOwner
+	}
+
+	return nil
+}
+
+func (t Team) memberByHandle(handle string) (string, *Member) {
+	for uid, m := range t.members {
+		if m.Handle == handle {
+			return uid, m
+		}
+	}
+
+	return "", nil
+}
+
+func (t Team) isLastOwner(uid string) bool {
+	if !t.IsOwner(uid) {
+		return false
+	}
+
+	for id, m := range t.members {
+		if id != uid && m.Role == RoleOwner {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t *Team) setChanged() {
+	t.AddEvent(events.NewDomainEventBuilder(events.EventTypeTeamUpdated).ForAggregate(t.id).Build())
+}
+
+func newID() string {
+	return strings.ReplaceAll(uuid.New().String(), "-", "")
+}
+
+func newMemberHandle() string {
+	return newID()[:12]
+}
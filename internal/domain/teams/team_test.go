@@ -0,0 +1,165 @@
+package teams_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/teams"
+	"planningpoker/test"
+)
+
+func TestNewTeam(t *testing.T) {
+	t.Parallel()
+
+	team := test.NewTeam(t, test.User1)
+
+	assert.NotEmpty(t, team.ID())
+	assert.NotEmpty(t, team.JoinCode())
+	assert.True(t, team.IsOwner(test.User1))
+	assert.NotEmpty(t, team.Members()[test.User1].Handle)
+	require.Len(t, team.GetEvents(), 1)
+	assert.Equal(t, events.EventTypeTeamUpdated, team.GetEvents()[0].EventType())
+}
+
+func TestNewCreateTeamCommand(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		name     string
+		expName  string
+		expError string
+	}{
+		"success":                 {name: "Team", expName: "Team"},
+		"success on trimmed name": {name: "  Team \n", expName: "Team"},
+		"fail on blank name":      {name: "  ", expError: "team name should be provided"},
+		"fail on too long name": {
+			name:     strings.Repeat("a", teams.MaxNameLength+1),
+			expError: "team name should be at most 64 characters long",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, err := teams.NewCreateTeamCommand(tt.name, test.User1, teams.Settings{})
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				assert.Nil(t, cmd)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expName, cmd.Name)
+		})
+	}
+}
+
+func TestTeam_Join(t *testing.T) {
+	t.Parallel()
+
+	team := test.NewTeam(t, test.User1)
+
+	err := team.Join(teams.JoinTeamCommand{JoinCode: "wrong", UserID: test.User2})
+	assert.ErrorIs(t, err, teams.ErrWrongJoinCode)
+	assert.False(t, team.IsMember(test.User2))
+
+	test.JoinTeam(t, team, test.User2)
+	handle := team.Members()[test.User2].Handle
+	assert.Equal(t, teams.RoleMember, team.Members()[test.User2].Role)
+	assert.NotEqual(t, team.Members()[test.User1].Handle, handle)
+
+	test.JoinTeam(t, team, test.User2)
+	assert.Equal(t, handle, team.Members()[test.User2].Handle)
+	assert.Equal(t, []string{test.User1, test.User2}, team.MemberIDs())
+
+	code := team.JoinCode()
+	assert.ErrorIs(t, team.RotateJoinCode(teams.RotateJoinCodeCommand{UserID: test.User2}), teams.ErrNotAnOwner)
+	require.NoError(t, team.RotateJoinCode(teams.RotateJoinCodeCommand{UserID: test.User1}))
+	assert.NotEqual(t, code, team.JoinCode())
+
+	err = team.Join(teams.JoinTeamCommand{JoinCode: code, UserID: test.User3})
+	assert.ErrorIs(t, err, teams.ErrWrongJoinCode)
+}
+
+func TestTeam_Members(t *testing.T) {
+	t.Parallel()
+
+	team := test.NewTeam(t, test.User1)
+	test.JoinTeam(t, team, test.User2)
+	test.JoinTeam(t, team, test.User3)
+	owner := team.Members()[test.User1].Handle
+	member := team.Members()[test.User2].Handle
+
+	changeRole := func(uid, handle, role string) error {
+		cmd, err := teams.NewChangeRoleCommand(team.ID(), uid, handle, role)
+		require.NoError(t, err)
+		return team.ChangeRole(*cmd)
+	}
+	remove := func(uid, handle string) error {
+		cmd, err := teams.NewRemoveMemberCommand(team.ID(), uid, handle)
+		require.NoError(t, err)
+		return team.RemoveMember(*cmd)
+	}
+
+	_, err := teams.NewChangeRoleCommand(team.ID(), test.User1, member, "admin")
+	assert.EqualError(t, err, "role should be owner or member")
+
+	assert.ErrorIs(t, changeRole(test.User2, member, teams.RoleOwner), teams.ErrNotAnOwner)
+	assert.ErrorIs(t, changeRole(test.User1, "unknown", teams.RoleOwner), teams.ErrMemberNotFound)
+	assert.ErrorIs(t, changeRole(test.User1, owner, teams.RoleMember), teams.ErrLastOwner)
+	assert.ErrorIs(t, remove(test.User1, owner), teams.ErrLastOwner)
+	assert.ErrorIs(t, remove(test.User2, team.Members()[test.User3].Handle), teams.ErrNotAnOwner)
+	assert.ErrorIs(t, remove("stranger", member), teams.ErrNotAMember)
+
+	require.NoError(t, changeRole(test.User1, member, teams.RoleOwner))
+	assert.True(t, team.IsOwner(test.User2))
+	require.NoError(t, remove(test.User1, owner))
+	assert.False(t, team.IsMember(test.User1))
+
+	require.NoError(t, remove(test.User3, team.Members()[test.User3].Handle))
+	assert.Equal(t, []string{test.User2}, team.MemberIDs())
+}
+
+func TestTeam_Update(t *testing.T) {
+	t.Parallel()
+
+	team := test.NewTeam(t, test.User1)
+	test.JoinTeam(t, team, test.User2)
+	settings := teams.Settings{CardsDeck: test.NewTestDeck(t), EveryoneCanReveal: true}
+
+	cmd, err := teams.NewUpdateTeamCommand(team.ID(), test.User2, "new", settings)
+	require.NoError(t, err)
+	assert.ErrorIs(t, team.Update(*cmd), teams.ErrNotAnOwner)
+
+	cmd.UserID = test.User1
+	require.NoError(t, team.Update(*cmd))
+	assert.Equal(t, "new", team.Name())
+	assert.Equal(t, settings, team.Settings())
+}
+
+func TestTeam_NewGameCommand(t *testing.T) {
+	t.Parallel()
+
+	team := test.NewTeam(t, test.User1)
+	test.JoinTeam(t, team, test.User2)
+
+	_, err := team.NewGameCommand(teams.CreateGameCommand{TeamID: team.ID(), UserID: test.User3, Name: "game"})
+	assert.ErrorIs(t, err, teams.ErrNotAMember)
+
+	_, err = team.NewGameCommand(teams.CreateGameCommand{TeamID: team.ID(), UserID: test.User2, TicketURL: "file:///"})
+	assert.EqualError(t, err, "ticket URL should be an absolute http or https URL")
+
+	cmd, err := team.NewGameCommand(teams.CreateGameCommand{TeamID: team.ID(), UserID: test.User2, Name: " game "})
+	require.NoError(t, err)
+	assert.Equal(t, "game", cmd.Name)
+	assert.Equal(t, test.User2, cmd.UserID)
+	assert.Equal(t, team.ID(), cmd.TeamID)
+	assert.Equal(t, team.Settings().CardsDeck, cmd.CardsDeck)
+	assert.Equal(t, team.Settings().EveryoneCanReveal, cmd.EveryoneCanReveal)
+}
@@ -0,0 +1,12 @@
+package users
+
+import "planningpoker/internal/domain"
+
+var (
+	// ErrUserNotFound is returned when the user does not exist.
+	ErrUserNotFound = domain.NewError(domain.KindNotFound, "user_not_found", "user not found")
+	// ErrEmptyName is returned when the user name is not provided.
+	ErrEmptyName = domain.NewError(domain.KindValidation, "invalid_name", "user name should be provided")
+	// ErrNameTooLong is returned when the user name is longer than MaxNameLength.
+	ErrNameTooLong = domain.NewError(domain.KindValidation, "invalid_name", "user name should be at most 64 characters long")
+)
@@ -1,7 +1,15 @@
 package users
 
+import (
+	"context"
+	"time"
+)
+
 // Repository is a contract to fetch and update users.
 type Repository interface {
-	Get(id string) (*User, error)
-	Save(user User) error
+	Get(ctx context.Context, id string) (*User, error)
+	Save(ctx context.Context, user User) error
+	ModifyExclusively(ctx context.Context, id string, cb func(user *User) error) error
+	Delete(ctx context.Context, id string) error
+	GetNotSeenSince(ctx context.Context, since time.Time) ([]string, error)
 }
@@ -1,10 +1,15 @@
 package users
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 )
 
+// seenResolution limits how often the last seen time is persisted, there is no need to save on every request.
+const seenResolution = time.Minute
+
 // Service is a user related application service.
 type Service struct {
 	usersRepo Repository
@@ -22,13 +27,13 @@ func NewService(ur Repository) (*Service, error) {
 }
 
 // Register is a first time registration, without userID known.
-func (s *Service) Register(cmd RegisterCommand) (*User, error) {
+func (s *Service) Register(ctx context.Context, cmd RegisterCommand) (*User, error) {
 	u, err := NewUser(cmd.Name)
 	if err != nil {
 		return nil, fmt.Errorf("user creation: %w", err)
 	}
 
-	if err := s.usersRepo.Save(*u); err != nil {
+	if err := s.usersRepo.Save(ctx, *u); err != nil {
 		return nil, err
 	}
 
@@ -36,40 +41,66 @@ func (s *Service) Register(cmd RegisterCommand) (*User, error) {
 }
 
 // Update updates some user details.
-func (s *Service) Update(cmd UpdateCommand) (*User, error) {
-	u, err := s.usersRepo.Get(cmd.ID)
+func (s *Service) Update(ctx context.Context, cmd UpdateCommand) (*User, error) {
+	var updated *User
+	err := s.usersRepo.ModifyExclusively(ctx, cmd.ID, func(u *User) error {
+		if err := u.NameAs(cmd.Name); err != nil {
+			return err
+		}
+		updated = u
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// Get returns a user entity by user ID.
+func (s *Service) Get(ctx context.Context, userID string) (*User, error) {
+	return s.usersRepo.Get(ctx, userID)
+}
+
+// AuthenticateByID checks that the user with provided ID exists.
+func (s *Service) AuthenticateByID(ctx context.Context, cmd AuthByIDCommand) (*User, error) {
+	u, err := s.usersRepo.Get(ctx, cmd.ID)
 	if err != nil {
 		return nil, err
 	}
 	if u == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
-	if err := u.NameAs(cmd.Name); err != nil {
-		return nil, err
+	if time.Since(u.LastSeenAt()) <= seenResolution {
+		return u, nil
 	}
 
-	if err := s.usersRepo.Save(*u); err != nil {
+	// the user is re-read exclusively, so a concurrent update is not overwritten with the stale copy
+	err = s.usersRepo.ModifyExclusively(ctx, cmd.ID, func(seen *User) error {
+		seen.MarkSeen()
+		u = seen
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return u, nil
 }
 
-// Get returns a user entity by user ID.
-func (s *Service) Get(userID string) (*User, error) {
-	return s.usersRepo.Get(userID)
-}
-
-// AuthenticateByID checks that the user with provided ID exists.
-func (s *Service) AuthenticateByID(cmd AuthByIDCommand) (*User, error) {
-	u, err := s.usersRepo.Get(cmd.ID)
+// DeleteNotSeen deletes users who were not seen for longer than notSeenFor.
+func (s *Service) DeleteNotSeen(ctx context.Context, notSeenFor time.Duration) error {
+	ids, err := s.usersRepo.GetNotSeenSince(ctx, time.Now().Add(-notSeenFor))
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("not seen users fetching: %w", err)
 	}
-	if u == nil {
-		return nil, errors.New("user not found")
+
+	for _, id := range ids {
+		if err := s.usersRepo.Delete(ctx, id); err != nil {
+			return fmt.Errorf("user deletion: %w", err)
+		}
 	}
 
-	return u, nil
+	return nil
 }
@@ -1,8 +1,10 @@
 package users_test
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"planningpoker/internal/domain/users"
 
@@ -78,7 +80,7 @@ func TestService_Register(t *testing.T) {
 			cmd, err := users.NewRegisterCommand(tt.name)
 			require.NoError(t, err)
 
-			u, err := srv.Register(*cmd)
+			u, err := srv.Register(context.Background(), *cmd)
 
 			if tt.expError != "" {
 				assert.EqualError(t, err, tt.expError)
@@ -102,13 +104,13 @@ func TestService_Update(t *testing.T) {
 	}{
 		"success": {
 			usersRepo: usersRepoStub{
-				getUser: users.NewRaw(uid, "foo"),
+				getUser: users.NewRaw(uid, "foo", time.Now()),
 			},
 			name: "foo",
 		},
 		"failed on wrong name": {
 			usersRepo: usersRepoStub{
-				getUser: users.NewRaw(uid, "foo"),
+				getUser: users.NewRaw(uid, "foo", time.Now()),
 			},
 			name:     "",
 			expError: "user name should be provided",
@@ -127,7 +129,7 @@ func TestService_Update(t *testing.T) {
 		},
 		"failed save repository": {
 			usersRepo: usersRepoStub{
-				getUser: users.NewRaw(uid, "foo"),
+				getUser: users.NewRaw(uid, "foo", time.Now()),
 				saveErr: errors.New("save failed"),
 			},
 			name:     "foo",
@@ -146,7 +148,7 @@ func TestService_Update(t *testing.T) {
 			cmd, err := users.NewUpdateCommand(uid, tt.name)
 			require.NoError(t, err)
 
-			u, err := srv.Update(*cmd)
+			u, err := srv.Update(context.Background(), *cmd)
 
 			if tt.expError != "" {
 				assert.EqualError(t, err, tt.expError)
@@ -169,7 +171,7 @@ func TestService_AuthenticateByID(t *testing.T) {
 	}{
 		"success": {
 			usersRepo: usersRepoStub{
-				getUser: users.NewRaw(uid, "foo"),
+				getUser: users.NewRaw(uid, "foo", time.Now()),
 			},
 		},
 		"failed fetch from repository": {
@@ -182,6 +184,19 @@ func TestService_AuthenticateByID(t *testing.T) {
 			usersRepo: usersRepoStub{},
 			expError:  "user not found",
 		},
+		"recently seen user is not saved": {
+			usersRepo: usersRepoStub{
+				getUser: users.NewRaw(uid, "foo", time.Now()),
+				saveErr: errors.New("save failed"),
+			},
+		},
+		"failed save of not recently seen user": {
+			usersRepo: usersRepoStub{
+				getUser: users.NewRaw(uid, "foo", time.Now().Add(-time.Hour)),
+				saveErr: errors.New("save failed"),
+			},
+			expError: "save failed",
+		},
 	}
 
 	for name, tt := range testCases {
@@ -195,7 +210,7 @@ func TestService_AuthenticateByID(t *testing.T) {
 			cmd, err := users.NewAuthByIDCommand(uid)
 			require.NoError(t, err)
 
-			u, err := srv.AuthenticateByID(*cmd)
+			u, err := srv.AuthenticateByID(context.Background(), *cmd)
 
 			if tt.expError != "" {
 				assert.EqualError(t, err, tt.expError)
@@ -208,23 +223,104 @@ func TestService_AuthenticateByID(t *testing.T) {
 	}
 }
 
+func TestService_AuthenticateByIDMarksSeen(t *testing.T) {
+	t.Parallel()
+
+	uid := "1"
+	seenAt := time.Now().Add(-time.Hour)
+	srv, err := users.NewService(usersRepoStub{getUser: users.NewRaw(uid, "foo", seenAt)})
+	require.NoError(t, err)
+
+	cmd, err := users.NewAuthByIDCommand(uid)
+	require.NoError(t, err)
+
+	u, err := srv.AuthenticateByID(context.Background(), *cmd)
+	require.NoError(t, err)
+	require.NotNil(t, u)
+	assert.True(t, u.LastSeenAt().After(seenAt))
+}
+
+func TestService_DeleteNotSeen(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		usersRepo users.Repository
+		expError  string
+	}{
+		"success": {
+			usersRepo: usersRepoStub{notSeenIDs: []string{"1", "2"}},
+		},
+		"failed fetch from repository": {
+			usersRepo: usersRepoStub{notSeenErr: errors.New("fetch failed")},
+			expError:  "not seen users fetching: fetch failed",
+		},
+		"failed delete": {
+			usersRepo: usersRepoStub{notSeenIDs: []string{"1"}, deleteErr: errors.New("delete failed")},
+			expError:  "user deletion: delete failed",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			srv, err := users.NewService(tt.usersRepo)
+			require.NoError(t, err)
+
+			err = srv.DeleteNotSeen(context.Background(), time.Hour)
+
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 type usersRepoStub struct {
-	getErr  error
-	getUser *users.User
-	saveErr error
+	getErr     error
+	getUser    *users.User
+	saveErr    error
+	deleteErr  error
+	notSeenIDs []string
+	notSeenErr error
 }
 
-func (u usersRepoStub) Get(id string) (*users.User, error) {
+func (u usersRepoStub) Get(_ context.Context, id string) (*users.User, error) {
 	if u.getUser != nil && u.getUser.ID() != id {
 		return nil, errors.New("test failed - user id does not match")
 	}
 	return u.getUser, u.getErr
 }
 
-func (u usersRepoStub) GetMany([]string) ([]users.User, error) {
+func (u usersRepoStub) ModifyExclusively(ctx context.Context, id string, cb func(user *users.User) error) error {
+	user, err := u.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return users.ErrUserNotFound
+	}
+	if err := cb(user); err != nil {
+		return err
+	}
+	return u.Save(ctx, *user)
+}
+
+func (u usersRepoStub) GetMany(context.Context, []string) ([]users.User, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (u usersRepoStub) Save(users.User) error {
+func (u usersRepoStub) Save(context.Context, users.User) error {
 	return u.saveErr
 }
+
+func (u usersRepoStub) Delete(context.Context, string) error {
+	return u.deleteErr
+}
+
+func (u usersRepoStub) GetNotSeenSince(context.Context, time.Time) ([]string, error) {
+	return u.notSeenIDs, u.notSeenErr
+}
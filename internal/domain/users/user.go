@@ -2,8 +2,9 @@
 package users
 
 import (
-	"errors"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"planningpoker/internal/domain"
 	"planningpoker/internal/domain/events"
@@ -11,17 +12,22 @@ import (
 	"github.com/google/uuid"
 )
 
+// MaxNameLength is the maximal user name length in characters.
+const MaxNameLength = 64
+
 // User is a user aggregate.
 type User struct {
 	domain.BaseAggregate
-	id   string
-	name string
+	id         string
+	name       string
+	lastSeenAt time.Time
 }
 
 // NewUser creates a new user.
 func NewUser(name string) (*User, error) {
 	u := &User{
-		id: strings.ReplaceAll(uuid.New().String(), "-", ""),
+		id:         strings.ReplaceAll(uuid.New().String(), "-", ""),
+		lastSeenAt: time.Now(),
 	}
 	if err := u.NameAs(name); err != nil {
 		return nil, err
@@ -32,10 +38,11 @@ func NewUser(name string) (*User, error) {
 
 // NewRaw instantiates a user aggregate from raw data.
 // It should never be used in any logic except aggregate hydration from any serialized format (db, etc...)
-func NewRaw(id string, name string) *User {
+func NewRaw(id string, name string, lastSeenAt time.Time) *User {
 	return &User{
-		id:   id,
-		name: name,
+		id:         id,
+		name:       name,
+		lastSeenAt: lastSeenAt,
 	}
 }
 
@@ -49,10 +56,24 @@ func (u User) Name() string {
 	return u.name
 }
 
-// NameAs changes the user name.
+// LastSeenAt returns the time when the user was authenticated last time.
+func (u User) LastSeenAt() time.Time {
+	return u.lastSeenAt
+}
+
+// MarkSeen records that the user is still around.
+func (u *User) MarkSeen() {
+	u.lastSeenAt = time.Now()
+}
+
+// NameAs changes the user name, leading and trailing spaces are trimmed.
 func (u *User) NameAs(name string) error {
+	name = strings.TrimSpace(name)
 	if name == "" {
-		return errors.New("user name should be provided")
+		return ErrEmptyName
+	}
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return ErrNameTooLong
 	}
 	u.name = name
 	u.AddEvent(events.NewDomainEventBuilder(events.EventTypeUserUpdated).ForAggregate(u.ID()).Build())
@@ -1,7 +1,9 @@
 package users_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"planningpoker/internal/domain/users"
 
@@ -14,14 +16,32 @@ func TestNewUser(t *testing.T) {
 
 	testCases := map[string]struct {
 		name     string
+		expName  string
 		expError string
 	}{
 		"success": {
-			name: "foo",
+			name:    "foo",
+			expName: "foo",
+		},
+		"success on trimmed name": {
+			name:    " \tfoo bar \n",
+			expName: "foo bar",
 		},
 		"failed on wrong name": {
 			expError: "user name should be provided",
 		},
+		"failed on blank name": {
+			name:     "   ",
+			expError: "user name should be provided",
+		},
+		"success on max length name": {
+			name:    strings.Repeat("ж", users.MaxNameLength),
+			expName: strings.Repeat("ж", users.MaxNameLength),
+		},
+		"failed on too long name": {
+			name:     strings.Repeat("a", users.MaxNameLength+1),
+			expError: "user name should be at most 64 characters long",
+		},
 	}
 
 	for name, tt := range testCases {
@@ -38,7 +58,7 @@ func TestNewUser(t *testing.T) {
 				assert.NoError(t, err)
 				require.NotNil(t, u)
 				assert.NotEmpty(t, u.ID())
-				assert.Equal(t, tt.name, u.Name())
+				assert.Equal(t, tt.expName, u.Name())
 			}
 		})
 	}
@@ -63,7 +83,7 @@ func TestNewRawUser(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			u := users.NewRaw(tt.id, tt.name)
+			u := users.NewRaw(tt.id, tt.name, time.Now())
 			require.NotNil(t, u)
 			assert.NotEmpty(t, u.ID())
 			assert.Equal(t, tt.name, u.Name())
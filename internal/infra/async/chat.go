@@ -0,0 +1,180 @@
+package async
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	socketio "github.com/googollee/go-socket.io"
+
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/infra/logging"
+	"planningpoker/internal/infra/ratelimit"
+	"planningpoker/internal/infra/transformers"
+)
+
+const (
+	eventChatMessage = "chatMessage"
+	eventChatHistory = "chatHistory"
+
+	// chatHistorySize is the number of the last messages sent to a connection joining the game.
+	chatHistorySize = 50
+)
+
+// chatMessageResponse is a chat message or a reaction, players are identified by their handles.
+type chatMessageResponse struct {
+	Sender   string    `json:"sender"`
+	Text     string    `json:"text,omitempty"`
+	Reaction string    `json:"reaction,omitempty"`
+	Target   string    `json:"target,omitempty"`
+	SentAt   time.Time `json:"sent_at"`
+}
+
+func newChatMessageResponse(msg games.ChatMessage) chatMessageResponse {
+	return chatMessageResponse{
+		Sender:   msg.SenderHandle,
+		Text:     msg.Text,
+		Reaction: msg.Reaction,
+		Target:   msg.TargetHandle,
+		SentAt:   msg.SentAt,
+	}
+}
+
+// chatHistory keeps the last chatHistorySize messages of a game, the oldest ones are dropped.
+type chatHistory struct {
+	m    sync.Mutex
+	list []chatMessageResponse
+}
+
+func (h *chatHistory) add(msg chatMessageResponse) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	h.list = append(h.list, msg)
+	if len(h.list) > chatHistorySize {
+		h.list = append(h.list[:0:0], h.list[len(h.list)-chatHistorySize:]...)
+	}
+}
+
+// messages returns a copy of the kept messages, the oldest first.
+func (h *chatHistory) messages() []chatMessageResponse {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	return append(make([]chatMessageResponse, 0, len(h.list)), h.list...)
+}
+
+// chats is a registry of chat histories per game, a history lives while the game room has connections.
+type chats struct {
+	m    sync.Mutex
+	list map[string]*chatHistory
+}
+
+func newChats() *chats {
+	return &chats{list: make(map[string]*chatHistory)}
+}
+
+func (c *chats) get(gameID string) *chatHistory {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	h, ok := c.list[gameID]
+	if !ok {
+		h = &chatHistory{}
+		c.list[gameID] = h
+	}
+
+	return h
+}
+
+func (c *chats) drop(gameID string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	delete(c.list, gameID)
+}
+
+// LimitChat limits chat messages and reactions per user, they are not counted by the events limit.
+// It should be called before the routes are set up.
+func (p *API) LimitChat(perUser *ratelimit.Limiter) {
+	p.chatLimiter = perUser
+}
+
+type chatPayload struct {
+	Text string `json:"text"`
+}
+
+type reactPayload struct {
+	Emoji string `json:"emoji"`
+	// Target is an optional handle of the player the reaction is addressed to.
+	Target string `json:"target"`
+}
+
+func (p *API) chat(conn socketio.Conn, raw json.RawMessage) interface{} {
+	cc, ok := conn.Context().(conContext)
+	if !ok {
+		p.logger.WithField(logging.FieldConnID, conn.ID()).Error(errNoContext)
+		return transformers.NewErrorResponse(errNoContext)
+	}
+
+	pl := chatPayload{}
+	if err := decodePayload(raw, &pl); err != nil {
+		return transformers.NewErrorResponse(err)
+	}
+
+	cmd, err := games.NewChatMessageCommand(cc.gameID, cc.userID, pl.Text)
+	if err != nil {
+		return transformers.NewErrorResponse(err)
+	}
+
+	return p.sendChat("chat", cc, *cmd)
+}
+
+func (p *API) react(conn socketio.Conn, raw json.RawMessage) interface{} {
+	cc, ok := conn.Context().(conContext)
+	if !ok {
+		p.logger.WithField(logging.FieldConnID, conn.ID()).Error(errNoContext)
+		return transformers.NewErrorResponse(errNoContext)
+	}
+
+	pl := reactPayload{}
+	if err := decodePayload(raw, &pl); err != nil {
+		return transformers.NewErrorResponse(err)
+	}
+
+	cmd, err := games.NewReactionCommand(cc.gameID, cc.userID, pl.Emoji, pl.Target)
+	if err != nil {
+		return transformers.NewErrorResponse(err)
+	}
+
+	return p.sendChat("react", cc, *cmd)
+}
+
+// sendChat broadcasts the message to everyone in the game and keeps it in the game chat history.
+func (p *API) sendChat(event string, cc conContext, cmd games.ChatCommand) interface{} {
+	ctx, span := p.eventContext(event, cc)
+	defer span.End()
+	if !p.chatLimiter.Allow(cc.userID) {
+		p.logger.WithContext(ctx).Warn("chat rate limit exceeded")
+		return transformers.NewErrorResponse(ratelimit.ErrLimited)
+	}
+
+	msg, err := p.gamesService.Chat(ctx, cmd)
+	if err != nil {
+		return transformers.NewErrorResponse(err)
+	}
+
+	resp := newChatMessageResponse(*msg)
+	p.chats.get(cc.gameID).add(resp)
+	if !p.server.BroadcastToRoom(rootNameSpace, cc.gameID, eventChatMessage, resp) {
+		p.logger.WithContext(ctx).Error("chat broadcast failed")
+	}
+
+	return "ok"
+}
+
+func (p *API) dropChatIfEmpty(gameID string) {
+	if p.server.RoomLen(rootNameSpace, gameID) == 0 {
+		p.chats.drop(gameID)
+	}
+}
@@ -0,0 +1,102 @@
+package async
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/infra/ratelimit"
+	"planningpoker/internal/infra/transformers"
+	"planningpoker/test"
+)
+
+func TestChatHistory(t *testing.T) {
+	t.Parallel()
+
+	h := &chatHistory{}
+	for i := 0; i < chatHistorySize+10; i++ {
+		h.add(chatMessageResponse{Text: fmt.Sprint(i)})
+	}
+
+	list := h.messages()
+	require.Len(t, list, chatHistorySize)
+	assert.Equal(t, "10", list[0].Text, "the oldest messages should be dropped")
+	assert.Equal(t, fmt.Sprint(chatHistorySize+9), list[chatHistorySize-1].Text)
+
+	list[0].Text = "changed"
+	assert.Equal(t, "10", h.messages()[0].Text, "a copy of the history should be returned")
+}
+
+func TestAPI_Chat(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		err        error
+		limiter    *ratelimit.Limiter
+		expError   string
+		expHistory []string
+	}{
+		"success": {
+			expHistory: []string{"first", "second"},
+		},
+		"fail on rate limit": {
+			limiter:    ratelimit.NewLimiter(0.001, 1),
+			expError:   transformers.ErrorCodeRateLimited,
+			expHistory: []string{"first"},
+		},
+		"fail on game service error": {
+			err:        errors.New("failed"),
+			expError:   transformers.ErrorCodeInternal,
+			expHistory: []string{},
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			srv := &gameServiceStub{err: tt.err}
+			api := NewAPI(srv, nil, test.NewLogger())
+			api.LimitChat(tt.limiter)
+			conn := newConnStub(test.User1)
+			conn.SetContext(conContext{connID: "conn-id", userID: test.User1, gameID: "game-id"})
+
+			api.chat(conn, json.RawMessage(`{"text": "first"}`))
+			resp := api.chat(conn, json.RawMessage(`{"text": "second"}`))
+
+			history := make([]string, 0)
+			for _, msg := range api.chats.get("game-id").messages() {
+				history = append(history, msg.Text)
+			}
+			assert.Equal(t, tt.expHistory, history)
+			if tt.expError != "" {
+				assert.Equal(t, tt.expError, resp.(transformers.ErrorResponse).Code)
+				return
+			}
+			assert.Equal(t, "ok", resp)
+		})
+	}
+}
+
+func TestAPI_ChatHistorySentOnJoin(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPI(&gameServiceStub{}, nil, test.NewLogger())
+	sender := newConnStub(test.User1)
+	sender.SetContext(conContext{connID: "conn-id", userID: test.User1, gameID: "game-id"})
+	require.Equal(t, "ok", api.chat(sender, json.RawMessage(`{"text": "hello"}`)))
+
+	conn := newConnStub(test.User2)
+	require.Equal(t, "ok", api.join(conn, json.RawMessage(`{"game_id": "game-id"}`)))
+
+	require.Len(t, conn.emitted[eventChatHistory], 1)
+	history, ok := conn.emitted[eventChatHistory][0].([]chatMessageResponse)
+	require.True(t, ok)
+	require.Len(t, history, 1)
+	assert.Equal(t, "hello", history[0].Text)
+	assert.Equal(t, "handle", history[0].Sender)
+}
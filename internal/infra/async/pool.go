@@ -2,103 +2,271 @@
 package async
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"net/http"
 	"net/url"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	socketio "github.com/googollee/go-socket.io"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 
+	"planningpoker/internal/domain"
 	"planningpoker/internal/domain/games"
 	"planningpoker/internal/domain/state"
+	"planningpoker/internal/infra/jsonpatch"
+	"planningpoker/internal/infra/logging"
+	"planningpoker/internal/infra/ratelimit"
+	"planningpoker/internal/infra/tracing"
 	"planningpoker/internal/infra/transformers"
 )
 
 const (
-	rootNameSpace       = "/"
-	genericErrorMessage = "error"
+	rootNameSpace = "/"
+
+	// shutdownEvent tells clients that the server goes down and they should reconnect.
+	shutdownEvent = "shutdown"
+	// shutdownGrace is a time given to clients to receive the shutdown notice.
+	shutdownGrace = 500 * time.Millisecond
+)
+
+var (
+	errNoContext    = errors.New("unable to get the connection context")
+	errShuttingDown = errors.New("server is shutting down")
 )
 
+// decodePayload decodes the event payload, handlers take raw payloads since go-socket.io drops the connection
+// on a payload of an unexpected shape, instead of responding with a validation error.
+func decodePayload(raw json.RawMessage, v interface{}) error {
+	err := json.Unmarshal(raw, v)
+	if err == nil {
+		return nil
+	}
+
+	msg := "malformed payload"
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		msg = fmt.Sprintf("malformed payload: unexpected %s in %s field", typeErr.Value, typeErr.Field)
+	}
+
+	return domain.NewError(domain.KindValidation, transformers.ErrorCodeBadRequest, msg)
+}
+
 // API is a socket.io API implementation.
 type API struct {
 	server       *socketio.Server
 	usersAuth    userAuthenticator
-	gamesService gameService
-}
-
-// GameRepository is a contract to fetch games data.
-type gameService interface {
-	Create(cmd games.CreateGameCommand) (string, error)
-	Join(cmd games.JoinGameCommand) error
-	Leave(cmd games.LeaveGameCommand) error
-	Update(cmd games.UpdateGameCommand) error
-	Vote(cmd games.VoteCommand) error
-	UnVote(cmd games.UnVoteCommand) error
-	Reveal(cmd games.RevealCardsCommand) error
-	Restart(cmd games.RestartGameCommand) error
+	gamesService games.Commander
+	streams      *streams
+	chats        *chats
+	logger       *logrus.Entry
+	// ipLimiter, userLimiter and chatLimiter are optional, nil limiters allow everything.
+	ipLimiter   *ratelimit.Limiter
+	userLimiter *ratelimit.Limiter
+	chatLimiter *ratelimit.Limiter
+	// proxies are trusted to report the client IP, nil trusts nobody.
+	proxies *ratelimit.Proxies
+
+	cm      sync.RWMutex
+	closing bool
+	conns   map[string]socketio.Conn
 }
 
 type conContext struct {
+	connID string
 	userID string
 	gameID string
+	// spectator watches the game without being its player.
+	spectator bool
 }
 
 // UserAuthenticator is a contract to authenticate users.
 type userAuthenticator interface {
 	// AuthenticateByToken returns a user ID or error is user is not authenticated
-	AuthenticateByToken(token string) (string, error)
+	AuthenticateByToken(ctx context.Context, token string) (string, error)
 }
 
 // NewAPI creates a new socket.io related api.
-func NewAPI(repository gameService, authenticator userAuthenticator) *API {
+func NewAPI(repository games.Commander, authenticator userAuthenticator, logger *logrus.Entry) *API {
 	p := &API{
 		gamesService: repository,
 		usersAuth:    authenticator,
 		server:       socketio.NewServer(nil),
+		streams:      newStreams(),
+		chats:        newChats(),
+		logger:       logger,
+		conns:        make(map[string]socketio.Conn),
 	}
 
 	go func() {
 		if err := p.server.Serve(); err != nil {
-			log.Fatalf("socketio listen error: %s\n", err)
+			p.logger.WithError(err).Fatal("socket.io server failed")
 		}
 	}()
-	// defer server.Close()
 
 	p.server.OnConnect(rootNameSpace, p.onConnect)
 	p.server.OnDisconnect(rootNameSpace, p.onDisconnect)
 	p.server.OnEvent(rootNameSpace, "create", p.create)
 	p.server.OnEvent(rootNameSpace, "join", p.join)
+	p.server.OnEvent(rootNameSpace, "spectate", p.spectate)
 	p.server.OnEvent(rootNameSpace, "leave", p.leave)
 	p.server.OnEvent(rootNameSpace, "vote", p.vote)
 	p.server.OnEvent(rootNameSpace, "update", p.update)
 	p.server.OnEvent(rootNameSpace, "unvote", p.unVote)
 	p.server.OnEvent(rootNameSpace, "reveal", p.reveal)
 	p.server.OnEvent(rootNameSpace, "restart", p.restart)
-	p.server.OnError(rootNameSpace, func(s socketio.Conn, e error) {
-		log.Println("meet error:", e)
+	p.server.OnEvent(rootNameSpace, "revote", p.revote)
+	p.server.OnEvent(rootNameSpace, "seats", p.rearrange)
+	p.server.OnEvent(rootNameSpace, "passcode", p.changePasscode)
+	p.server.OnEvent(rootNameSpace, "resync", p.resync)
+	p.server.OnEvent(rootNameSpace, "chat", p.chat)
+	p.server.OnEvent(rootNameSpace, "react", p.react)
+	p.server.OnError(rootNameSpace, func(conn socketio.Conn, err error) {
+		logger := p.logger.WithError(err)
+		if conn != nil {
+			logger = logger.WithField(logging.FieldConnID, conn.ID())
+		}
+		logger.Error("socket error")
 	})
 	return p
 }
 
+// LimitRate limits socket connections per client IP and socket events per user.
+// It should be called before the routes are set up.
+func (p *API) LimitRate(perIP, perUser *ratelimit.Limiter) {
+	p.ipLimiter = perIP
+	p.userLimiter = perUser
+}
+
+// TrustProxies makes the client IP to be read from the proxy headers of connections coming from the proxies.
+// It should be called before the routes are set up.
+func (p *API) TrustProxies(proxies *ratelimit.Proxies) {
+	p.proxies = proxies
+}
+
 // SetupRoutes sets up socket.io related routes.
 func (p *API) SetupRoutes(r gin.IRoutes) {
-	r.GET("/socket.io/*any", gin.WrapH(p.server))
-	r.POST("/socket.io/*any", gin.WrapH(p.server))
+	r.GET("/socket.io/*any", p.serveHTTP)
+	r.POST("/socket.io/*any", p.serveHTTP)
 }
 
-// SendToPlayer sends a message to a player of specific game.
-func (p *API) SendToPlayer(gameState state.GameState, userID string) error {
+// Shutdown stops accepting new connections, asks connected clients to reconnect and closes their connections.
+// Clients are given a short grace period to receive the notice before their connections are closed.
+// The socket.io server itself is not closed, since it panics on sessions which are still being initialized.
+func (p *API) Shutdown(ctx context.Context) error {
+	p.cm.Lock()
+	p.closing = true
+	conns := make([]socketio.Conn, 0, len(p.conns))
+	for _, conn := range p.conns {
+		conns = append(conns, conn)
+	}
+	p.cm.Unlock()
+
+	if len(conns) == 0 {
+		return nil
+	}
+
+	for _, conn := range conns {
+		conn.Emit(shutdownEvent)
+	}
+
+	select {
+	case <-time.After(shutdownGrace):
+	case <-ctx.Done():
+	}
+
+	var closeErr error
+	for _, conn := range conns {
+		// closing calls the disconnect handler, so the lock should not be held here
+		if err := conn.Close(); err != nil {
+			closeErr = fmt.Errorf("socket closing: %w", err)
+		}
+	}
+
+	return closeErr
+}
+
+// ConnectionsCount returns the number of connected sockets.
+func (p *API) ConnectionsCount() int {
+	p.cm.RLock()
+	defer p.cm.RUnlock()
+
+	return len(p.conns)
+}
+
+// serveHTTP passes requests to the socket.io server unless it is shutting down.
+func (p *API) serveHTTP(c *gin.Context) {
+	p.cm.RLock()
+	closing := p.closing
+	p.cm.RUnlock()
+
+	if closing {
+		c.AbortWithStatus(http.StatusServiceUnavailable)
+		return
+	}
+
+	p.server.ServeHTTP(c.Writer, c.Request)
+}
+
+// SendToGame sends the shared state to everyone in the game room, the state is serialized once for all of them.
+// The first message in a room is a full snapshot, all the following ones are patches against the previous state.
+func (p *API) SendToGame(ctx context.Context, gameState state.GameState) (err error) {
+	_, span := tracing.Start(ctx, "async.SendToGame", tracing.AttrGameID.String(gameState.GameID))
+	defer func() { tracing.End(span, err) }()
+
+	sharedState, err := jsonpatch.ToTree(transformers.NewGameStateResponse(gameState))
+	if err != nil {
+		return fmt.Errorf("state serialization: %w", err)
+	}
+
+	stream := p.streams.get(gameState.GameID)
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	event, payload := stream.next(sharedState)
+	if event == "" {
+		return nil
+	}
+
+	if !p.server.BroadcastToRoom(rootNameSpace, gameState.GameID, event, payload) {
+		return fmt.Errorf("broadcast to gameID=%s failed", gameState.GameID)
+	}
+
+	return nil
+}
+
+// SendToPlayer sends the personal state to all connections of a player of specific game.
+// The personal state is small, so it is always sent in full, but only when it has changed.
+func (p *API) SendToPlayer(ctx context.Context, gameState state.GameState, userID string) (err error) {
+	_, span := tracing.Start(ctx, "async.SendToPlayer",
+		tracing.AttrGameID.String(gameState.GameID), tracing.AttrUserID.String(userID))
+	defer func() { tracing.End(span, err) }()
+
 	player, err := gameState.PlayerByID(userID)
 	if err != nil {
 		return err
 	}
 
-	personalState := transformers.NewGameStateResponse(gameState, *player)
+	personalState, err := jsonpatch.ToTree(transformers.NewPersonalStateResponse(*player))
+	if err != nil {
+		return fmt.Errorf("state serialization: %w", err)
+	}
+
+	stream := p.streams.get(gameState.GameID + userID)
+	stream.m.Lock()
+	defer stream.m.Unlock()
 
-	ok := p.server.BroadcastToRoom(rootNameSpace, gameState.GameID+userID, "gameState", personalState)
-	if !ok {
+	snapshot := stream.nextSnapshot(personalState)
+	if snapshot == nil {
+		return nil
+	}
+
+	if !p.server.BroadcastToRoom(rootNameSpace, gameState.GameID+userID, eventPlayerState, *snapshot) {
 		return fmt.Errorf("broadcast to gameID=%s failed", gameState.GameID)
 	}
 
@@ -109,24 +277,55 @@ func (p *API) onConnect(conn socketio.Conn) error {
 	urlVal := conn.URL()
 	token := urlVal.Query().Get("token")
 
-	uid, err := p.usersAuth.AuthenticateByToken(token)
+	ctx := logging.WithFields(context.Background(), logrus.Fields{logging.FieldConnID: conn.ID()})
+	ctx, span := tracing.Start(ctx, "socket.connect")
+	defer span.End()
+
+	if ip := p.proxies.ClientIP(conn.RemoteAddr().String(), conn.RemoteHeader()); !p.ipLimiter.Allow(ip) {
+		p.logger.WithContext(ctx).WithField("client", ip).Warn("IP rate limit exceeded")
+		return ratelimit.ErrLimited
+	}
+
+	uid, err := p.usersAuth.AuthenticateByToken(ctx, token)
 	if err != nil {
-		logrus.Errorf("socket authentication error: %v", err)
+		p.logger.WithContext(ctx).WithError(err).Warn("socket authentication failed")
 		return errors.New("unauthorized")
 	}
 
-	conn.SetContext(conContext{userID: uid})
+	p.cm.Lock()
+	defer p.cm.Unlock()
+	if p.closing {
+		return errShuttingDown
+	}
+	p.conns[conn.ID()] = conn
+
+	conn.SetContext(conContext{connID: conn.ID(), userID: uid})
+	p.logger.WithContext(ctx).WithField(logging.FieldUserID, uid).Debug("socket connected")
 
 	return nil
 }
 
 func (p *API) onDisconnect(conn socketio.Conn, reason string) {
-	uid, ok := conn.Context().(string)
+	p.cm.Lock()
+	delete(p.conns, conn.ID())
+	p.cm.Unlock()
+
+	logger := p.logger.WithFields(logrus.Fields{logging.FieldConnID: conn.ID(), "reason": reason})
+
+	cc, ok := conn.Context().(conContext)
 	if !ok {
-		logrus.Infof("client unknown id disconnected with reason: %s", reason)
+		logger.Info("unauthenticated socket disconnected")
+		return
 	}
 
-	logrus.Infof("client with id=%s disconnected with reason: %s", uid, reason)
+	if cc.gameID != "" {
+		p.dropStreamIfEmpty(cc.gameID + cc.userID)
+		p.dropStreamIfEmpty(cc.gameID)
+		p.dropChatIfEmpty(cc.gameID)
+	}
+
+	logger.WithFields(logrus.Fields{logging.FieldUserID: cc.userID, logging.FieldGameID: cc.gameID}).
+		Info("socket disconnected")
 }
 
 type createPayload struct {
@@ -135,39 +334,104 @@ type createPayload struct {
 	CardsDeck struct {
 		Name  string   `json:"name"`
 		Types []string `json:"types"`
+		// Dimensions are optional, each dimension is voted with its own cards instead of types.
+		Dimensions []struct {
+			Name  string   `json:"name"`
+			Types []string `json:"types"`
+		} `json:"dimensions"`
+		Formula string `json:"formula"`
 	} `json:"cards_deck"`
 	EveryoneCanReveal bool `json:"everyone_can_reveal"`
+	// Passcode is optional, new players should provide it to join the game.
+	Passcode string `json:"passcode"`
+	// RevoteThreshold is optional, a re-vote is suggested when the votes spread in deck cards is wider than that.
+	RevoteThreshold int `json:"revote_threshold"`
+	// Anonymous hides who voted which card.
+	Anonymous bool `json:"anonymous"`
+	// ChatDisabled turns off chat messages and reactions.
+	ChatDisabled bool `json:"chat_disabled"`
 }
 
-func (p *API) create(conn socketio.Conn, pl createPayload) interface{} {
-	cc, ok := conn.Context().(conContext)
-	if !ok {
-		logrus.Errorf("socket listen for updates: unable to get the context")
-		return genericErrorMessage
+// deck creates either a plain deck or a dimensional one when dimensions are provided.
+func (pl createPayload) deck() (*games.CardsDeck, error) {
+	if len(pl.CardsDeck.Dimensions) == 0 {
+		cards, err := newCards(pl.CardsDeck.Types)
+		if err != nil {
+			return nil, err
+		}
+		return games.NewCardsDeck(pl.CardsDeck.Name, cards)
 	}
 
-	cards := make([]games.Card, len(pl.CardsDeck.Types))
-	for i, v := range pl.CardsDeck.Types {
+	dimensions := make([]games.Dimension, len(pl.CardsDeck.Dimensions))
+	for i, d := range pl.CardsDeck.Dimensions {
+		cards, err := newCards(d.Types)
+		if err != nil {
+			return nil, err
+		}
+		dimension, err := games.NewDimension(d.Name, cards)
+		if err != nil {
+			return nil, err
+		}
+		dimensions[i] = *dimension
+	}
+
+	return games.NewDimensionalCardsDeck(pl.CardsDeck.Name, dimensions, pl.CardsDeck.Formula)
+}
+
+func newCards(types []string) ([]games.Card, error) {
+	cards := make([]games.Card, len(types))
+	for i, v := range types {
 		card, err := games.NewCard(v)
 		if err != nil {
-			return genericErrorMessage
+			return nil, err
 		}
 		cards[i] = *card
 	}
 
-	deck, err := games.NewCardsDeck(pl.CardsDeck.Name, cards)
+	return cards, nil
+}
+
+func (p *API) create(conn socketio.Conn, raw json.RawMessage) interface{} {
+	cc, ok := conn.Context().(conContext)
+	if !ok {
+		p.logger.WithField(logging.FieldConnID, conn.ID()).Error(errNoContext)
+		return transformers.NewErrorResponse(errNoContext)
+	}
+
+	ctx, span := p.eventContext("create", cc)
+	defer span.End()
+	if p.limited(ctx, cc) {
+		return transformers.NewErrorResponse(ratelimit.ErrLimited)
+	}
+	pl := createPayload{}
+	if err := decodePayload(raw, &pl); err != nil {
+		p.logger.WithContext(ctx).WithError(err).Info("malformed create request")
+		return transformers.NewErrorResponse(err)
+	}
+
+	deck, err := pl.deck()
 	if err != nil {
-		return genericErrorMessage
+		return transformers.NewErrorResponse(err)
 	}
 
 	cmd, err := games.NewCreateGameCommand(pl.Name, pl.TicketURL, cc.userID, *deck, pl.EveryoneCanReveal)
 	if err != nil {
-		return genericErrorMessage
+		return transformers.NewErrorResponse(err)
+	}
+	if pl.Passcode != "" {
+		if err := cmd.ProtectWith(pl.Passcode); err != nil {
+			return transformers.NewErrorResponse(err)
+		}
+	}
+	if err := cmd.SuggestRevoteAbove(pl.RevoteThreshold); err != nil {
+		return transformers.NewErrorResponse(err)
 	}
+	cmd.Anonymous = pl.Anonymous
+	cmd.ChatDisabled = pl.ChatDisabled
 
-	gameID, err := p.gamesService.Create(*cmd)
+	gameID, err := p.gamesService.Create(ctx, *cmd)
 	if err != nil {
-		return genericErrorMessage
+		return transformers.NewErrorResponse(err)
 	}
 
 	return gin.H{
@@ -175,89 +439,219 @@ func (p *API) create(conn socketio.Conn, pl createPayload) interface{} {
 	}
 }
 
-func (p *API) join(conn socketio.Conn, gameID string) string {
+// joinPayload is either a bare game ID, which is sent by older clients, or an object with the passcode.
+type joinPayload struct {
+	GameID   string `json:"game_id"`
+	Passcode string `json:"passcode"`
+}
+
+// UnmarshalJSON accepts both the bare game ID and the object forms.
+func (pl *joinPayload) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &pl.GameID)
+	}
+
+	type plain joinPayload
+	return json.Unmarshal(data, (*plain)(pl))
+}
+
+func (p *API) join(conn socketio.Conn, raw json.RawMessage) interface{} {
 	cc, ok := conn.Context().(conContext)
 	if !ok {
-		logrus.Errorf("socket listen for updates: unable to get the context")
-		return genericErrorMessage
+		p.logger.WithField(logging.FieldConnID, conn.ID()).Error(errNoContext)
+		return transformers.NewErrorResponse(errNoContext)
+	}
+
+	ctx, span := p.eventContext("join", cc)
+	defer span.End()
+	if p.limited(ctx, cc) {
+		return transformers.NewErrorResponse(ratelimit.ErrLimited)
+	}
+	pl := joinPayload{}
+	if err := decodePayload(raw, &pl); err != nil {
+		p.logger.WithContext(ctx).WithError(err).Info("malformed join request")
+		return transformers.NewErrorResponse(err)
 	}
+	gameID := pl.GameID
+	span.SetAttributes(tracing.AttrGameID.String(gameID))
 
 	cmd, err := games.NewJoinGameCommand(gameID, cc.userID)
 	if err != nil {
-		logrus.Errorf("socket listen for updates: unable to create player join command")
-		return genericErrorMessage
+		p.logger.WithContext(ctx).WithError(err).Info("invalid join request")
+		return transformers.NewErrorResponse(err)
 	}
+	cmd.Passcode = pl.Passcode
 
-	// join the room in order to receive personalized game updates
+	// join the personal room in order to receive the player's own vote and permissions,
+	// the next personal state is sent even if it has not changed, so the new connection gets it too.
 	conn.Join(gameID + cc.userID)
+	stream := p.streams.get(gameID + cc.userID)
+	stream.m.Lock()
+	stream.reset()
+	stream.m.Unlock()
 
-	if err := p.gamesService.Join(*cmd); err != nil {
-		logrus.Errorf("socket listen for updates: unable to find the game id=%s: %v", gameID, err)
-		return genericErrorMessage
+	if err := p.gamesService.Join(ctx, *cmd); err != nil {
+		// the personal room is left, so a rejected connection does not keep the stream alive
+		conn.Leave(gameID + cc.userID)
+		p.dropStreamIfEmpty(gameID + cc.userID)
+		return transformers.NewErrorResponse(err)
 	}
 
 	cc.gameID = gameID
-	conn.SetContext(cc)
+	cc.spectator = false
+	p.enterGame(conn, cc)
+
+	return "ok"
+}
+
+// spectate lets the user watch the game without taking a seat, the connection joins the game room only.
+// Protected games require the passcode from spectators as well.
+func (p *API) spectate(conn socketio.Conn, raw json.RawMessage) interface{} {
+	cc, ok := conn.Context().(conContext)
+	if !ok {
+		p.logger.WithField(logging.FieldConnID, conn.ID()).Error(errNoContext)
+		return transformers.NewErrorResponse(errNoContext)
+	}
+
+	ctx, span := p.eventContext("spectate", cc)
+	defer span.End()
+	if p.limited(ctx, cc) {
+		return transformers.NewErrorResponse(ratelimit.ErrLimited)
+	}
+	pl := joinPayload{}
+	if err := decodePayload(raw, &pl); err != nil {
+		p.logger.WithContext(ctx).WithError(err).Info("malformed spectate request")
+		return transformers.NewErrorResponse(err)
+	}
+	span.SetAttributes(tracing.AttrGameID.String(pl.GameID))
+
+	cmd, err := games.NewJoinGameCommand(pl.GameID, cc.userID)
+	if err != nil {
+		p.logger.WithContext(ctx).WithError(err).Info("invalid spectate request")
+		return transformers.NewErrorResponse(err)
+	}
+	cmd.Passcode = pl.Passcode
+
+	if err := p.gamesService.Spectate(ctx, *cmd); err != nil {
+		return transformers.NewErrorResponse(err)
+	}
+
+	cc.gameID = pl.GameID
+	cc.spectator = true
+	p.enterGame(conn, cc)
 
 	return "ok"
 }
 
-func (p *API) leave(conn socketio.Conn) string {
+// enterGame remembers the game of the connection and adds it to the game room, which receives the state
+// and the chat shared by everyone in the game. The last snapshot and the chat history let the connection catch up.
+func (p *API) enterGame(conn socketio.Conn, cc conContext) {
+	conn.SetContext(cc)
+	p.joinGameRoom(conn, cc.gameID)
+	conn.Emit(eventChatHistory, p.chats.get(cc.gameID).messages())
+}
+
+func (p *API) leave(conn socketio.Conn) interface{} {
 	cc, ok := conn.Context().(conContext)
 	if !ok {
-		logrus.Errorf("socket game: unable to get the context")
-		return genericErrorMessage
+		p.logger.WithField(logging.FieldConnID, conn.ID()).Error(errNoContext)
+		return transformers.NewErrorResponse(errNoContext)
+	}
+
+	ctx, span := p.eventContext("leave", cc)
+	defer span.End()
+	if p.limited(ctx, cc) {
+		return transformers.NewErrorResponse(ratelimit.ErrLimited)
 	}
 	conn.Leave(cc.gameID + cc.userID)
+	p.dropStreamIfEmpty(cc.gameID + cc.userID)
+	conn.Leave(cc.gameID)
+	p.dropStreamIfEmpty(cc.gameID)
+	p.dropChatIfEmpty(cc.gameID)
+
+	// spectators have no seat to leave
+	if cc.spectator {
+		return "ok"
+	}
 
 	cmd, err := games.NewLeaveGameCommand(cc.gameID, cc.userID)
 	if err != nil {
-		logrus.Errorf("leave: %v", err)
-		return genericErrorMessage
+		p.logger.WithContext(ctx).WithError(err).Info("invalid leave request")
+		return transformers.NewErrorResponse(err)
 	}
 
-	if err := p.gamesService.Leave(*cmd); err != nil {
-		logrus.Errorf("leave: %v", err)
-		return genericErrorMessage
+	if err := p.gamesService.Leave(ctx, *cmd); err != nil {
+		return transformers.NewErrorResponse(err)
 	}
 
 	return "ok"
 }
 
 type votePayload struct {
-	Vote       string `json:"vote"`
-	Confidence string `json:"confidence"`
+	Vote string `json:"vote"`
+	// Votes are cards per dimension name, they are sent instead of the vote in dimensional games.
+	Votes      map[string]string `json:"votes"`
+	Confidence string            `json:"confidence"`
 }
 
-func (p *API) vote(conn socketio.Conn, payload votePayload) string {
-	cc, ok := conn.Context().(conContext)
-	if !ok {
-		logrus.Errorf("socket game: unable to get the context")
-		return genericErrorMessage
+// command creates a vote command, cards are URL encoded by clients.
+func (pl votePayload) command(gameID, userID string) (*games.VoteCommand, error) {
+	if len(pl.Votes) > 0 {
+		cards := make(map[string]games.Card, len(pl.Votes))
+		for name, v := range pl.Votes {
+			card, err := newVotedCard(v)
+			if err != nil {
+				return nil, err
+			}
+			cards[name] = *card
+		}
+		return games.NewDimensionalVoteCommand(gameID, userID, cards, pl.Confidence)
 	}
 
-	vote, err := url.QueryUnescape(payload.Vote)
+	card, err := newVotedCard(pl.Vote)
 	if err != nil {
-		logrus.Errorf("socket leave game: failed to unescape vote: %v", err)
-		return genericErrorMessage
+		return nil, err
 	}
 
-	card, err := games.NewCard(vote)
+	return games.NewVoteCommand(gameID, userID, *card, pl.Confidence)
+}
+
+func newVotedCard(encoded string) (*games.Card, error) {
+	vote, err := url.QueryUnescape(encoded)
 	if err != nil {
-		logrus.Errorf("vote: %v", err)
-		return genericErrorMessage
+		return nil, domain.NewError(domain.KindValidation, transformers.ErrorCodeBadRequest, "malformed vote: "+err.Error())
+	}
+
+	return games.NewCard(vote)
+}
+
+func (p *API) vote(conn socketio.Conn, raw json.RawMessage) interface{} {
+	cc, ok := conn.Context().(conContext)
+	if !ok {
+		p.logger.WithField(logging.FieldConnID, conn.ID()).Error(errNoContext)
+		return transformers.NewErrorResponse(errNoContext)
 	}
 
-	cmd, err := games.NewVoteCommand(cc.gameID, cc.userID, *card, payload.Confidence)
+	ctx, span := p.eventContext("vote", cc)
+	defer span.End()
+	if p.limited(ctx, cc) {
+		return transformers.NewErrorResponse(ratelimit.ErrLimited)
+	}
+	payload := votePayload{}
+	if err := decodePayload(raw, &payload); err != nil {
+		p.logger.WithContext(ctx).WithError(err).Info("malformed vote request")
+		return transformers.NewErrorResponse(err)
+	}
+
+	cmd, err := payload.command(cc.gameID, cc.userID)
 	if err != nil {
-		logrus.Errorf("vote: %v", err)
-		return genericErrorMessage
+		p.logger.WithContext(ctx).WithError(err).Info("invalid vote request")
+		return transformers.NewErrorResponse(err)
 	}
 
-	err = p.gamesService.Vote(*cmd)
+	err = p.gamesService.Vote(ctx, *cmd)
 	if err != nil {
-		logrus.Errorf("vote: %v", err)
-		return genericErrorMessage
+		return transformers.NewErrorResponse(err)
 	}
 
 	return ""
@@ -268,86 +662,271 @@ type updatePayload struct {
 	TicketURL string `json:"ticket_url"`
 }
 
-func (p *API) update(conn socketio.Conn, params updatePayload) string {
+func (p *API) update(conn socketio.Conn, raw json.RawMessage) interface{} {
 	cc, ok := conn.Context().(conContext)
 	if !ok {
-		logrus.Errorf("socket game: unable to get the context")
-		return genericErrorMessage
+		p.logger.WithField(logging.FieldConnID, conn.ID()).Error(errNoContext)
+		return transformers.NewErrorResponse(errNoContext)
+	}
+
+	ctx, span := p.eventContext("update", cc)
+	defer span.End()
+	if p.limited(ctx, cc) {
+		return transformers.NewErrorResponse(ratelimit.ErrLimited)
+	}
+	params := updatePayload{}
+	if err := decodePayload(raw, &params); err != nil {
+		p.logger.WithContext(ctx).WithError(err).Info("malformed update request")
+		return transformers.NewErrorResponse(err)
 	}
 
 	cmd, err := games.NewUpdateGameCommand(cc.gameID, params.Name, params.TicketURL, cc.userID)
 	if err != nil {
-		logrus.Errorf("update: %v", err)
-		return genericErrorMessage
+		p.logger.WithContext(ctx).WithError(err).Info("invalid update request")
+		return transformers.NewErrorResponse(err)
 	}
 
-	if err := p.gamesService.Update(*cmd); err != nil {
-		logrus.Errorf("update: %v", err)
-		return genericErrorMessage
+	if err := p.gamesService.Update(ctx, *cmd); err != nil {
+		return transformers.NewErrorResponse(err)
 	}
 
 	return "ok"
 }
 
-func (p *API) reveal(conn socketio.Conn) string {
+func (p *API) reveal(conn socketio.Conn) interface{} {
 	cc, ok := conn.Context().(conContext)
 	if !ok {
-		logrus.Errorf("socket game: unable to get the context")
-		return genericErrorMessage
+		p.logger.WithField(logging.FieldConnID, conn.ID()).Error(errNoContext)
+		return transformers.NewErrorResponse(errNoContext)
+	}
+
+	ctx, span := p.eventContext("reveal", cc)
+	defer span.End()
+	if p.limited(ctx, cc) {
+		return transformers.NewErrorResponse(ratelimit.ErrLimited)
 	}
 
 	cmd, err := games.NewRevealCardsCommand(cc.gameID, cc.userID)
 	if err != nil {
-		logrus.Errorf("reveal: %v", err)
-		return genericErrorMessage
+		p.logger.WithContext(ctx).WithError(err).Info("invalid reveal request")
+		return transformers.NewErrorResponse(err)
 	}
 
-	if err := p.gamesService.Reveal(*cmd); err != nil {
-		logrus.Errorf("reveal: %v", err)
-		return genericErrorMessage
+	if err := p.gamesService.Reveal(ctx, *cmd); err != nil {
+		return transformers.NewErrorResponse(err)
 	}
 
 	return "ok"
 }
 
-func (p *API) unVote(conn socketio.Conn) string {
+func (p *API) unVote(conn socketio.Conn) interface{} {
 	cc, ok := conn.Context().(conContext)
 	if !ok {
-		logrus.Errorf("socket game: unable to get the context")
-		return genericErrorMessage
+		p.logger.WithField(logging.FieldConnID, conn.ID()).Error(errNoContext)
+		return transformers.NewErrorResponse(errNoContext)
+	}
+
+	ctx, span := p.eventContext("unvote", cc)
+	defer span.End()
+	if p.limited(ctx, cc) {
+		return transformers.NewErrorResponse(ratelimit.ErrLimited)
 	}
 
 	cmd, err := games.NewUnVoteCommand(cc.gameID, cc.userID)
 	if err != nil {
-		logrus.Errorf("unvote: %v", err)
-		return genericErrorMessage
+		p.logger.WithContext(ctx).WithError(err).Info("invalid unvote request")
+		return transformers.NewErrorResponse(err)
 	}
 
-	if err := p.gamesService.UnVote(*cmd); err != nil {
-		logrus.Errorf("unvote: %v", err)
-		return genericErrorMessage
+	if err := p.gamesService.UnVote(ctx, *cmd); err != nil {
+		return transformers.NewErrorResponse(err)
 	}
 
 	return "ok"
 }
 
-func (p *API) restart(conn socketio.Conn) string {
+func (p *API) restart(conn socketio.Conn) interface{} {
 	cc, ok := conn.Context().(conContext)
 	if !ok {
-		logrus.Errorf("socket game: unable to get the context")
-		return genericErrorMessage
+		p.logger.WithField(logging.FieldConnID, conn.ID()).Error(errNoContext)
+		return transformers.NewErrorResponse(errNoContext)
+	}
+
+	ctx, span := p.eventContext("restart", cc)
+	defer span.End()
+	if p.limited(ctx, cc) {
+		return transformers.NewErrorResponse(ratelimit.ErrLimited)
 	}
 
 	cmd, err := games.NewRestartGameCommand(cc.gameID, cc.userID)
 	if err != nil {
-		logrus.Errorf("restart: %v", err)
-		return genericErrorMessage
+		p.logger.WithContext(ctx).WithError(err).Info("invalid restart request")
+		return transformers.NewErrorResponse(err)
 	}
 
-	if err := p.gamesService.Restart(*cmd); err != nil {
-		logrus.Errorf("restart: %v", err)
-		return genericErrorMessage
+	if err := p.gamesService.Restart(ctx, *cmd); err != nil {
+		return transformers.NewErrorResponse(err)
 	}
 
 	return "ok"
 }
+
+func (p *API) revote(conn socketio.Conn) interface{} {
+	cc, ok := conn.Context().(conContext)
+	if !ok {
+		p.logger.WithField(logging.FieldConnID, conn.ID()).Error(errNoContext)
+		return transformers.NewErrorResponse(errNoContext)
+	}
+
+	ctx, span := p.eventContext("revote", cc)
+	defer span.End()
+	if p.limited(ctx, cc) {
+		return transformers.NewErrorResponse(ratelimit.ErrLimited)
+	}
+
+	cmd, err := games.NewRevoteCommand(cc.gameID, cc.userID)
+	if err != nil {
+		p.logger.WithContext(ctx).WithError(err).Info("invalid revote request")
+		return transformers.NewErrorResponse(err)
+	}
+
+	if err := p.gamesService.Revote(ctx, *cmd); err != nil {
+		return transformers.NewErrorResponse(err)
+	}
+
+	return "ok"
+}
+
+type seatsPayload struct {
+	Order []int `json:"order"`
+}
+
+func (p *API) rearrange(conn socketio.Conn, raw json.RawMessage) interface{} {
+	cc, ok := conn.Context().(conContext)
+	if !ok {
+		p.logger.WithField(logging.FieldConnID, conn.ID()).Error(errNoContext)
+		return transformers.NewErrorResponse(errNoContext)
+	}
+
+	ctx, span := p.eventContext("seats", cc)
+	defer span.End()
+	if p.limited(ctx, cc) {
+		return transformers.NewErrorResponse(ratelimit.ErrLimited)
+	}
+	pl := seatsPayload{}
+	if err := decodePayload(raw, &pl); err != nil {
+		p.logger.WithContext(ctx).WithError(err).Info("malformed seats request")
+		return transformers.NewErrorResponse(err)
+	}
+
+	cmd, err := games.NewRearrangeSeatsCommand(cc.gameID, cc.userID, pl.Order)
+	if err != nil {
+		p.logger.WithContext(ctx).WithError(err).Info("invalid seats request")
+		return transformers.NewErrorResponse(err)
+	}
+
+	if err := p.gamesService.Rearrange(ctx, *cmd); err != nil {
+		return transformers.NewErrorResponse(err)
+	}
+
+	return "ok"
+}
+
+type passcodePayload struct {
+	Passcode string `json:"passcode"`
+}
+
+func (p *API) changePasscode(conn socketio.Conn, raw json.RawMessage) interface{} {
+	cc, ok := conn.Context().(conContext)
+	if !ok {
+		p.logger.WithField(logging.FieldConnID, conn.ID()).Error(errNoContext)
+		return transformers.NewErrorResponse(errNoContext)
+	}
+
+	ctx, span := p.eventContext("passcode", cc)
+	defer span.End()
+	if p.limited(ctx, cc) {
+		return transformers.NewErrorResponse(ratelimit.ErrLimited)
+	}
+	pl := passcodePayload{}
+	if err := decodePayload(raw, &pl); err != nil {
+		p.logger.WithContext(ctx).WithError(err).Info("malformed passcode request")
+		return transformers.NewErrorResponse(err)
+	}
+
+	cmd, err := games.NewChangePasscodeCommand(cc.gameID, cc.userID, pl.Passcode)
+	if err != nil {
+		p.logger.WithContext(ctx).WithError(err).Info("invalid passcode request")
+		return transformers.NewErrorResponse(err)
+	}
+
+	if err := p.gamesService.ChangePasscode(ctx, *cmd); err != nil {
+		return transformers.NewErrorResponse(err)
+	}
+
+	return "ok"
+}
+
+// resync returns the last full state, clients request it when they detect a gap in sequence numbers.
+func (p *API) resync(conn socketio.Conn) interface{} {
+	cc, ok := conn.Context().(conContext)
+	if !ok {
+		p.logger.WithField(logging.FieldConnID, conn.ID()).Error(errNoContext)
+		return transformers.NewErrorResponse(errNoContext)
+	}
+
+	stream := p.streams.get(cc.gameID)
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	snapshot := stream.snapshot()
+	if snapshot == nil {
+		p.logger.WithFields(logrus.Fields{
+			logging.FieldConnID: cc.connID, logging.FieldGameID: cc.gameID, logging.FieldUserID: cc.userID,
+		}).Warn("no state to resync")
+		return transformers.NewErrorResponse(errors.New("no state to resync"))
+	}
+
+	return snapshot
+}
+
+// limited reports whether the user exceeded the events rate limit.
+func (p *API) limited(ctx context.Context, cc conContext) bool {
+	if p.userLimiter.Allow(cc.userID) {
+		return false
+	}
+	p.logger.WithContext(ctx).Warn("user rate limit exceeded")
+	return true
+}
+
+// eventContext starts a context of a socket event handling, it is the root of the event trace
+// and carries the connection, the user and the game as logging fields.
+func (p *API) eventContext(event string, cc conContext) (context.Context, trace.Span) {
+	ctx := logging.WithFields(context.Background(), logrus.Fields{
+		logging.FieldConnID: cc.connID,
+		logging.FieldUserID: cc.userID,
+		logging.FieldGameID: cc.gameID,
+	})
+
+	return tracing.Start(ctx, "socket."+event,
+		tracing.AttrUserID.String(cc.userID), tracing.AttrGameID.String(cc.gameID))
+}
+
+// joinGameRoom adds the connection to the game room and sends it the last shared state if any.
+// The stream is locked, so no patch is broadcast between the snapshot and joining the room.
+func (p *API) joinGameRoom(conn socketio.Conn, gameID string) {
+	stream := p.streams.get(gameID)
+	stream.m.Lock()
+	defer stream.m.Unlock()
+
+	conn.Join(gameID)
+	if snapshot := stream.snapshot(); snapshot != nil {
+		conn.Emit(eventGameState, *snapshot)
+	}
+}
+
+func (p *API) dropStreamIfEmpty(room string) {
+	if p.server.RoomLen(rootNameSpace, room) == 0 {
+		p.streams.drop(room)
+	}
+}
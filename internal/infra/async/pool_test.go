@@ -0,0 +1,220 @@
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	socketio "github.com/googollee/go-socket.io"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/infra/transformers"
+	"planningpoker/test"
+)
+
+func TestDecodePayload(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		raw      string
+		expPl    votePayload
+		expError string
+	}{
+		"success": {
+			raw:   `{"vote": "XS", "confidence": "high"}`,
+			expPl: votePayload{Vote: "XS", Confidence: "high"},
+		},
+		"null payload": {
+			raw: `null`,
+		},
+		"fail on wrong field type": {
+			raw:      `{"vote": 5}`,
+			expError: "malformed payload: unexpected number in vote field",
+		},
+		"fail on wrong payload type": {
+			raw:      `[1, 2]`,
+			expError: "malformed payload",
+		},
+		"fail on missing payload": {
+			raw:      ``,
+			expError: "malformed payload",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			pl := votePayload{}
+			err := decodePayload(json.RawMessage(tt.raw), &pl)
+
+			if tt.expError != "" {
+				require.EqualError(t, err, tt.expError)
+				resp := transformers.NewErrorResponse(err)
+				assert.Equal(t, transformers.ErrorCodeBadRequest, resp.Code)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expPl, pl)
+			}
+		})
+	}
+}
+
+func TestAPI_Spectate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		err      error
+		expError string
+		expRooms []string
+	}{
+		"success": {
+			expRooms: []string{"game-id"},
+		},
+		"fail on wrong passcode": {
+			err:      games.ErrWrongPasscode,
+			expError: games.ErrWrongPasscode.Code(),
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			srv := &gameServiceStub{err: tt.err}
+			api := NewAPI(srv, nil, test.NewLogger())
+			conn := newConnStub(test.User1)
+
+			resp := api.spectate(conn, json.RawMessage(`{"game_id": "game-id", "passcode": "secret"}`))
+
+			assert.Equal(t, games.JoinGameCommand{GameID: "game-id", UserID: test.User1, Passcode: "secret"}, srv.spectated)
+			assert.Empty(t, srv.joined, "spectators should not take a seat")
+			assert.Equal(t, tt.expRooms, conn.rooms)
+			if tt.expError != "" {
+				assert.Equal(t, tt.expError, resp.(transformers.ErrorResponse).Code)
+				assert.Empty(t, conn.Context().(conContext).gameID)
+				return
+			}
+			assert.Equal(t, "ok", resp)
+			assert.True(t, conn.Context().(conContext).spectator)
+			assert.Contains(t, conn.emitted, eventChatHistory)
+		})
+	}
+}
+
+func TestAPI_Join(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		err        error
+		expError   string
+		expRooms   []string
+		expStreams []string
+	}{
+		"success": {
+			expRooms:   []string{"game-id" + test.User1, "game-id"},
+			expStreams: []string{"game-id" + test.User1, "game-id"},
+		},
+		"fail on wrong passcode": {
+			err:        games.ErrWrongPasscode,
+			expError:   games.ErrWrongPasscode.Code(),
+			expRooms:   []string{},
+			expStreams: []string{},
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			api := NewAPI(&gameServiceStub{err: tt.err}, nil, test.NewLogger())
+			conn := newConnStub(test.User1)
+
+			resp := api.join(conn, json.RawMessage(`{"game_id": "game-id", "passcode": "secret"}`))
+
+			assert.Equal(t, tt.expRooms, conn.rooms)
+			streams := make([]string, 0)
+			for room := range api.streams.list {
+				streams = append(streams, room)
+			}
+			assert.ElementsMatch(t, tt.expStreams, streams)
+			if tt.expError != "" {
+				assert.Equal(t, tt.expError, resp.(transformers.ErrorResponse).Code)
+				return
+			}
+			assert.Equal(t, "ok", resp)
+			assert.Equal(t, "game-id", conn.Context().(conContext).gameID)
+		})
+	}
+}
+
+// connStub is a socket connection recording joined rooms and emitted events, other methods are not implemented.
+type connStub struct {
+	socketio.Conn
+	ctx     interface{}
+	rooms   []string
+	emitted map[string][]interface{}
+}
+
+func newConnStub(userID string) *connStub {
+	return &connStub{ctx: conContext{connID: "conn-id", userID: userID}, emitted: make(map[string][]interface{})}
+}
+
+func (c *connStub) ID() string {
+	return "conn-id"
+}
+
+func (c *connStub) Context() interface{} {
+	return c.ctx
+}
+
+func (c *connStub) SetContext(ctx interface{}) {
+	c.ctx = ctx
+}
+
+func (c *connStub) Join(room string) {
+	c.rooms = append(c.rooms, room)
+}
+
+func (c *connStub) Leave(room string) {
+	for i, r := range c.rooms {
+		if r == room {
+			c.rooms = append(c.rooms[:i], c.rooms[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *connStub) Emit(event string, v ...interface{}) {
+	c.emitted[event] = append(c.emitted[event], v...)
+}
+
+// gameServiceStub records commands of the game service, methods which are not used by the tests are not implemented.
+type gameServiceStub struct {
+	games.Commander
+	joined    []games.JoinGameCommand
+	spectated games.JoinGameCommand
+	chatted   []games.ChatCommand
+	err       error
+}
+
+func (s *gameServiceStub) Join(_ context.Context, cmd games.JoinGameCommand) error {
+	s.joined = append(s.joined, cmd)
+	return s.err
+}
+
+func (s *gameServiceStub) Spectate(_ context.Context, cmd games.JoinGameCommand) error {
+	s.spectated = cmd
+	return s.err
+}
+
+func (s *gameServiceStub) Chat(_ context.Context, cmd games.ChatCommand) (*games.ChatMessage, error) {
+	s.chatted = append(s.chatted, cmd)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &games.ChatMessage{SenderHandle: "handle", Text: cmd.Text, Reaction: cmd.Reaction, SentAt: time.Now()}, nil
+}
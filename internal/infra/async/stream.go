@@ -0,0 +1,108 @@
+package async
+
+import (
+	"sync"
+
+	"planningpoker/internal/infra/jsonpatch"
+)
+
+const (
+	eventGameState      = "gameState"
+	eventGameStatePatch = "gameStatePatch"
+	eventPlayerState    = "playerState"
+)
+
+// stateSnapshot is a full game state message, the client replaces its local state with it.
+type stateSnapshot struct {
+	Seq   uint64      `json:"seq"`
+	State interface{} `json:"state"`
+}
+
+// statePatch is a JSON patch which should be applied on top of the state with sequence number Seq-1.
+type statePatch struct {
+	Seq   uint64                `json:"seq"`
+	Patch []jsonpatch.Operation `json:"patch"`
+}
+
+// stateStream keeps the last state sent to a room in order to produce sequence-numbered patches.
+type stateStream struct {
+	m    sync.Mutex
+	seq  uint64
+	last interface{}
+}
+
+// next returns the event name and payload which moves clients to the provided state.
+// An empty event name means that nothing has changed and nothing should be sent.
+func (s *stateStream) next(state interface{}) (string, interface{}) {
+	if s.last == nil {
+		s.seq++
+		s.last = state
+		return eventGameState, stateSnapshot{Seq: s.seq, State: state}
+	}
+
+	ops := jsonpatch.Diff(s.last, state)
+	if len(ops) == 0 {
+		return "", nil
+	}
+
+	s.seq++
+	s.last = state
+
+	return eventGameStatePatch, statePatch{Seq: s.seq, Patch: ops}
+}
+
+// nextSnapshot returns a full snapshot of the provided state, nil means that nothing has changed.
+// It is used for small states, which are cheaper to resend than to patch.
+func (s *stateStream) nextSnapshot(state interface{}) *stateSnapshot {
+	if s.last != nil && len(jsonpatch.Diff(s.last, state)) == 0 {
+		return nil
+	}
+
+	s.seq++
+	s.last = state
+
+	return &stateSnapshot{Seq: s.seq, State: state}
+}
+
+// snapshot returns the last sent state, nil if nothing was sent yet.
+func (s *stateStream) snapshot() *stateSnapshot {
+	if s.last == nil {
+		return nil
+	}
+	return &stateSnapshot{Seq: s.seq, State: s.last}
+}
+
+// reset forces the next message to be a full snapshot.
+func (s *stateStream) reset() {
+	s.last = nil
+}
+
+// streams is a registry of state streams per room.
+type streams struct {
+	m    sync.Mutex
+	list map[string]*stateStream
+}
+
+func newStreams() *streams {
+	return &streams{list: make(map[string]*stateStream)}
+}
+
+func (s *streams) get(room string) *stateStream {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	st, ok := s.list[room]
+	if !ok {
+		st = &stateStream{}
+		s.list[room] = st
+	}
+
+	return st
+}
+
+func (s *streams) drop(room string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	delete(s.list, room)
+}
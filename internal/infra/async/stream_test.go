@@ -0,0 +1,113 @@
+package async
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/state"
+	"planningpoker/internal/infra/jsonpatch"
+	"planningpoker/internal/infra/transformers"
+)
+
+// BenchmarkStateBroadcast compares the serialization work done on every game update of a large game.
+// "room per player" builds and diffs the whole state for every player, as it was done with personal rooms only,
+// "game room" builds and diffs the shared state once and a small personal state per player.
+func BenchmarkStateBroadcast(b *testing.B) {
+	for _, players := range []int{10, 50, 200} {
+		updates := benchmarkUpdates(b, players)
+
+		b.Run(fmt.Sprintf("room per player/%d", players), func(b *testing.B) {
+			streams := newStreams()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				gState := updates[i%len(updates)]
+				for _, player := range gState.Players {
+					shared := mustTree(b, transformers.NewGameStateResponse(gState))
+					personal := mustTree(b, transformers.NewPersonalStateResponse(player))
+					streams.get(gState.GameID + player.UserID).next(map[string]interface{}{
+						"game": shared, "player": personal,
+					})
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("game room/%d", players), func(b *testing.B) {
+			streams := newStreams()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				gState := updates[i%len(updates)]
+				streams.get(gState.GameID).next(mustTree(b, transformers.NewGameStateResponse(gState)))
+				for _, player := range gState.Players {
+					personal := mustTree(b, transformers.NewPersonalStateResponse(player))
+					streams.get(gState.GameID + player.UserID).nextSnapshot(personal)
+				}
+			}
+		})
+	}
+}
+
+func TestStateStream_NextSnapshot(t *testing.T) {
+	t.Parallel()
+
+	s := &stateStream{}
+	snapshot := s.nextSnapshot(map[string]interface{}{"voted_card": "XS"})
+	require.NotNil(t, snapshot)
+	assert.Equal(t, uint64(1), snapshot.Seq)
+
+	assert.Nil(t, s.nextSnapshot(map[string]interface{}{"voted_card": "XS"}), "unchanged state should not be sent")
+
+	snapshot = s.nextSnapshot(map[string]interface{}{"voted_card": "S"})
+	require.NotNil(t, snapshot)
+	assert.Equal(t, uint64(2), snapshot.Seq)
+	assert.Equal(t, map[string]interface{}{"voted_card": "S"}, snapshot.State)
+}
+
+// benchmarkUpdates returns states of the game where players vote one by one.
+func benchmarkUpdates(b *testing.B, players int) []state.GameState {
+	deck, err := games.NewCardsDeck("Fibonacci", []games.Card{"1", "2", "3", "5", "8", "13", "?"})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	gState := state.GameState{
+		GameID:    "game-id",
+		Name:      "Sprint planning",
+		TicketURL: "https://tracker.example.com/issues/1",
+		CardsDeck: *deck,
+		State:     games.GameStateStarted,
+		Players:   make([]state.PlayerState, players),
+	}
+	for i := range gState.Players {
+		gState.Players[i] = state.PlayerState{
+			UserID: fmt.Sprintf("user-%d", i), Handle: fmt.Sprintf("handle-%d", i), Name: fmt.Sprintf("Player %d", i),
+			Confidence: games.ConfidenceNormal, CanReveal: true, Active: true, Seat: i,
+		}
+	}
+
+	updates := make([]state.GameState, 0, players)
+	for i := range gState.Players {
+		card := deck.Cards()[i%len(deck.Cards())]
+		next := gState
+		next.Players = append([]state.PlayerState(nil), gState.Players...)
+		next.Players[i].VotedCard = &card
+		updates = append(updates, next)
+		gState = next
+	}
+
+	return updates
+}
+
+func mustTree(b *testing.B, v interface{}) interface{} {
+	tree, err := jsonpatch.ToTree(v)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return tree
+}
@@ -2,36 +2,60 @@
 package auth
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
 
 	"planningpoker/internal/domain/users"
 )
 
+const tokenSeparator = "."
+
 type usersService interface {
-	AuthenticateByID(cmd users.AuthByIDCommand) (*users.User, error)
+	AuthenticateByID(ctx context.Context, cmd users.AuthByIDCommand) (*users.User, error)
 }
 
 // UserAuthenticator is a service to authenticate a use.
 type UserAuthenticator struct {
 	usersService
+	secret []byte
 }
 
 // NewUserAuthenticator creates a new user authenticator instance.
-func NewUserAuthenticator(us usersService) *UserAuthenticator {
+// Tokens are signed with the secret, an empty secret means that tokens are bare user IDs.
+func NewUserAuthenticator(us usersService, secret string) *UserAuthenticator {
 	return &UserAuthenticator{
 		usersService: us,
+		secret:       []byte(secret),
 	}
 }
 
+// IssueToken creates an auth token for the user.
+func (a *UserAuthenticator) IssueToken(userID string) string {
+	if len(a.secret) == 0 {
+		return userID
+	}
+
+	return userID + tokenSeparator + a.sign(userID)
+}
+
 // AuthenticateByToken authenticates user by bare token.
-func (a *UserAuthenticator) AuthenticateByToken(token string) (string, error) {
-	// for simplicity for now token is actual user ID
-	cmd, err := users.NewAuthByIDCommand(token)
+func (a *UserAuthenticator) AuthenticateByToken(ctx context.Context, token string) (string, error) {
+	id, err := a.userIDFromToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	cmd, err := users.NewAuthByIDCommand(id)
 	if err != nil {
 		return "", fmt.Errorf("unable to create an auth command: %w", err)
 	}
 
-	user, err := a.usersService.AuthenticateByID(*cmd)
+	user, err := a.usersService.AuthenticateByID(ctx, *cmd)
 	if err != nil {
 		return "", fmt.Errorf("unable to authenticate by ID: %w", err)
 	}
@@ -42,3 +66,27 @@ func (a *UserAuthenticator) AuthenticateByToken(token string) (string, error) {
 
 	return user.ID(), nil
 }
+
+func (a *UserAuthenticator) userIDFromToken(token string) (string, error) {
+	if len(a.secret) == 0 {
+		return token, nil
+	}
+
+	i := strings.LastIndex(token, tokenSeparator)
+	if i < 0 {
+		return "", errors.New("token is not signed")
+	}
+
+	id, signature := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(a.sign(id))) {
+		return "", errors.New("invalid token signature")
+	}
+
+	return id, nil
+}
+
+func (a *UserAuthenticator) sign(userID string) string {
+	mac := hmac.New(sha256.New, a.secret)
+	_, _ = mac.Write([]byte(userID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
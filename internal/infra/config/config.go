@@ -0,0 +1,285 @@
+// Package config contains the poker service configuration.
+package config
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"planningpoker/internal/infra/ratelimit"
+)
+
+const (
+	// StorageMemory is an in-memory storage backend, all data is lost on restart.
+	StorageMemory = "memory"
+	// StorageFile is an in-memory storage backend which is restored from a file on start and flushed on shutdown.
+	StorageFile = "file"
+
+	// TracingNone disables traces export.
+	TracingNone = "none"
+	// TracingStdout writes traces to the standard output.
+	TracingStdout = "stdout"
+	// TracingOTLP sends traces to an OTLP HTTP collector.
+	TracingOTLP = "otlp"
+
+	// LogFormatText is a human-readable log format.
+	LogFormatText = "text"
+	// LogFormatJSON is a structured log format.
+	LogFormatJSON = "json"
+
+	// minTokenSecretLen is a minimal length of a token secret which is hard enough to brute force.
+	minTokenSecretLen = 16
+)
+
+// Config is the whole service configuration.
+type Config struct {
+	HTTP      HTTPConfig      `yaml:"http"`
+	Storage   StorageConfig   `yaml:"storage"`
+	Auth      AuthConfig      `yaml:"auth"`
+	Log       LogConfig       `yaml:"log"`
+	Limits    LimitsConfig    `yaml:"limits"`
+	Cleanup   CleanupConfig   `yaml:"cleanup"`
+	Scheduler SchedulerConfig `yaml:"scheduler"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+}
+
+// HTTPConfig contains HTTP server settings.
+type HTTPConfig struct {
+	ListenAddr  string   `yaml:"listen_addr"`
+	TLSCertFile string   `yaml:"tls_cert_file"`
+	TLSKeyFile  string   `yaml:"tls_key_file"`
+	StaticDir   string   `yaml:"static_dir"`
+	GzipLevel   int      `yaml:"gzip_level"`
+	CORSOrigins []string `yaml:"cors_origins"`
+	// ShutdownTimeout limits the time given to drain connections and flush the data on shutdown.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+// TLSEnabled returns true if the server should serve HTTPS.
+func (c HTTPConfig) TLSEnabled() bool {
+	return c.TLSCertFile != ""
+}
+
+// StorageConfig contains storage settings.
+type StorageConfig struct {
+	Backend string `yaml:"backend"`
+	// Path is a snapshot file of the file backend.
+	Path string `yaml:"path"`
+}
+
+// AuthConfig contains authentication settings.
+type AuthConfig struct {
+	// TokenSecret is used to sign auth tokens, tokens are bare user IDs when it is empty.
+	TokenSecret string `yaml:"token_secret"`
+}
+
+// LogConfig contains logging settings.
+type LogConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+// LimitsConfig contains limits protecting the service resources.
+type LimitsConfig struct {
+	MaxBodyBytes int64 `yaml:"max_body_bytes"`
+	// IPRate and IPBurst limit API requests and socket connections per client IP, zero rate disables the limit.
+	IPRate  float64 `yaml:"ip_rate"`
+	IPBurst int     `yaml:"ip_burst"`
+	// TrustedProxies are IPs and CIDRs of reverse proxies, the client IP is read from the proxy headers
+	// only on requests coming from them. Empty list makes the peer address the client IP.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+	// UserRate and UserBurst limit authenticated API requests and socket events per user, zero rate disables the limit.
+	UserRate  float64 `yaml:"user_rate"`
+	UserBurst int     `yaml:"user_burst"`
+	// ChatRate and ChatBurst limit in-game chat messages and reactions per user, zero rate disables the limit.
+	ChatRate  float64 `yaml:"chat_rate"`
+	ChatBurst int     `yaml:"chat_burst"`
+	// MaxGamesPerUser and MaxPlayersPerGame cap the games data, zero disables the cap.
+	MaxGamesPerUser   int `yaml:"max_games_per_user"`
+	MaxPlayersPerGame int `yaml:"max_players_per_game"`
+}
+
+// CleanupConfig contains abandoned games and users cleanup settings.
+type CleanupConfig struct {
+	Interval time.Duration `yaml:"interval"`
+	GameTTL  time.Duration `yaml:"game_ttl"`
+	UserTTL  time.Duration `yaml:"user_ttl"`
+}
+
+// SchedulerConfig contains asynchronous voting deadlines settings.
+type SchedulerConfig struct {
+	// Interval is how often deadlines are checked, tickets are revealed at most this late.
+	Interval time.Duration `yaml:"interval"`
+}
+
+// MetricsConfig contains prometheus metrics settings.
+type MetricsConfig struct {
+	// Enabled exposes the metrics at /metrics endpoint.
+	Enabled bool `yaml:"enabled"`
+}
+
+// TracingConfig contains OpenTelemetry tracing settings.
+type TracingConfig struct {
+	Exporter string `yaml:"exporter"`
+	// Endpoint is a host:port of the OTLP collector.
+	Endpoint string `yaml:"endpoint"`
+	// Insecure disables TLS of the OTLP collector connection.
+	Insecure bool `yaml:"insecure"`
+	// SampleRatio is a fraction of traces to export, from 0 to 1.
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// Default returns the configuration used when nothing is overridden.
+func Default() Config {
+	return Config{
+		HTTP: HTTPConfig{
+			ListenAddr:      ":8080",
+			StaticDir:       "./web/dist",
+			GzipLevel:       gzip.DefaultCompression,
+			ShutdownTimeout: 15 * time.Second,
+		},
+		Storage: StorageConfig{
+			Backend: StorageMemory,
+		},
+		Log: LogConfig{
+			Level:  logrus.InfoLevel.String(),
+			Format: LogFormatText,
+		},
+		Limits: LimitsConfig{
+			MaxBodyBytes:      1 << 20,
+			IPRate:            5,
+			IPBurst:           20,
+			UserRate:          10,
+			UserBurst:         30,
+			ChatRate:          1,
+			ChatBurst:         5,
+			MaxGamesPerUser:   20,
+			MaxPlayersPerGame: 50,
+		},
+		Cleanup: CleanupConfig{
+			Interval: 10 * time.Minute,
+			GameTTL:  24 * time.Hour,
+			UserTTL:  90 * 24 * time.Hour,
+		},
+		Scheduler: SchedulerConfig{
+			Interval: 15 * time.Second,
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+		},
+		Tracing: TracingConfig{
+			Exporter:    TracingNone,
+			SampleRatio: 1,
+		},
+	}
+}
+
+// Validate checks that the configuration is consistent.
+func (c Config) Validate() error {
+	if _, _, err := net.SplitHostPort(c.HTTP.ListenAddr); err != nil {
+		return fmt.Errorf("http listen address: %w", err)
+	}
+
+	if (c.HTTP.TLSCertFile == "") != (c.HTTP.TLSKeyFile == "") {
+		return errors.New("both TLS certificate and key files should be provided")
+	}
+
+	if c.HTTP.StaticDir == "" {
+		return errors.New("static assets directory should be provided")
+	}
+
+	if c.HTTP.GzipLevel < gzip.HuffmanOnly || c.HTTP.GzipLevel > gzip.BestCompression {
+		return fmt.Errorf("gzip level should be in range [%d, %d]", gzip.HuffmanOnly, gzip.BestCompression)
+	}
+
+	for _, origin := range c.HTTP.CORSOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("CORS origin %q should be an http(s) origin or *", origin)
+		}
+	}
+
+	if c.HTTP.ShutdownTimeout <= 0 {
+		return errors.New("shutdown timeout should be positive")
+	}
+
+	switch c.Storage.Backend {
+	case StorageMemory:
+	case StorageFile:
+		if c.Storage.Path == "" {
+			return errors.New("storage path should be provided for the file backend")
+		}
+	default:
+		return fmt.Errorf("unknown storage backend %q", c.Storage.Backend)
+	}
+
+	if c.Auth.TokenSecret != "" && len(c.Auth.TokenSecret) < minTokenSecretLen {
+		return fmt.Errorf("token secret should be at least %d chars long", minTokenSecretLen)
+	}
+
+	if _, err := logrus.ParseLevel(c.Log.Level); err != nil {
+		return fmt.Errorf("log level: %w", err)
+	}
+
+	if c.Log.Format != LogFormatText && c.Log.Format != LogFormatJSON {
+		return fmt.Errorf("log format should be %q or %q", LogFormatText, LogFormatJSON)
+	}
+
+	if c.Limits.MaxBodyBytes <= 0 {
+		return errors.New("max body bytes should be positive")
+	}
+
+	if c.Limits.IPRate < 0 || c.Limits.UserRate < 0 || c.Limits.ChatRate < 0 {
+		return errors.New("rate limits should not be negative")
+	}
+
+	if (c.Limits.IPRate > 0 && c.Limits.IPBurst < 1) || (c.Limits.UserRate > 0 && c.Limits.UserBurst < 1) ||
+		(c.Limits.ChatRate > 0 && c.Limits.ChatBurst < 1) {
+		return errors.New("rate limit bursts should be positive")
+	}
+
+	if _, err := ratelimit.NewProxies(c.Limits.TrustedProxies); err != nil {
+		return err
+	}
+
+	if c.Limits.MaxGamesPerUser < 0 || c.Limits.MaxPlayersPerGame < 0 {
+		return errors.New("games caps should not be negative")
+	}
+
+	if c.Cleanup.Interval <= 0 {
+		return errors.New("cleanup interval should be positive")
+	}
+
+	if c.Cleanup.GameTTL < 0 || c.Cleanup.UserTTL < 0 {
+		return errors.New("cleanup TTLs should not be negative")
+	}
+
+	if c.Scheduler.Interval <= 0 {
+		return errors.New("scheduler interval should be positive")
+	}
+
+	switch c.Tracing.Exporter {
+	case TracingNone, TracingStdout:
+	case TracingOTLP:
+		if c.Tracing.Endpoint == "" {
+			return errors.New("tracing endpoint should be provided for the otlp exporter")
+		}
+	default:
+		return fmt.Errorf("unknown tracing exporter %q", c.Tracing.Exporter)
+	}
+
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return errors.New("tracing sample ratio should be in range [0, 1]")
+	}
+
+	return nil
+}
@@ -0,0 +1,222 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"planningpoker/internal/infra/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Defaults(t *testing.T) {
+	t.Parallel()
+
+	cfg, printConfig, err := config.Load(nil, noEnv)
+	require.NoError(t, err)
+	assert.False(t, printConfig)
+	assert.Equal(t, config.Default(), cfg)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	t.Parallel()
+
+	file := writeConfigFile(t, `
+http:
+  listen_addr: ":9000"
+  static_dir: /srv/web
+log:
+  level: debug
+cleanup:
+  game_ttl: 1h
+`)
+	env := map[string]string{
+		"POKER_CONFIG":      file,
+		"POKER_LISTEN_ADDR": ":9001",
+		"POKER_LOG_FORMAT":  "json",
+	}
+
+	cfg, printConfig, err := config.Load([]string{"--listen-addr", ":9002", "--print-config"}, envFrom(env))
+	require.NoError(t, err)
+
+	assert.True(t, printConfig)
+	assert.Equal(t, ":9002", cfg.HTTP.ListenAddr, "flags override env")
+	assert.Equal(t, config.LogFormatJSON, cfg.Log.Format, "env overrides defaults")
+	assert.Equal(t, "/srv/web", cfg.HTTP.StaticDir, "file overrides defaults")
+	assert.Equal(t, "debug", cfg.Log.Level)
+	assert.Equal(t, time.Hour, cfg.Cleanup.GameTTL)
+	assert.Equal(t, config.Default().Cleanup.UserTTL, cfg.Cleanup.UserTTL)
+}
+
+func TestLoad_BoolFlags(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"POKER_METRICS": "false"}
+	cfg, _, err := config.Load([]string{"--metrics", "--tracing-insecure", "--listen-addr", ":9002"}, envFrom(env))
+	require.NoError(t, err)
+	assert.True(t, cfg.Metrics.Enabled, "flag without a value sets true")
+	assert.True(t, cfg.Tracing.Insecure)
+	assert.Equal(t, ":9002", cfg.HTTP.ListenAddr, "next flag is not consumed as a value")
+
+	cfg, _, err = config.Load([]string{"--metrics=false"}, noEnv)
+	require.NoError(t, err)
+	assert.False(t, cfg.Metrics.Enabled)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		args     []string
+		env      map[string]string
+		file     string
+		expError string
+	}{
+		"unknown flag": {
+			args:     []string{"--foo"},
+			expError: "flags parsing: flag provided but not defined: -foo",
+		},
+		"wrong flag value": {
+			args:     []string{"--gzip-level", "max"},
+			expError: `flag --gzip-level: strconv.Atoi: parsing "max": invalid syntax`,
+		},
+		"wrong env value": {
+			env:      map[string]string{"POKER_GAME_TTL": "forever"},
+			expError: `env POKER_GAME_TTL: time: invalid duration "forever"`,
+		},
+		"unknown file field": {
+			file:     "http:\n  port: 80\n",
+			expError: "field port not found in type config.HTTPConfig",
+		},
+		"invalid listen address": {
+			args:     []string{"--listen-addr", "8080"},
+			expError: "invalid configuration: http listen address: address 8080: missing port in address",
+		},
+		"tls key without certificate": {
+			args:     []string{"--tls-key", "key.pem"},
+			expError: "invalid configuration: both TLS certificate and key files should be provided",
+		},
+		"wrong gzip level": {
+			args:     []string{"--gzip-level", "10"},
+			expError: "invalid configuration: gzip level should be in range [-2, 9]",
+		},
+		"wrong cors origin": {
+			args:     []string{"--cors-origins", "https://example.com,example.com"},
+			expError: `invalid configuration: CORS origin "example.com" should be an http(s) origin or *`,
+		},
+		"wrong trusted proxy": {
+			args:     []string{"--trusted-proxies", "10.0.0.0/8,proxy.local"},
+			expError: `invalid configuration: trusted proxy "proxy.local" should be an IP or a CIDR`,
+		},
+		"unknown storage": {
+			args:     []string{"--storage", "postgres"},
+			expError: `invalid configuration: unknown storage backend "postgres"`,
+		},
+		"file storage without path": {
+			args:     []string{"--storage", "file"},
+			expError: "invalid configuration: storage path should be provided for the file backend",
+		},
+		"wrong shutdown timeout": {
+			args:     []string{"--shutdown-timeout", "0s"},
+			expError: "invalid configuration: shutdown timeout should be positive",
+		},
+		"short token secret": {
+			args:     []string{"--token-secret", "secret"},
+			expError: "invalid configuration: token secret should be at least 16 chars long",
+		},
+		"unknown log level": {
+			args:     []string{"--log-level", "loud"},
+			expError: `invalid configuration: log level: not a valid logrus Level: "loud"`,
+		},
+		"wrong body limit": {
+			args:     []string{"--max-body-bytes", "0"},
+			expError: "invalid configuration: max body bytes should be positive",
+		},
+		"negative rate limit": {
+			args:     []string{"--ip-rate", "-1"},
+			expError: "invalid configuration: rate limits should not be negative",
+		},
+		"wrong rate limit burst": {
+			args:     []string{"--user-burst", "0"},
+			expError: "invalid configuration: rate limit bursts should be positive",
+		},
+		"wrong chat rate limit burst": {
+			args:     []string{"--chat-burst", "0"},
+			expError: "invalid configuration: rate limit bursts should be positive",
+		},
+		"negative games cap": {
+			args:     []string{"--max-players-per-game", "-1"},
+			expError: "invalid configuration: games caps should not be negative",
+		},
+		"unknown tracing exporter": {
+			args:     []string{"--tracing-exporter", "jaeger"},
+			expError: `invalid configuration: unknown tracing exporter "jaeger"`,
+		},
+		"otlp exporter without endpoint": {
+			args:     []string{"--tracing-exporter", "otlp"},
+			expError: "invalid configuration: tracing endpoint should be provided for the otlp exporter",
+		},
+		"wrong sample ratio": {
+			args:     []string{"--tracing-sample-ratio", "1.5"},
+			expError: "invalid configuration: tracing sample ratio should be in range [0, 1]",
+		},
+		"negative ttl": {
+			args:     []string{"--user-ttl", "-1h"},
+			expError: "invalid configuration: cleanup TTLs should not be negative",
+		},
+		"wrong scheduler interval": {
+			args:     []string{"--scheduler-interval", "0s"},
+			expError: "invalid configuration: scheduler interval should be positive",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			env := tt.env
+			if tt.file != "" {
+				env = map[string]string{"POKER_CONFIG": writeConfigFile(t, tt.file)}
+			}
+
+			_, _, err := config.Load(tt.args, envFrom(env))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expError)
+		})
+	}
+}
+
+func TestConfig_YAMLMasksSecrets(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Default()
+	cfg.Auth.TokenSecret = "very-secret-token-value"
+
+	out, err := cfg.YAML()
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), cfg.Auth.TokenSecret)
+	assert.Contains(t, string(out), "token_secret:")
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func envFrom(env map[string]string) func(string) string {
+	return func(key string) string {
+		return env[key]
+	}
+}
+
+func noEnv(string) string {
+	return ""
+}
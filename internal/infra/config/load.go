@@ -0,0 +1,267 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	envPrefix    = "POKER_"
+	maskedSecret = "******"
+)
+
+// option is a single configuration value which can be overridden by a flag or an environment variable.
+type option struct {
+	name   string
+	usage  string
+	setter setter
+}
+
+// setter parses a raw option value into the configuration.
+type setter interface {
+	set(c *Config, v string) error
+}
+
+type setterFunc func(c *Config, v string) error
+
+func (f setterFunc) set(c *Config, v string) error {
+	return f(c, v)
+}
+
+// boolSetter parses a boolean option, its flag can be provided without a value, e.g. --metrics.
+type boolSetter func(c *Config, v string) error
+
+func (f boolSetter) set(c *Config, v string) error {
+	return f(c, v)
+}
+
+// flagValue keeps the raw value of the flag, so flags are applied after the file and environment variables.
+type flagValue struct {
+	value  string
+	isBool bool
+}
+
+func (f *flagValue) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+func (f *flagValue) Set(v string) error {
+	f.value = v
+	return nil
+}
+
+// IsBoolFlag makes the flag package accept a boolean flag without a value.
+func (f *flagValue) IsBoolFlag() bool {
+	return f != nil && f.isBool
+}
+
+// env returns the environment variable name of the option, e.g. POKER_LISTEN_ADDR for listen-addr.
+func (o option) env() string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(o.name, "-", "_"))
+}
+
+var options = []option{
+	{"listen-addr", "HTTP listen address", setString(func(c *Config) *string { return &c.HTTP.ListenAddr })},
+	{"tls-cert", "TLS certificate file, HTTPS is served when provided", setString(func(c *Config) *string { return &c.HTTP.TLSCertFile })},
+	{"tls-key", "TLS private key file", setString(func(c *Config) *string { return &c.HTTP.TLSKeyFile })},
+	{"static-dir", "frontend static assets directory", setString(func(c *Config) *string { return &c.HTTP.StaticDir })},
+	{"gzip-level", "gzip compression level", setInt(func(c *Config) *int { return &c.HTTP.GzipLevel })},
+	{"cors-origins", "comma separated list of allowed CORS origins", setList(func(c *Config) *[]string { return &c.HTTP.CORSOrigins })},
+	{"shutdown-timeout", "graceful shutdown timeout", setDuration(func(c *Config) *time.Duration { return &c.HTTP.ShutdownTimeout })},
+	{"storage", "storage backend: memory or file", setString(func(c *Config) *string { return &c.Storage.Backend })},
+	{"storage-path", "snapshot file of the file storage backend", setString(func(c *Config) *string { return &c.Storage.Path })},
+	{"token-secret", "auth tokens signing secret", setString(func(c *Config) *string { return &c.Auth.TokenSecret })},
+	{"log-level", "log level", setString(func(c *Config) *string { return &c.Log.Level })},
+	{"log-format", "log format: text or json", setString(func(c *Config) *string { return &c.Log.Format })},
+	{"max-body-bytes", "maximal HTTP request body size", setInt64(func(c *Config) *int64 { return &c.Limits.MaxBodyBytes })},
+	{"ip-rate", "API requests and socket connections per second per client IP, 0 disables", setFloat(func(c *Config) *float64 { return &c.Limits.IPRate })},
+	{"ip-burst", "burst of API requests and socket connections per client IP", setInt(func(c *Config) *int { return &c.Limits.IPBurst })},
+	{"trusted-proxies", "comma separated IPs and CIDRs of reverse proxies trusted to set X-Forwarded-For", setList(func(c *Config) *[]string { return &c.Limits.TrustedProxies })},
+	{"user-rate", "API requests and socket events per second per user, 0 disables", setFloat(func(c *Config) *float64 { return &c.Limits.UserRate })},
+	{"user-burst", "burst of API requests and socket events per user", setInt(func(c *Config) *int { return &c.Limits.UserBurst })},
+	{"chat-rate", "in-game chat messages and reactions per second per user, 0 disables", setFloat(func(c *Config) *float64 { return &c.Limits.ChatRate })},
+	{"chat-burst", "burst of in-game chat messages and reactions per user", setInt(func(c *Config) *int { return &c.Limits.ChatBurst })},
+	{"max-games-per-user", "maximal number of not archived games facilitated by a user, 0 disables", setInt(func(c *Config) *int { return &c.Limits.MaxGamesPerUser })},
+	{"max-players-per-game", "maximal number of players in a game, 0 disables", setInt(func(c *Config) *int { return &c.Limits.MaxPlayersPerGame })},
+	{"cleanup-interval", "abandoned games and users cleanup interval", setDuration(func(c *Config) *time.Duration { return &c.Cleanup.Interval })},
+	{"game-ttl", "idle time after which a game is archived, 0 disables", setDuration(func(c *Config) *time.Duration { return &c.Cleanup.GameTTL })},
+	{"user-ttl", "time after which a not seen user is deleted, 0 disables", setDuration(func(c *Config) *time.Duration { return &c.Cleanup.UserTTL })},
+	{"scheduler-interval", "asynchronous voting deadlines check interval", setDuration(func(c *Config) *time.Duration { return &c.Scheduler.Interval })},
+	{"metrics", "expose prometheus metrics at /metrics", setBool(func(c *Config) *bool { return &c.Metrics.Enabled })},
+	{"tracing-exporter", "traces exporter: none, stdout or otlp", setString(func(c *Config) *string { return &c.Tracing.Exporter })},
+	{"tracing-endpoint", "OTLP HTTP collector host:port", setString(func(c *Config) *string { return &c.Tracing.Endpoint })},
+	{"tracing-insecure", "disable TLS of the OTLP collector connection", setBool(func(c *Config) *bool { return &c.Tracing.Insecure })},
+	{"tracing-sample-ratio", "fraction of exported traces", setFloat(func(c *Config) *float64 { return &c.Tracing.SampleRatio })},
+}
+
+// Load builds the configuration from defaults, an optional YAML file, environment variables and flags,
+// each next source overrides the previous one. printConfig reports that --print-config flag was provided.
+func Load(args []string, getenv func(string) string) (cfg Config, printConfig bool, err error) {
+	fs := flag.NewFlagSet("poker", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	configFile := fs.String("config", getenv(envPrefix+"CONFIG"), "YAML configuration file")
+	printOnly := fs.Bool("print-config", false, "print the effective configuration and exit")
+
+	raw := make(map[string]*flagValue, len(options))
+	for _, o := range options {
+		_, isBool := o.setter.(boolSetter)
+		raw[o.name] = &flagValue{isBool: isBool}
+		fs.Var(raw[o.name], o.name, fmt.Sprintf("%s (env %s)", o.usage, o.env()))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return Config{}, false, fmt.Errorf("flags parsing: %w", err)
+	}
+
+	cfg = Default()
+
+	if *configFile != "" {
+		if err := loadFile(*configFile, &cfg); err != nil {
+			return Config{}, false, err
+		}
+	}
+
+	for _, o := range options {
+		if v := getenv(o.env()); v != "" {
+			if err := o.setter.set(&cfg, v); err != nil {
+				return Config{}, false, fmt.Errorf("env %s: %w", o.env(), err)
+			}
+		}
+	}
+
+	setByFlags := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { setByFlags[f.Name] = true })
+	for _, o := range options {
+		if setByFlags[o.name] {
+			if err := o.setter.set(&cfg, raw[o.name].value); err != nil {
+				return Config{}, false, fmt.Errorf("flag --%s: %w", o.name, err)
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, false, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, *printOnly, nil
+}
+
+// YAML returns the configuration in YAML format with secrets masked.
+func (c Config) YAML() ([]byte, error) {
+	if c.Auth.TokenSecret != "" {
+		c.Auth.TokenSecret = maskedSecret
+	}
+
+	buf := bytes.Buffer{}
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func setString(field func(*Config) *string) setter {
+	return setterFunc(func(c *Config, v string) error {
+		*field(c) = v
+		return nil
+	})
+}
+
+func setList(field func(*Config) *[]string) setter {
+	return setterFunc(func(c *Config, v string) error {
+		list := make([]string, 0)
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		*field(c) = list
+		return nil
+	})
+}
+
+func setInt(field func(*Config) *int) setter {
+	return setterFunc(func(c *Config, v string) error {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		*field(c) = i
+		return nil
+	})
+}
+
+func setInt64(field func(*Config) *int64) setter {
+	return setterFunc(func(c *Config, v string) error {
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return err
+		}
+		*field(c) = i
+		return nil
+	})
+}
+
+func setBool(field func(*Config) *bool) setter {
+	return boolSetter(func(c *Config, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		*field(c) = b
+		return nil
+	})
+}
+
+func setFloat(field func(*Config) *float64) setter {
+	return setterFunc(func(c *Config, v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		*field(c) = f
+		return nil
+	})
+}
+
+func setDuration(field func(*Config) *time.Duration) setter {
+	return setterFunc(func(c *Config, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*field(c) = d
+		return nil
+	})
+}
@@ -2,15 +2,20 @@
 package eventbus
 
 import (
+	"context"
 	"sync"
 
 	"planningpoker/internal/domain/events"
+	"planningpoker/internal/infra/logging"
+	"planningpoker/internal/infra/tracing"
 )
 
 // InternalBus represents a simple in-service pub/sub service for domain events.
 type InternalBus struct {
 	m           sync.RWMutex
 	subscribers map[string][]events.Consumer
+	// inflight tracks running consumers, so the bus can be drained on shutdown.
+	inflight sync.WaitGroup
 }
 
 // NewInternalBus creates a new internal bus instance.
@@ -21,17 +26,42 @@ func NewInternalBus() *InternalBus {
 }
 
 // Publish publishes the provided event.
-func (b *InternalBus) Publish(event events.DomainEvent) error {
+// The trace context and the logging correlation fields of ctx are stored in the event metadata,
+// so consumers continue the trace and log with the same fields.
+func (b *InternalBus) Publish(ctx context.Context, event events.DomainEvent) error {
+	ctx, span := tracing.Start(ctx, "eventbus.Publish", tracing.AttrEventType.String(event.EventType()))
+	defer span.End()
+
+	md := event.Metadata()
+	tracing.Inject(ctx, md)
+	logging.Inject(ctx, md)
+	event = event.WithMetadata(md)
+
 	b.m.RLock()
 	defer b.m.RUnlock()
 
 	for _, c := range b.subscribers[event.EventType()] {
-		go c(event)
+		b.inflight.Add(1)
+		go b.consume(c, event)
 	}
 
 	return nil
 }
 
+// consume runs the consumer in a context detached from the publisher cancellation, but continuing its trace and
+// keeping its logging correlation fields.
+func (b *InternalBus) consume(c events.Consumer, event events.DomainEvent) {
+	defer b.inflight.Done()
+
+	ctx := tracing.Extract(context.Background(), event.Metadata())
+	ctx = logging.Extract(ctx, event.Metadata())
+	ctx, span := tracing.Start(ctx, "eventbus.Consume",
+		tracing.AttrEventType.String(event.EventType()), tracing.AttrAggregateID.String(event.AggregateID()))
+	defer span.End()
+
+	c(ctx, event)
+}
+
 // Subscribe is a way for services to subscribe to some events.
 func (b *InternalBus) Subscribe(consumer events.Consumer, eventTypes ...string) {
 	b.m.Lock()
@@ -41,3 +71,20 @@ func (b *InternalBus) Subscribe(consumer events.Consumer, eventTypes ...string)
 		b.subscribers[typ] = append(b.subscribers[typ], consumer)
 	}
 }
+
+// Drain waits until all the published events are consumed, including events published by consumers themselves.
+// Event producers should be stopped before, otherwise the bus may never become idle.
+func (b *InternalBus) Drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		b.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,6 +1,8 @@
 package eventbus_test
 
 import (
+	"context"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -17,14 +19,14 @@ func TestInternalBus(t *testing.T) {
 	calledCh := make(chan struct{})
 	eventType := "event1"
 
-	consumer := func(event events.DomainEvent) {
+	consumer := func(_ context.Context, event events.DomainEvent) {
 		assert.Equal(t, eventType, event.EventType())
 		calledCh <- struct{}{}
 	}
 
 	bus.Subscribe(consumer, "event1", "event2")
 
-	err := bus.Publish(events.NewDomainEventBuilder(eventType).Build())
+	err := bus.Publish(context.Background(), events.NewDomainEventBuilder(eventType).Build())
 	require.NoError(t, err)
 
 	select {
@@ -34,3 +36,30 @@ func TestInternalBus(t *testing.T) {
 		t.Fatalf("consumer func was not called")
 	}
 }
+
+func TestInternalBus_Drain(t *testing.T) {
+	bus := eventbus.NewInternalBus()
+
+	var consumed int32
+	release := make(chan struct{})
+
+	bus.Subscribe(func(_ context.Context, event events.DomainEvent) {
+		<-release
+		atomic.AddInt32(&consumed, 1)
+		// consumers may publish new events while the bus is draining
+		require.NoError(t, bus.Publish(context.Background(), events.NewDomainEventBuilder("event2").Build()))
+	}, "event1")
+	bus.Subscribe(func(_ context.Context, event events.DomainEvent) {
+		atomic.AddInt32(&consumed, 1)
+	}, "event2")
+
+	require.NoError(t, bus.Publish(context.Background(), events.NewDomainEventBuilder("event1").Build()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, bus.Drain(ctx), context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, bus.Drain(context.Background()))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&consumed))
+}
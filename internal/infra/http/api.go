@@ -2,55 +2,157 @@
 package http
 
 import (
+	"context"
 	"errors"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"planningpoker/internal/domain/audit"
 	"planningpoker/internal/domain/users"
+	"planningpoker/internal/infra/ratelimit"
 )
 
 // UserAuthenticator is a contract to authenticate users.
 type userAuthenticator interface {
 	// AuthenticateByToken returns a user ID or error if user is not authenticated
-	AuthenticateByToken(token string) (string, error)
+	AuthenticateByToken(ctx context.Context, token string) (string, error)
+	// IssueToken creates an auth token for the user
+	IssueToken(userID string) string
 }
 
 // UsersService is a contract to perform user related actions.
 type UsersService interface {
-	Register(cmd users.RegisterCommand) (*users.User, error)
-	Update(cmd users.UpdateCommand) (*users.User, error)
-	Get(userID string) (*users.User, error)
+	Register(ctx context.Context, cmd users.RegisterCommand) (*users.User, error)
+	Update(ctx context.Context, cmd users.UpdateCommand) (*users.User, error)
+	Get(ctx context.Context, userID string) (*users.User, error)
+}
+
+// AuditService is a contract to read game audit trails.
+type AuditService interface {
+	GameLog(ctx context.Context, gameID, userID string) ([]audit.Entry, error)
 }
 
 // API contains all HTTP API handlers.
 type API struct {
-	usersService  UsersService
-	authenticator userAuthenticator
+	usersService   UsersService
+	auditService   AuditService
+	teamsService   TeamsService
+	roomsService   RoomsService
+	reportsService ReportsService
+	batchesService BatchesService
+	authenticator  userAuthenticator
+	logger         *logrus.Entry
+	// ipLimiter and userLimiter are optional, nil limiters allow everything.
+	ipLimiter   *ratelimit.Limiter
+	userLimiter *ratelimit.Limiter
+	// proxies are trusted to report the client IP, nil trusts nobody.
+	proxies *ratelimit.Proxies
 }
 
 // NewAPI creates a new API instance.
-func NewAPI(us UsersService, auth userAuthenticator) (*API, error) {
+func NewAPI(
+	us UsersService,
+	as AuditService,
+	ts TeamsService,
+	rs RoomsService,
+	rps ReportsService,
+	bs BatchesService,
+	auth userAuthenticator,
+	logger *logrus.Entry,
+) (*API, error) {
 	if us == nil {
 		return nil, errors.New("users service should be provided")
 	}
 
+	if as == nil {
+		return nil, errors.New("audit service should be provided")
+	}
+
+	if ts == nil {
+		return nil, errors.New("teams service should be provided")
+	}
+
+	if rs == nil {
+		return nil, errors.New("rooms service should be provided")
+	}
+
+	if rps == nil {
+		return nil, errors.New("reports service should be provided")
+	}
+
+	if bs == nil {
+		return nil, errors.New("batches service should be provided")
+	}
+
 	if auth == nil {
 		return nil, errors.New("user authenticator should be provided")
 	}
 
+	if logger == nil {
+		return nil, errors.New("logger should be provided")
+	}
+
 	return &API{
-		usersService:  us,
-		authenticator: auth,
+		usersService:   us,
+		auditService:   as,
+		teamsService:   ts,
+		roomsService:   rs,
+		reportsService: rps,
+		batchesService: bs,
+		authenticator:  auth,
+		logger:         logger,
 	}, nil
 }
 
+// LimitRate limits API requests per client IP and authenticated requests per user.
+// It should be called before the routes are set up.
+func (h *API) LimitRate(perIP, perUser *ratelimit.Limiter) {
+	h.ipLimiter = perIP
+	h.userLimiter = perUser
+}
+
+// TrustProxies makes the client IP to be read from the proxy headers of requests coming from the proxies.
+// It should be called before the routes are set up.
+func (h *API) TrustProxies(proxies *ratelimit.Proxies) {
+	h.proxies = proxies
+}
+
 // SetupRoutes creates HTTP API routes and binds them to handlers.
 func (h *API) SetupRoutes(r gin.IRoutes) {
 	r.GET("/alive", h.Alive)
 
-	r.POST("/api/v1/register", h.register)
+	r.POST("/api/v1/register", h.limitByIP, h.register)
+
+	r.GET("/api/v1/me", h.limitByIP, h.withUser(h.currentUser))
+	r.PUT("/api/v1/me", h.limitByIP, h.withUser(h.changeUserData))
+
+	r.GET("/api/v1/games/:id/audit", h.limitByIP, h.withUser(h.gameAudit))
+
+	r.POST("/api/v1/teams", h.limitByIP, h.withUser(h.createTeam))
+	r.GET("/api/v1/teams", h.limitByIP, h.withUser(h.listTeams))
+	r.POST("/api/v1/memberships", h.limitByIP, h.withUser(h.joinTeam))
+	r.GET("/api/v1/teams/:id", h.limitByIP, h.withUser(h.getTeam))
+	r.PUT("/api/v1/teams/:id", h.limitByIP, h.withUser(h.updateTeam))
+	r.POST("/api/v1/teams/:id/join_code", h.limitByIP, h.withUser(h.rotateJoinCode))
+	r.PUT("/api/v1/teams/:id/members/:handle", h.limitByIP, h.withUser(h.changeMemberRole))
+	r.DELETE("/api/v1/teams/:id/members/:handle", h.limitByIP, h.withUser(h.removeMember))
+	r.GET("/api/v1/teams/:id/games", h.limitByIP, h.withUser(h.teamGames))
+	r.POST("/api/v1/teams/:id/games", h.limitByIP, h.withUser(h.createTeamGame))
+	r.GET("/api/v1/teams/:id/report", h.limitByIP, h.withUser(h.teamReport))
+
+	r.POST("/api/v1/rooms", h.limitByIP, h.withUser(h.createRoom))
+	r.GET("/api/v1/rooms", h.limitByIP, h.withUser(h.listRooms))
+	r.GET("/api/v1/rooms/:slug", h.limitByIP, h.withUser(h.getRoom))
+	r.PUT("/api/v1/rooms/:slug", h.limitByIP, h.withUser(h.updateRoom))
+	r.POST("/api/v1/rooms/:slug/open", h.limitByIP, h.withUser(h.openRoom))
+	r.GET("/api/v1/rooms/:slug/report", h.limitByIP, h.withUser(h.roomReport))
 
-	r.GET("/api/v1/me", h.withUser(h.currentUser))
-	r.PUT("/api/v1/me", h.withUser(h.changeUserData))
+	r.POST("/api/v1/batches", h.limitByIP, h.withUser(h.createBatch))
+	r.GET("/api/v1/batches", h.limitByIP, h.withUser(h.listBatches))
+	r.GET("/api/v1/batches/:id", h.limitByIP, h.withUser(h.getBatch))
+	r.POST("/api/v1/batches/:id/join", h.limitByIP, h.withUser(h.joinBatch))
+	r.PUT("/api/v1/batches/:id/tickets/:ticket/vote", h.limitByIP, h.withUser(h.voteBatchTicket))
 }
 
 // Alive returns status 200 with empty body.
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type auditEntryResponse struct {
+	Action      string            `json:"action"`
+	ActorHandle string            `json:"actor_handle,omitempty"`
+	ActorName   string            `json:"actor_name,omitempty"`
+	Before      map[string]string `json:"before,omitempty"`
+	After       map[string]string `json:"after,omitempty"`
+	OccurredAt  time.Time         `json:"occurred_at"`
+}
+
+// gameAudit returns the game trail, actors are identified by their public handles and names.
+func (h *API) gameAudit(c *gin.Context, userID string) {
+	list, err := h.auditService.GameLog(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	entries := make([]auditEntryResponse, len(list))
+	for i, e := range list {
+		entries[i] = auditEntryResponse{
+			Action:      e.Action,
+			ActorHandle: e.ActorHandle,
+			ActorName:   e.ActorName,
+			Before:      e.Before,
+			After:       e.After,
+			OccurredAt:  e.OccurredAt,
+		}
+	}
+
+	success(c, gin.H{
+		"entries": entries,
+	})
+}
@@ -0,0 +1,267 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"planningpoker/internal/domain/batches"
+	"planningpoker/internal/domain/games"
+)
+
+// BatchesService is a contract to perform asynchronous voting actions.
+type BatchesService interface {
+	Create(ctx context.Context, cmd batches.CreateBatchCommand) (*batches.Batch, error)
+	Get(ctx context.Context, id, userID string) (*batches.Batch, error)
+	ListByParticipant(ctx context.Context, userID string) ([]batches.Batch, error)
+	Join(ctx context.Context, cmd batches.JoinBatchCommand) error
+	Vote(ctx context.Context, cmd batches.VoteCommand) error
+}
+
+type batchPayload struct {
+	Name string `json:"name"`
+	// TeamID is optional, all the team members become participants of a team batch.
+	TeamID    string `json:"team_id"`
+	CardsDeck struct {
+		Name  string   `json:"name"`
+		Types []string `json:"types"`
+	} `json:"cards_deck"`
+	Tickets []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"tickets"`
+	Deadline time.Time `json:"deadline"`
+}
+
+func (pl batchPayload) command(userID string) (*batches.CreateBatchCommand, error) {
+	deck, err := gameSettingsPayload{CardsDeck: pl.CardsDeck}.deck()
+	if err != nil {
+		return nil, err
+	}
+
+	tickets := make([]batches.TicketData, len(pl.Tickets))
+	for i, t := range pl.Tickets {
+		tickets[i] = batches.TicketData{Name: t.Name, URL: t.URL}
+	}
+
+	cmd, err := batches.NewCreateBatchCommand(pl.Name, userID, *deck, tickets, pl.Deadline)
+	if err != nil {
+		return nil, err
+	}
+	cmd.TeamID = pl.TeamID
+
+	return cmd, nil
+}
+
+type batchVotePayload struct {
+	Card string `json:"card"`
+}
+
+type batchParticipantResponse struct {
+	Name        string `json:"name"`
+	Facilitator bool   `json:"facilitator"`
+	Me          bool   `json:"me"`
+}
+
+type batchVoteResponse struct {
+	Name string `json:"name"`
+	Card string `json:"card"`
+}
+
+type batchTicketResponse struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	Revealed bool   `json:"revealed"`
+	// Voted lists names of participants who voted, their cards are sent in Votes once the ticket is revealed.
+	Voted      []string            `json:"voted"`
+	MyCard     string              `json:"my_card"`
+	Votes      []batchVoteResponse `json:"votes,omitempty"`
+	RevealedAt *time.Time          `json:"revealed_at,omitempty"`
+}
+
+type batchResponse struct {
+	ID           string                     `json:"id"`
+	Name         string                     `json:"name"`
+	TeamID       string                     `json:"team_id,omitempty"`
+	Facilitator  bool                       `json:"facilitator"`
+	Cards        []string                   `json:"cards"`
+	Deadline     time.Time                  `json:"deadline"`
+	Closed       bool                       `json:"closed"`
+	Participants []batchParticipantResponse `json:"participants"`
+	Tickets      []batchTicketResponse      `json:"tickets"`
+}
+
+func (h *API) createBatch(c *gin.Context, userID string) {
+	pl := batchPayload{}
+	if err := c.BindJSON(&pl); err != nil {
+		badRequestError(c, err)
+		return
+	}
+
+	cmd, err := pl.command(userID)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	batch, err := h.batchesService.Create(c.Request.Context(), *cmd)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, h.newBatchResponse(c.Request.Context(), *batch, userID))
+}
+
+func (h *API) listBatches(c *gin.Context, userID string) {
+	list, err := h.batchesService.ListByParticipant(c.Request.Context(), userID)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	resp := make([]gin.H, len(list))
+	for i, batch := range list {
+		// pending tickets are waiting for the user vote
+		pending := 0
+		for _, t := range batch.Tickets() {
+			if _, ok := t.Votes[userID]; !ok && !t.IsRevealed() {
+				pending++
+			}
+		}
+
+		resp[i] = gin.H{
+			"id":          batch.ID(),
+			"name":        batch.Name(),
+			"deadline":    batch.Deadline(),
+			"closed":      batch.IsClosed(),
+			"facilitator": batch.IsFacilitator(userID),
+			"tickets":     len(batch.Tickets()),
+			"pending":     pending,
+		}
+	}
+
+	success(c, gin.H{
+		"batches": resp,
+	})
+}
+
+func (h *API) getBatch(c *gin.Context, userID string) {
+	h.respondBatch(c, c.Param("id"), userID)
+}
+
+func (h *API) joinBatch(c *gin.Context, userID string) {
+	cmd, err := batches.NewJoinBatchCommand(c.Param("id"), userID)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	if err := h.batchesService.Join(c.Request.Context(), *cmd); err != nil {
+		domainError(c, err)
+		return
+	}
+
+	h.respondBatch(c, cmd.BatchID, userID)
+}
+
+func (h *API) voteBatchTicket(c *gin.Context, userID string) {
+	pl := batchVotePayload{}
+	if err := c.BindJSON(&pl); err != nil {
+		badRequestError(c, err)
+		return
+	}
+
+	card, err := games.NewCard(pl.Card)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	cmd, err := batches.NewVoteCommand(c.Param("id"), c.Param("ticket"), userID, *card)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	if err := h.batchesService.Vote(c.Request.Context(), *cmd); err != nil {
+		domainError(c, err)
+		return
+	}
+
+	h.respondBatch(c, cmd.BatchID, userID)
+}
+
+// respondBatch responds with the current batch state as the user sees it.
+func (h *API) respondBatch(c *gin.Context, id, userID string) {
+	batch, err := h.batchesService.Get(c.Request.Context(), id, userID)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, h.newBatchResponse(c.Request.Context(), *batch, userID))
+}
+
+// newBatchResponse creates a batch response, cards of other participants are shown only for revealed tickets.
+func (h *API) newBatchResponse(ctx context.Context, batch batches.Batch, userID string) batchResponse {
+	resp := batchResponse{
+		ID:           batch.ID(),
+		Name:         batch.Name(),
+		TeamID:       batch.TeamID(),
+		Facilitator:  batch.IsFacilitator(userID),
+		Cards:        make([]string, len(batch.CardsDeck().Cards())),
+		Deadline:     batch.Deadline(),
+		Closed:       batch.IsClosed(),
+		Participants: make([]batchParticipantResponse, 0, len(batch.Participants())),
+		Tickets:      make([]batchTicketResponse, len(batch.Tickets())),
+	}
+
+	for i, card := range batch.CardsDeck().Cards() {
+		resp.Cards[i] = card.Type()
+	}
+
+	names := make(map[string]string, len(batch.Participants()))
+	participantIDs := batch.ParticipantIDs()
+	for _, uid := range participantIDs {
+		names[uid] = h.userName(ctx, uid)
+		resp.Participants = append(resp.Participants, batchParticipantResponse{
+			Name:        names[uid],
+			Facilitator: batch.IsFacilitator(uid),
+			Me:          uid == userID,
+		})
+	}
+
+	for i, t := range batch.Tickets() {
+		ticket := batchTicketResponse{
+			ID:       t.ID,
+			Name:     t.Name,
+			URL:      t.URL,
+			Revealed: t.IsRevealed(),
+			Voted:    make([]string, 0, len(t.Votes)),
+		}
+		if card, ok := t.Votes[userID]; ok {
+			ticket.MyCard = card.Type()
+		}
+		if t.IsRevealed() {
+			revealedAt := t.RevealedAt
+			ticket.RevealedAt = &revealedAt
+		}
+
+		for _, uid := range participantIDs {
+			card, ok := t.Votes[uid]
+			if !ok {
+				continue
+			}
+			ticket.Voted = append(ticket.Voted, names[uid])
+			if t.IsRevealed() {
+				ticket.Votes = append(ticket.Votes, batchVoteResponse{Name: names[uid], Card: card.Type()})
+			}
+		}
+		resp.Tickets[i] = ticket
+	}
+
+	return resp
+}
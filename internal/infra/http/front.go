@@ -1,24 +1,31 @@
 package http
 
 import (
+	"path/filepath"
+
 	"github.com/gin-gonic/contrib/static"
 	"github.com/gin-gonic/gin"
 )
 
 // Frontend represents HTTP API for frontend related assets.
-type Frontend struct{}
+type Frontend struct {
+	staticDir string
+}
 
-// NewFrontend creates a new frontend provider instance.
-func NewFrontend() *Frontend {
-	return &Frontend{}
+// NewFrontend creates a new frontend provider instance serving assets from the static directory.
+func NewFrontend(staticDir string) *Frontend {
+	return &Frontend{
+		staticDir: staticDir,
+	}
 }
 
 // SetupRoutes creates a frontend API instance in order to serve frontend related files.
 func (f *Frontend) SetupRoutes(r *gin.Engine) {
-	r.Use(static.Serve("/", static.LocalFile("./web/dist", true)))
+	r.Use(static.Serve("/", static.LocalFile(f.staticDir, true)))
 
 	// serve all unknown routes with the index file to support browser-level routing
+	index := filepath.Join(f.staticDir, "index.html")
 	r.NoRoute(func(c *gin.Context) {
-		c.File("./web/dist/index.html")
+		c.File(index)
 	})
 }
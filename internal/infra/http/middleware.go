@@ -2,11 +2,26 @@ package http
 
 import (
 	"errors"
+	"net/http"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
 
 	"github.com/gin-gonic/gin"
+
+	"planningpoker/internal/infra/logging"
+	"planningpoker/internal/infra/ratelimit"
+	"planningpoker/internal/infra/tracing"
+)
+
+const (
+	// RequestIDHeader is a header carrying the request ID, it is accepted from clients and proxies if valid.
+	RequestIDHeader = "X-Request-ID"
+
+	maxRequestIDLength = 64
 )
 
 func (h *API) withUser(cb func(*gin.Context, string)) gin.HandlerFunc {
@@ -18,13 +33,136 @@ func (h *API) withUser(cb func(*gin.Context, string)) gin.HandlerFunc {
 			return
 		}
 
-		userID, err := h.authenticator.AuthenticateByToken(parts[1])
+		userID, err := h.authenticator.AuthenticateByToken(c.Request.Context(), parts[1])
 		if err != nil {
-			logrus.Infof("user auth failed: %v", err)
+			h.logger.WithContext(c.Request.Context()).WithError(err).Info("user authentication failed")
 			unauthorizedError(c, errors.New("unauthorized"))
 			return
 		}
 
+		ctx := logging.WithFields(c.Request.Context(), logrus.Fields{logging.FieldUserID: userID})
+		c.Request = c.Request.WithContext(ctx)
+
+		if !h.userLimiter.Allow(userID) {
+			h.logger.WithContext(ctx).Warn("user rate limit exceeded")
+			rateLimitedError(c, h.userLimiter.RetryAfter())
+			return
+		}
+
 		cb(c, userID)
 	}
 }
+
+// limitByIP rejects the request when the client IP exceeded the rate limit.
+func (h *API) limitByIP(c *gin.Context) {
+	if ip := h.proxies.ClientIP(c.Request.RemoteAddr, c.Request.Header); !h.ipLimiter.Allow(ip) {
+		h.logger.WithContext(c.Request.Context()).WithField("client", ip).Warn("IP rate limit exceeded")
+		rateLimitedError(c, h.ipLimiter.RetryAfter())
+		return
+	}
+
+	c.Next()
+}
+
+// CORS allows cross-origin requests from the provided origins, "*" allows any origin.
+func CORS(origins []string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(origins))
+	for _, o := range origins {
+		allowed[o] = true
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" || (!allowed["*"] && !allowed[origin]) {
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Set("Access-Control-Allow-Credentials", "true")
+		h.Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+		h.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		h.Add("Vary", "Origin")
+
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// MaxBodySize limits the size of request bodies.
+func MaxBodySize(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// Tracing starts a span for every request continuing the trace context of request headers.
+// Websocket connections are long-living and traced per socket event instead.
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.IsWebsocket() {
+			c.Next()
+			return
+		}
+
+		ctx := tracing.ExtractHTTP(c.Request.Context(), c.Request.Header)
+		ctx, span := tracing.Start(ctx, "HTTP "+c.Request.Method+" "+c.FullPath())
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(c.Writer.Status()))
+		span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(c.Writer.Status()))
+	}
+}
+
+// RequestLogger attaches a request ID to the request context and the response, and logs every handled request.
+// The ID of the incoming request header is kept, so requests can be correlated across proxies.
+// The client IP is read from the proxy headers only if the request comes from the trusted proxies.
+func RequestLogger(logger *logrus.Entry, proxies *ratelimit.Proxies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		ctx := logging.WithFields(c.Request.Context(), logrus.Fields{logging.FieldRequestID: requestID})
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		// the context is taken after the handlers, so the fields they added are logged as well
+		logger.WithContext(c.Request.Context()).WithFields(logrus.Fields{
+			"method":   c.Request.Method,
+			"path":     c.Request.URL.Path,
+			"route":    c.FullPath(),
+			"status":   c.Writer.Status(),
+			"duration": time.Since(start).Seconds(),
+			"client":   proxies.ClientIP(c.Request.RemoteAddr, c.Request.Header),
+		}).Info("request handled")
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+
+	return true
+}
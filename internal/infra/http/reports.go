@@ -0,0 +1,107 @@
+package http
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"planningpoker/internal/domain/reports"
+	"planningpoker/internal/domain/rooms"
+)
+
+// ReportsService is a contract to build estimation reports.
+type ReportsService interface {
+	TeamReport(ctx context.Context, teamID, userID string) (*reports.Report, error)
+	RoomReport(ctx context.Context, slug, userID string) (*reports.Report, error)
+}
+
+type sessionReportResponse struct {
+	GameID        string    `json:"game_id"`
+	Name          string    `json:"name"`
+	Rounds        int       `json:"rounds"`
+	Tickets       int       `json:"tickets"`
+	Points        float64   `json:"points"`
+	FirstRevealAt time.Time `json:"first_reveal_at"`
+	LastRevealAt  time.Time `json:"last_reveal_at"`
+}
+
+type playerReportResponse struct {
+	Name          string  `json:"name"`
+	Me            bool    `json:"me"`
+	Rounds        int     `json:"rounds"`
+	AverageSpread float64 `json:"average_spread"`
+	OutlierRate   float64 `json:"outlier_rate"`
+}
+
+type reportResponse struct {
+	Sessions                []sessionReportResponse `json:"sessions"`
+	Tickets                 int                     `json:"tickets"`
+	FirstRoundConsensusRate float64                 `json:"first_round_consensus_rate"`
+	Players                 []playerReportResponse  `json:"players"`
+}
+
+func (h *API) teamReport(c *gin.Context, userID string) {
+	report, err := h.reportsService.TeamReport(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, h.newReportResponse(c.Request.Context(), *report, userID))
+}
+
+func (h *API) roomReport(c *gin.Context, userID string) {
+	slug, err := rooms.NormalizeSlug(c.Param("slug"))
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	report, err := h.reportsService.RoomReport(c.Request.Context(), slug, userID)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, h.newReportResponse(c.Request.Context(), *report, userID))
+}
+
+// newReportResponse creates a report response, players are shown by their names only and rates are rounded.
+func (h *API) newReportResponse(ctx context.Context, report reports.Report, userID string) reportResponse {
+	resp := reportResponse{
+		Sessions:                make([]sessionReportResponse, len(report.Sessions)),
+		Tickets:                 report.Tickets,
+		FirstRoundConsensusRate: roundTo2(report.FirstRoundConsensusRate),
+		Players:                 make([]playerReportResponse, len(report.Players)),
+	}
+
+	for i, s := range report.Sessions {
+		resp.Sessions[i] = sessionReportResponse{
+			GameID:        s.GameID,
+			Name:          s.Name,
+			Rounds:        s.Rounds,
+			Tickets:       s.Tickets,
+			Points:        s.Points,
+			FirstRevealAt: s.FirstRevealAt,
+			LastRevealAt:  s.LastRevealAt,
+		}
+	}
+
+	for i, p := range report.Players {
+		resp.Players[i] = playerReportResponse{
+			Name:          h.userName(ctx, p.UserID),
+			Me:            p.UserID == userID,
+			Rounds:        p.Rounds,
+			AverageSpread: roundTo2(p.AverageSpread),
+			OutlierRate:   roundTo2(p.OutlierRate),
+		}
+	}
+
+	return resp
+}
+
+func roundTo2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
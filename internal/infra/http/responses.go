@@ -1,33 +1,60 @@
 package http
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
-)
 
-type httpErr struct {
-	Error string `json:"error"`
-}
+	"planningpoker/internal/domain"
+	"planningpoker/internal/infra/ratelimit"
+	"planningpoker/internal/infra/transformers"
+)
 
 func success(c *gin.Context, h interface{}) {
 	c.JSON(http.StatusOK, h)
 }
 
 func badRequestError(c *gin.Context, err error) {
-	c.JSON(http.StatusBadRequest, httpErr{
-		Error: err.Error(),
+	c.JSON(http.StatusBadRequest, transformers.ErrorResponse{
+		Code:    transformers.ErrorCodeBadRequest,
+		Message: err.Error(),
 	})
 }
 
-func internalError(c *gin.Context, err error) {
-	c.JSON(http.StatusInternalServerError, httpErr{
-		Error: err.Error(),
-	})
+func rateLimitedError(c *gin.Context, retryAfter time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, transformers.NewErrorResponse(ratelimit.ErrLimited))
 }
 
 func unauthorizedError(c *gin.Context, err error) {
-	c.JSON(http.StatusUnauthorized, httpErr{
-		Error: err.Error(),
+	c.JSON(http.StatusUnauthorized, transformers.ErrorResponse{
+		Code:    transformers.ErrorCodeUnauthorized,
+		Message: err.Error(),
 	})
 }
+
+// domainError responds with a status code matching the domain error kind,
+// errors which are not domain errors are treated as internal ones.
+func domainError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+
+	var de *domain.Error
+	if errors.As(err, &de) {
+		switch de.Kind() {
+		case domain.KindNotFound:
+			status = http.StatusNotFound
+		case domain.KindForbidden:
+			status = http.StatusForbidden
+		case domain.KindConflict:
+			status = http.StatusConflict
+		case domain.KindValidation:
+			status = http.StatusUnprocessableEntity
+		}
+	}
+
+	c.JSON(status, transformers.NewErrorResponse(err))
+}
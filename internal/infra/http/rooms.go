@@ -0,0 +1,186 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"planningpoker/internal/domain/rooms"
+)
+
+// RoomsService is a contract to perform room related actions.
+type RoomsService interface {
+	Create(ctx context.Context, cmd rooms.CreateRoomCommand) (*rooms.Room, error)
+	Get(ctx context.Context, slug string) (*rooms.Room, error)
+	ListByMember(ctx context.Context, userID string) ([]rooms.Room, error)
+	Update(ctx context.Context, cmd rooms.UpdateRoomCommand) error
+	Open(ctx context.Context, cmd rooms.OpenRoomCommand) (string, error)
+}
+
+type roomPayload struct {
+	Slug     string              `json:"slug"`
+	Name     string              `json:"name"`
+	Settings gameSettingsPayload `json:"settings"`
+}
+
+func (pl roomPayload) settings() (*rooms.Settings, error) {
+	deck, err := pl.Settings.deck()
+	if err != nil {
+		return nil, err
+	}
+
+	return &rooms.Settings{CardsDeck: *deck, EveryoneCanReveal: pl.Settings.EveryoneCanReveal}, nil
+}
+
+type roomMemberResponse struct {
+	Name  string `json:"name"`
+	Owner bool   `json:"owner"`
+	Me    bool   `json:"me"`
+}
+
+type roomSessionResponse struct {
+	GameID    string    `json:"game_id"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+type roomResponse struct {
+	Slug          string                `json:"slug"`
+	Name          string                `json:"name"`
+	Owner         bool                  `json:"owner"`
+	Members       []roomMemberResponse  `json:"members"`
+	Settings      gameSettingsResponse  `json:"settings"`
+	CurrentGameID string                `json:"current_game_id"`
+	Sessions      []roomSessionResponse `json:"sessions"`
+}
+
+func (h *API) createRoom(c *gin.Context, userID string) {
+	pl := roomPayload{}
+	if err := c.BindJSON(&pl); err != nil {
+		badRequestError(c, err)
+		return
+	}
+
+	settings, err := pl.settings()
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	cmd, err := rooms.NewCreateRoomCommand(pl.Slug, pl.Name, userID, *settings)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	room, err := h.roomsService.Create(c.Request.Context(), *cmd)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, h.newRoomResponse(c.Request.Context(), *room, userID))
+}
+
+func (h *API) listRooms(c *gin.Context, userID string) {
+	list, err := h.roomsService.ListByMember(c.Request.Context(), userID)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	resp := make([]gin.H, len(list))
+	for i, room := range list {
+		resp[i] = gin.H{
+			"slug":  room.Slug(),
+			"name":  room.Name(),
+			"owner": room.IsOwner(userID),
+		}
+	}
+
+	success(c, gin.H{
+		"rooms": resp,
+	})
+}
+
+func (h *API) getRoom(c *gin.Context, userID string) {
+	room, err := h.roomsService.Get(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, h.newRoomResponse(c.Request.Context(), *room, userID))
+}
+
+func (h *API) updateRoom(c *gin.Context, userID string) {
+	pl := roomPayload{}
+	if err := c.BindJSON(&pl); err != nil {
+		badRequestError(c, err)
+		return
+	}
+
+	settings, err := pl.settings()
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	cmd, err := rooms.NewUpdateRoomCommand(c.Param("slug"), userID, pl.Name, *settings)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	if err := h.roomsService.Update(c.Request.Context(), *cmd); err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, gin.H{})
+}
+
+func (h *API) openRoom(c *gin.Context, userID string) {
+	cmd, err := rooms.NewOpenRoomCommand(c.Param("slug"), userID)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	gameID, err := h.roomsService.Open(c.Request.Context(), *cmd)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, gin.H{
+		"game_id": gameID,
+	})
+}
+
+// newRoomResponse creates a room response, members are shown by their names only.
+func (h *API) newRoomResponse(ctx context.Context, room rooms.Room, userID string) roomResponse {
+	resp := roomResponse{
+		Slug:          room.Slug(),
+		Name:          room.Name(),
+		Owner:         room.IsOwner(userID),
+		Members:       make([]roomMemberResponse, 0, len(room.Members())),
+		Settings:      newGameSettingsResponse(room.Settings().CardsDeck, room.Settings().EveryoneCanReveal),
+		CurrentGameID: room.CurrentGameID(),
+		Sessions:      make([]roomSessionResponse, len(room.Sessions())),
+	}
+
+	for _, uid := range room.MemberIDs() {
+		resp.Members = append(resp.Members, roomMemberResponse{
+			Name:  h.userName(ctx, uid),
+			Owner: room.IsOwner(uid),
+			Me:    uid == userID,
+		})
+	}
+
+	for i, s := range room.Sessions() {
+		resp.Sessions[i] = roomSessionResponse{GameID: s.GameID, StartedAt: s.StartedAt}
+	}
+
+	return resp
+}
@@ -0,0 +1,367 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/teams"
+)
+
+// TeamsService is a contract to perform team related actions.
+type TeamsService interface {
+	Create(ctx context.Context, cmd teams.CreateTeamCommand) (*teams.Team, error)
+	Get(ctx context.Context, teamID, userID string) (*teams.Team, error)
+	ListByMember(ctx context.Context, userID string) ([]teams.Team, error)
+	Update(ctx context.Context, cmd teams.UpdateTeamCommand) error
+	Join(ctx context.Context, cmd teams.JoinTeamCommand) (*teams.Team, error)
+	ChangeRole(ctx context.Context, cmd teams.ChangeRoleCommand) error
+	RemoveMember(ctx context.Context, cmd teams.RemoveMemberCommand) error
+	RotateJoinCode(ctx context.Context, cmd teams.RotateJoinCodeCommand) (string, error)
+	CreateGame(ctx context.Context, cmd teams.CreateGameCommand) (string, error)
+	Games(ctx context.Context, teamID, userID string) (*teams.Games, error)
+}
+
+// gameSettingsPayload are defaults of games created by teams and rooms.
+type gameSettingsPayload struct {
+	CardsDeck struct {
+		Name  string   `json:"name"`
+		Types []string `json:"types"`
+	} `json:"cards_deck"`
+	EveryoneCanReveal bool `json:"everyone_can_reveal"`
+}
+
+func (pl gameSettingsPayload) deck() (*games.CardsDeck, error) {
+	cards := make([]games.Card, len(pl.CardsDeck.Types))
+	for i, v := range pl.CardsDeck.Types {
+		card, err := games.NewCard(v)
+		if err != nil {
+			return nil, err
+		}
+		cards[i] = *card
+	}
+
+	return games.NewCardsDeck(pl.CardsDeck.Name, cards)
+}
+
+func (pl gameSettingsPayload) toTeamSettings() (*teams.Settings, error) {
+	deck, err := pl.deck()
+	if err != nil {
+		return nil, err
+	}
+
+	return &teams.Settings{CardsDeck: *deck, EveryoneCanReveal: pl.EveryoneCanReveal}, nil
+}
+
+type teamPayload struct {
+	Name     string              `json:"name"`
+	Settings gameSettingsPayload `json:"settings"`
+}
+
+type teamMemberResponse struct {
+	Handle string `json:"handle"`
+	Name   string `json:"name"`
+	Role   string `json:"role"`
+	Me     bool   `json:"me"`
+}
+
+type gameSettingsResponse struct {
+	CardsDeck struct {
+		Name  string   `json:"name"`
+		Types []string `json:"types"`
+	} `json:"cards_deck"`
+	EveryoneCanReveal bool `json:"everyone_can_reveal"`
+}
+
+func newGameSettingsResponse(deck games.CardsDeck, everyoneCanReveal bool) gameSettingsResponse {
+	resp := gameSettingsResponse{EveryoneCanReveal: everyoneCanReveal}
+	resp.CardsDeck.Name = deck.Name()
+	resp.CardsDeck.Types = make([]string, 0, len(deck.Cards()))
+	for _, card := range deck.Cards() {
+		resp.CardsDeck.Types = append(resp.CardsDeck.Types, card.Type())
+	}
+
+	return resp
+}
+
+type teamResponse struct {
+	ID       string               `json:"id"`
+	Name     string               `json:"name"`
+	Role     string               `json:"role"`
+	Members  []teamMemberResponse `json:"members"`
+	Settings gameSettingsResponse `json:"settings"`
+	// JoinCode is shown to owners only.
+	JoinCode string `json:"join_code,omitempty"`
+}
+
+type teamGameResponse struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	TicketURL      string    `json:"ticket_url"`
+	State          string    `json:"state"`
+	PlayersCount   int       `json:"players_count"`
+	LastActivityAt time.Time `json:"last_activity_at"`
+}
+
+func (h *API) createTeam(c *gin.Context, userID string) {
+	pl := teamPayload{}
+	if err := c.BindJSON(&pl); err != nil {
+		badRequestError(c, err)
+		return
+	}
+
+	settings, err := pl.Settings.toTeamSettings()
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	cmd, err := teams.NewCreateTeamCommand(pl.Name, userID, *settings)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	team, err := h.teamsService.Create(c.Request.Context(), *cmd)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, h.newTeamResponse(c.Request.Context(), *team, userID))
+}
+
+func (h *API) listTeams(c *gin.Context, userID string) {
+	list, err := h.teamsService.ListByMember(c.Request.Context(), userID)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	resp := make([]gin.H, len(list))
+	for i, team := range list {
+		resp[i] = gin.H{
+			"id":   team.ID(),
+			"name": team.Name(),
+			"role": team.Members()[userID].Role,
+		}
+	}
+
+	success(c, gin.H{
+		"teams": resp,
+	})
+}
+
+func (h *API) getTeam(c *gin.Context, userID string) {
+	team, err := h.teamsService.Get(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, h.newTeamResponse(c.Request.Context(), *team, userID))
+}
+
+func (h *API) updateTeam(c *gin.Context, userID string) {
+	pl := teamPayload{}
+	if err := c.BindJSON(&pl); err != nil {
+		badRequestError(c, err)
+		return
+	}
+
+	settings, err := pl.Settings.toTeamSettings()
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	cmd, err := teams.NewUpdateTeamCommand(c.Param("id"), userID, pl.Name, *settings)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	if err := h.teamsService.Update(c.Request.Context(), *cmd); err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, gin.H{})
+}
+
+func (h *API) joinTeam(c *gin.Context, userID string) {
+	pl := struct {
+		JoinCode string `json:"join_code"`
+	}{}
+	if err := c.BindJSON(&pl); err != nil {
+		badRequestError(c, err)
+		return
+	}
+
+	cmd, err := teams.NewJoinTeamCommand(pl.JoinCode, userID)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	team, err := h.teamsService.Join(c.Request.Context(), *cmd)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, h.newTeamResponse(c.Request.Context(), *team, userID))
+}
+
+func (h *API) changeMemberRole(c *gin.Context, userID string) {
+	pl := struct {
+		Role string `json:"role"`
+	}{}
+	if err := c.BindJSON(&pl); err != nil {
+		badRequestError(c, err)
+		return
+	}
+
+	cmd, err := teams.NewChangeRoleCommand(c.Param("id"), userID, c.Param("handle"), pl.Role)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	if err := h.teamsService.ChangeRole(c.Request.Context(), *cmd); err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, gin.H{})
+}
+
+func (h *API) removeMember(c *gin.Context, userID string) {
+	cmd, err := teams.NewRemoveMemberCommand(c.Param("id"), userID, c.Param("handle"))
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	if err := h.teamsService.RemoveMember(c.Request.Context(), *cmd); err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, gin.H{})
+}
+
+func (h *API) rotateJoinCode(c *gin.Context, userID string) {
+	cmd, err := teams.NewRotateJoinCodeCommand(c.Param("id"), userID)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	code, err := h.teamsService.RotateJoinCode(c.Request.Context(), *cmd)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, gin.H{
+		"join_code": code,
+	})
+}
+
+func (h *API) createTeamGame(c *gin.Context, userID string) {
+	pl := struct {
+		Name      string `json:"name"`
+		TicketURL string `json:"ticket_url"`
+	}{}
+	if err := c.BindJSON(&pl); err != nil {
+		badRequestError(c, err)
+		return
+	}
+
+	cmd, err := teams.NewCreateGameCommand(c.Param("id"), userID, pl.Name, pl.TicketURL)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	gameID, err := h.teamsService.CreateGame(c.Request.Context(), *cmd)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, gin.H{
+		"game_id": gameID,
+	})
+}
+
+func (h *API) teamGames(c *gin.Context, userID string) {
+	list, err := h.teamsService.Games(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		domainError(c, err)
+		return
+	}
+
+	success(c, gin.H{
+		"active": newTeamGamesResponse(list.Active),
+		"past":   newTeamGamesResponse(list.Past),
+	})
+}
+
+// newTeamResponse creates a team response, members are identified by their handles and names.
+func (h *API) newTeamResponse(ctx context.Context, team teams.Team, userID string) teamResponse {
+	resp := teamResponse{
+		ID:       team.ID(),
+		Name:     team.Name(),
+		Role:     team.Members()[userID].Role,
+		Members:  make([]teamMemberResponse, 0, len(team.Members())),
+		Settings: newGameSettingsResponse(team.Settings().CardsDeck, team.Settings().EveryoneCanReveal),
+	}
+	if team.IsOwner(userID) {
+		resp.JoinCode = team.JoinCode()
+	}
+
+	for _, uid := range team.MemberIDs() {
+		m := team.Members()[uid]
+		resp.Members = append(resp.Members, teamMemberResponse{
+			Handle: m.Handle,
+			Name:   h.userName(ctx, uid),
+			Role:   m.Role,
+			Me:     uid == userID,
+		})
+	}
+
+	return resp
+}
+
+// userName returns the user name for responses, a placeholder is returned if the user is unknown.
+func (h *API) userName(ctx context.Context, uid string) string {
+	user, err := h.usersService.Get(ctx, uid)
+	if err != nil {
+		h.logger.WithContext(ctx).WithError(err).Warn("unable to fetch the user name")
+	}
+	if user == nil {
+		return "Unknown"
+	}
+
+	return user.Name()
+}
+
+func newTeamGamesResponse(list []games.Game) []teamGameResponse {
+	resp := make([]teamGameResponse, len(list))
+	for i, g := range list {
+		resp[i] = teamGameResponse{
+			ID:             g.ID(),
+			Name:           g.Name(),
+			TicketURL:      g.TicketURL(),
+			State:          g.State(),
+			PlayersCount:   len(g.Players()),
+			LastActivityAt: g.LastActivityAt(),
+		}
+	}
+
+	return resp
+}
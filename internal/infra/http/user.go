@@ -1,8 +1,6 @@
 package http
 
 import (
-	"errors"
-
 	"planningpoker/internal/domain/users"
 
 	"github.com/gin-gonic/gin"
@@ -20,30 +18,31 @@ func (h *API) register(c *gin.Context) {
 
 	cmd, err := users.NewRegisterCommand(pl.Name)
 	if err != nil {
-		badRequestError(c, err)
+		domainError(c, err)
 		return
 	}
 
-	user, err := h.usersService.Register(*cmd)
+	user, err := h.usersService.Register(c.Request.Context(), *cmd)
 	if err != nil {
-		badRequestError(c, err)
+		domainError(c, err)
 		return
 	}
 
 	success(c, gin.H{
 		"user_id": user.ID(),
+		"token":   h.authenticator.IssueToken(user.ID()),
 	})
 }
 
 func (h *API) currentUser(c *gin.Context, userID string) {
-	user, err := h.usersService.Get(userID)
-	if err != nil || user == nil {
-		badRequestError(c, err)
+	user, err := h.usersService.Get(c.Request.Context(), userID)
+	if err != nil {
+		domainError(c, err)
 		return
 	}
 
 	if user == nil {
-		badRequestError(c, errors.New("user not found"))
+		domainError(c, users.ErrUserNotFound)
 		return
 	}
 
@@ -63,13 +62,13 @@ func (h *API) changeUserData(c *gin.Context, userID string) {
 
 	cmd, err := users.NewUpdateCommand(userID, pl.Name)
 	if err != nil {
-		badRequestError(c, err)
+		domainError(c, err)
 		return
 	}
 
-	_, err = h.usersService.Update(*cmd)
+	_, err = h.usersService.Update(c.Request.Context(), *cmd)
 	if err != nil {
-		internalError(c, err)
+		domainError(c, err)
 		return
 	}
 
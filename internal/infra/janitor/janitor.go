@@ -0,0 +1,60 @@
+// Package janitor contains a background cleanup of abandoned games and users.
+package janitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"planningpoker/internal/infra/periodic"
+)
+
+type gamesService interface {
+	CleanupIdle(ctx context.Context, idleFor time.Duration) error
+}
+
+type usersService interface {
+	DeleteNotSeen(ctx context.Context, notSeenFor time.Duration) error
+}
+
+// Janitor periodically archives idle games and deletes users who never came back.
+type Janitor struct {
+	*periodic.Runner
+	gamesService gamesService
+	usersService usersService
+	gameTTL      time.Duration
+	userTTL      time.Duration
+	logger       *logrus.Entry
+}
+
+// NewJanitor creates a new janitor instance, zero TTL disables the corresponding cleanup.
+func NewJanitor(gs gamesService, us usersService, interval, gameTTL, userTTL time.Duration, logger *logrus.Entry) *Janitor {
+	j := &Janitor{
+		gamesService: gs,
+		usersService: us,
+		gameTTL:      gameTTL,
+		userTTL:      userTTL,
+		logger:       logger,
+	}
+	j.Runner = periodic.NewRunner(interval, false, func(time.Time) { j.Cleanup() })
+
+	return j
+}
+
+// Cleanup performs one cleanup round.
+func (j *Janitor) Cleanup() {
+	ctx := context.Background()
+
+	if j.gameTTL > 0 {
+		if err := j.gamesService.CleanupIdle(ctx, j.gameTTL); err != nil {
+			j.logger.WithError(err).Error("idle games cleanup failed")
+		}
+	}
+
+	if j.userTTL > 0 {
+		if err := j.usersService.DeleteNotSeen(ctx, j.userTTL); err != nil {
+			j.logger.WithError(err).Error("not seen users cleanup failed")
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Package jsonpatch contains a minimal RFC 6902 JSON patch generator.
+package jsonpatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// OpAdd adds a value to an object or inserts it into an array.
+	OpAdd = "add"
+	// OpRemove removes a value from an object or array.
+	OpRemove = "remove"
+	// OpReplace replaces a value.
+	OpReplace = "replace"
+)
+
+// Operation is a single JSON patch operation.
+type Operation struct {
+	Op    string
+	Path  string
+	Value interface{}
+}
+
+// MarshalJSON encodes the operation, omitting the value for remove operations only,
+// because null is a legitimate value for add and replace.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	if o.Op == OpRemove {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+
+	return json.Marshal(struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}{o.Op, o.Path, o.Value})
+}
+
+// ToTree converts any JSON serializable value into its generic representation
+// (maps, slices and primitives), which is the form Diff works with.
+func ToTree(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree interface{}
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return nil, err
+	}
+
+	return tree, nil
+}
+
+// Diff returns the list of operations which transforms the "from" tree into the "to" tree.
+// Both values are expected to be produced by ToTree.
+func Diff(from, to interface{}) []Operation {
+	return diff("", from, to, nil)
+}
+
+func diff(path string, from, to interface{}, ops []Operation) []Operation {
+	switch f := from.(type) {
+	case map[string]interface{}:
+		if t, ok := to.(map[string]interface{}); ok {
+			return diffObjects(path, f, t, ops)
+		}
+	case []interface{}:
+		if t, ok := to.([]interface{}); ok {
+			return diffArrays(path, f, t, ops)
+		}
+	}
+
+	if reflect.DeepEqual(from, to) {
+		return ops
+	}
+
+	return append(ops, Operation{Op: OpReplace, Path: path, Value: to})
+}
+
+func diffObjects(path string, from, to map[string]interface{}, ops []Operation) []Operation {
+	for _, k := range sortedKeys(from) {
+		if _, ok := to[k]; !ok {
+			ops = append(ops, Operation{Op: OpRemove, Path: path + "/" + escape(k)})
+		}
+	}
+
+	for _, k := range sortedKeys(to) {
+		fv, ok := from[k]
+		if !ok {
+			ops = append(ops, Operation{Op: OpAdd, Path: path + "/" + escape(k), Value: to[k]})
+			continue
+		}
+		ops = diff(path+"/"+escape(k), fv, to[k], ops)
+	}
+
+	return ops
+}
+
+func diffArrays(path string, from, to []interface{}, ops []Operation) []Operation {
+	common := len(from)
+	if len(to) < common {
+		common = len(to)
+	}
+
+	for i := 0; i < common; i++ {
+		ops = diff(path+"/"+strconv.Itoa(i), from[i], to[i], ops)
+	}
+
+	// remove from the tail, so indexes of the remaining elements are not shifted
+	for i := len(from) - 1; i >= common; i-- {
+		ops = append(ops, Operation{Op: OpRemove, Path: path + "/" + strconv.Itoa(i)})
+	}
+
+	for i := common; i < len(to); i++ {
+		ops = append(ops, Operation{Op: OpAdd, Path: path + "/" + strconv.Itoa(i), Value: to[i]})
+	}
+
+	return ops
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func escape(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,87 @@
+package jsonpatch_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"planningpoker/internal/infra/jsonpatch"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		from   string
+		to     string
+		expOps string
+	}{
+		"no changes": {
+			from:   `{"name":"foo","players":[{"name":"a"}]}`,
+			to:     `{"name":"foo","players":[{"name":"a"}]}`,
+			expOps: `null`,
+		},
+		"replace value": {
+			from:   `{"name":"foo","state":"started"}`,
+			to:     `{"name":"foo","state":"finished"}`,
+			expOps: `[{"op":"replace","path":"/state","value":"finished"}]`,
+		},
+		"add and remove keys": {
+			from:   `{"a":1,"b":2}`,
+			to:     `{"b":2,"c":null}`,
+			expOps: `[{"op":"remove","path":"/a"},{"op":"add","path":"/c","value":null}]`,
+		},
+		"nested array element": {
+			from:   `{"players":[{"name":"a","voted_card":""},{"name":"b","voted_card":""}]}`,
+			to:     `{"players":[{"name":"a","voted_card":""},{"name":"b","voted_card":"*"}]}`,
+			expOps: `[{"op":"replace","path":"/players/1/voted_card","value":"*"}]`,
+		},
+		"array grows": {
+			from:   `{"players":["a"]}`,
+			to:     `{"players":["a","b","c"]}`,
+			expOps: `[{"op":"add","path":"/players/1","value":"b"},{"op":"add","path":"/players/2","value":"c"}]`,
+		},
+		"array shrinks from the tail": {
+			from:   `{"players":["a","b","c"]}`,
+			to:     `{"players":["a"]}`,
+			expOps: `[{"op":"remove","path":"/players/2"},{"op":"remove","path":"/players/1"}]`,
+		},
+		"type change": {
+			from:   `{"players":null}`,
+			to:     `{"players":["a"]}`,
+			expOps: `[{"op":"replace","path":"/players","value":["a"]}]`,
+		},
+		"escaped keys": {
+			from:   `{"a/b":1,"c~d":1}`,
+			to:     `{"a/b":2,"c~d":2}`,
+			expOps: `[{"op":"replace","path":"/a~1b","value":2},{"op":"replace","path":"/c~0d","value":2}]`,
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var from, to interface{}
+			require.NoError(t, json.Unmarshal([]byte(tt.from), &from))
+			require.NoError(t, json.Unmarshal([]byte(tt.to), &to))
+
+			ops, err := json.Marshal(jsonpatch.Diff(from, to))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expOps, string(ops))
+		})
+	}
+}
+
+func TestToTree(t *testing.T) {
+	t.Parallel()
+
+	tree, err := jsonpatch.ToTree(struct {
+		Name string `json:"name"`
+	}{Name: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "foo"}, tree)
+}
@@ -0,0 +1,138 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"planningpoker/internal/domain/games"
+)
+
+// GamesService is a games service decorator attaching the game and the user to the command path and logging results.
+type GamesService struct {
+	next   games.Commander
+	logger *logrus.Entry
+}
+
+// NewGamesService wraps the games service with logging.
+func NewGamesService(next games.Commander, logger *logrus.Entry) *GamesService {
+	return &GamesService{
+		next:   next,
+		logger: logger,
+	}
+}
+
+// Create creates a game.
+func (s *GamesService) Create(ctx context.Context, cmd games.CreateGameCommand) (string, error) {
+	var id string
+	err := s.run(ctx, "create", "", cmd.UserID, func(ctx context.Context) (err error) {
+		id, err = s.next.Create(ctx, cmd)
+		return err
+	})
+
+	return id, err
+}
+
+// Join adds a player to the game.
+func (s *GamesService) Join(ctx context.Context, cmd games.JoinGameCommand) error {
+	return s.run(ctx, "join", cmd.GameID, cmd.UserID, func(ctx context.Context) error {
+		return s.next.Join(ctx, cmd)
+	})
+}
+
+// Leave removes a player from the game.
+func (s *GamesService) Leave(ctx context.Context, cmd games.LeaveGameCommand) error {
+	return s.run(ctx, "leave", cmd.GameID, cmd.UserID, func(ctx context.Context) error {
+		return s.next.Leave(ctx, cmd)
+	})
+}
+
+// Update updates a game.
+func (s *GamesService) Update(ctx context.Context, cmd games.UpdateGameCommand) error {
+	return s.run(ctx, "update", cmd.GameID, cmd.UserID, func(ctx context.Context) error {
+		return s.next.Update(ctx, cmd)
+	})
+}
+
+// Vote votes for a card.
+func (s *GamesService) Vote(ctx context.Context, cmd games.VoteCommand) error {
+	return s.run(ctx, "vote", cmd.GameID, cmd.UserID, func(ctx context.Context) error {
+		return s.next.Vote(ctx, cmd)
+	})
+}
+
+// UnVote removes a vote.
+func (s *GamesService) UnVote(ctx context.Context, cmd games.UnVoteCommand) error {
+	return s.run(ctx, "unvote", cmd.GameID, cmd.UserID, func(ctx context.Context) error {
+		return s.next.UnVote(ctx, cmd)
+	})
+}
+
+// Reveal reveals the cards.
+func (s *GamesService) Reveal(ctx context.Context, cmd games.RevealCardsCommand) error {
+	return s.run(ctx, "reveal", cmd.GameID, cmd.UserID, func(ctx context.Context) error {
+		return s.next.Reveal(ctx, cmd)
+	})
+}
+
+// Restart restarts the game.
+func (s *GamesService) Restart(ctx context.Context, cmd games.RestartGameCommand) error {
+	return s.run(ctx, "restart", cmd.GameID, cmd.UserID, func(ctx context.Context) error {
+		return s.next.Restart(ctx, cmd)
+	})
+}
+
+// Revote starts voting on the same ticket again.
+func (s *GamesService) Revote(ctx context.Context, cmd games.RevoteCommand) error {
+	return s.run(ctx, "revote", cmd.GameID, cmd.UserID, func(ctx context.Context) error {
+		return s.next.Revote(ctx, cmd)
+	})
+}
+
+// Rearrange changes the seats order.
+func (s *GamesService) Rearrange(ctx context.Context, cmd games.RearrangeSeatsCommand) error {
+	return s.run(ctx, "rearrange", cmd.GameID, cmd.UserID, func(ctx context.Context) error {
+		return s.next.Rearrange(ctx, cmd)
+	})
+}
+
+// ChangePasscode rotates the join passcode.
+func (s *GamesService) ChangePasscode(ctx context.Context, cmd games.ChangePasscodeCommand) error {
+	return s.run(ctx, "change_passcode", cmd.GameID, cmd.UserID, func(ctx context.Context) error {
+		return s.next.ChangePasscode(ctx, cmd)
+	})
+}
+
+// Spectate checks the user can watch the game.
+func (s *GamesService) Spectate(ctx context.Context, cmd games.JoinGameCommand) error {
+	return s.run(ctx, "spectate", cmd.GameID, cmd.UserID, func(ctx context.Context) error {
+		return s.next.Spectate(ctx, cmd)
+	})
+}
+
+// Chat sends a chat message or a reaction.
+func (s *GamesService) Chat(ctx context.Context, cmd games.ChatCommand) (*games.ChatMessage, error) {
+	var msg *games.ChatMessage
+	err := s.run(ctx, "chat", cmd.GameID, cmd.UserID, func(ctx context.Context) (err error) {
+		msg, err = s.next.Chat(ctx, cmd)
+		return err
+	})
+
+	return msg, err
+}
+
+// run attaches the game and the user to ctx, so they are logged by every next layer, and logs the command result.
+func (s *GamesService) run(ctx context.Context, command, gameID, userID string, cb func(context.Context) error) error {
+	ctx = WithFields(ctx, logrus.Fields{FieldGameID: gameID, FieldUserID: userID})
+
+	err := cb(ctx)
+
+	entry := s.logger.WithContext(ctx).WithField("command", command)
+	if err != nil {
+		entry.WithError(err).Warn("command failed")
+		return err
+	}
+	entry.Debug("command handled")
+
+	return nil
+}
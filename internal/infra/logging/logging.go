@@ -0,0 +1,125 @@
+// Package logging contains the structured logger setup and correlation fields propagation.
+package logging
+
+import (
+	"context"
+	"io"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+
+	"planningpoker/internal/domain"
+)
+
+const (
+	// FieldRequestID is an HTTP request ID field.
+	FieldRequestID = "request_id"
+	// FieldConnID is a socket connection ID field.
+	FieldConnID = "conn_id"
+	// FieldGameID is a game ID field.
+	FieldGameID = domain.LogFieldGameID
+	// FieldUserID is a user ID field.
+	FieldUserID = domain.LogFieldUserID
+	// FieldTeamID is a team ID field.
+	FieldTeamID = domain.LogFieldTeamID
+	// FieldRoomID is a room ID field.
+	FieldRoomID = domain.LogFieldRoomID
+	// FieldBatchID is a voting batch ID field.
+	FieldBatchID = domain.LogFieldBatchID
+	// FieldTraceID is a trace ID field, it links log entries with traces.
+	FieldTraceID = "trace_id"
+
+	// metadataPrefix prefixes correlation fields stored in domain event metadata.
+	metadataPrefix = "log."
+)
+
+// correlated are the fields which are propagated through domain events.
+var correlated = []string{FieldRequestID, FieldConnID, FieldGameID, FieldUserID}
+
+type fieldsKey struct{}
+
+// New creates a logger writing to w, JSON format is intended for log collectors.
+// Entries logged with a context get its correlation fields.
+func New(w io.Writer, level logrus.Level, json bool) *logrus.Entry {
+	l := logrus.New()
+	l.SetOutput(w)
+	l.SetLevel(level)
+	if json {
+		l.SetFormatter(&logrus.JSONFormatter{})
+	}
+	l.AddHook(contextHook{})
+
+	return logrus.NewEntry(l)
+}
+
+// WithFields returns a copy of ctx with additional correlation fields, empty values are skipped.
+func WithFields(ctx context.Context, fields logrus.Fields) context.Context {
+	merged := logrus.Fields{}
+	for k, v := range Fields(ctx) {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		if v != "" {
+			merged[k] = v
+		}
+	}
+
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// Fields returns correlation fields of ctx.
+func Fields(ctx context.Context) logrus.Fields {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).(logrus.Fields)
+
+	return fields
+}
+
+// Inject writes correlation fields of ctx into the carrier, e.g. domain event metadata.
+func Inject(ctx context.Context, carrier map[string]string) {
+	fields := Fields(ctx)
+	for _, k := range correlated {
+		if v, ok := fields[k].(string); ok {
+			carrier[metadataPrefix+k] = v
+		}
+	}
+}
+
+// Extract returns a copy of ctx with correlation fields stored in the carrier.
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	fields := logrus.Fields{}
+	for _, k := range correlated {
+		if v, ok := carrier[metadataPrefix+k]; ok {
+			fields[k] = v
+		}
+	}
+
+	return WithFields(ctx, fields)
+}
+
+// contextHook adds correlation fields and the trace ID of the entry context, explicit entry fields take precedence.
+type contextHook struct{}
+
+func (contextHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (contextHook) Fire(e *logrus.Entry) error {
+	if e.Context == nil {
+		return nil
+	}
+
+	for k, v := range Fields(e.Context) {
+		if _, ok := e.Data[k]; !ok {
+			e.Data[k] = v
+		}
+	}
+
+	if sc := trace.SpanContextFromContext(e.Context); sc.IsValid() {
+		e.Data[FieldTraceID] = sc.TraceID().String()
+	}
+
+	return nil
+}
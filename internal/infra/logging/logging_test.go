@@ -0,0 +1,151 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/infra/logging"
+)
+
+func TestLogger_ContextFields(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	logger := logging.New(out, logrus.InfoLevel, true)
+
+	ctx := logging.WithFields(context.Background(), logrus.Fields{logging.FieldRequestID: "req-1", logging.FieldGameID: ""})
+	ctx = logging.WithFields(ctx, logrus.Fields{logging.FieldUserID: "user-1"})
+	logger.WithContext(ctx).WithField(logging.FieldUserID, "explicit").Info("message")
+
+	entry := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "message", entry["msg"])
+	assert.Equal(t, "req-1", entry[logging.FieldRequestID])
+	assert.Equal(t, "explicit", entry[logging.FieldUserID])
+	assert.NotContains(t, entry, logging.FieldGameID)
+}
+
+func TestInjectExtract(t *testing.T) {
+	t.Parallel()
+
+	ctx := logging.WithFields(context.Background(), logrus.Fields{
+		logging.FieldRequestID: "req-1",
+		logging.FieldGameID:    "game-1",
+		"unrelated":            "value",
+	})
+
+	md := map[string]string{"traceparent": "trace"}
+	logging.Inject(ctx, md)
+	assert.Equal(t, map[string]string{
+		"traceparent":    "trace",
+		"log.request_id": "req-1",
+		"log.game_id":    "game-1",
+	}, md)
+
+	got := logging.Fields(logging.Extract(context.Background(), md))
+	assert.Equal(t, logrus.Fields{logging.FieldRequestID: "req-1", logging.FieldGameID: "game-1"}, got)
+}
+
+func TestGamesService_AttachesFields(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	next := &gamesServiceStub{err: errors.New("failed")}
+	s := logging.NewGamesService(next, logging.New(out, logrus.DebugLevel, true))
+
+	ctx := logging.WithFields(context.Background(), logrus.Fields{logging.FieldConnID: "conn-1"})
+	assert.Error(t, s.Vote(ctx, games.VoteCommand{GameID: "game-1", UserID: "user-1"}))
+
+	assert.Equal(t, logrus.Fields{
+		logging.FieldConnID: "conn-1",
+		logging.FieldGameID: "game-1",
+		logging.FieldUserID: "user-1",
+	}, logging.Fields(next.ctx))
+
+	entry := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "command failed", entry["msg"])
+	assert.Equal(t, "vote", entry["command"])
+	assert.Equal(t, "failed", entry["error"])
+	assert.Equal(t, "conn-1", entry[logging.FieldConnID])
+	assert.Equal(t, "game-1", entry[logging.FieldGameID])
+	assert.Equal(t, "user-1", entry[logging.FieldUserID])
+}
+
+type gamesServiceStub struct {
+	ctx context.Context
+	err error
+}
+
+func (s *gamesServiceStub) Create(ctx context.Context, _ games.CreateGameCommand) (string, error) {
+	s.ctx = ctx
+	return "id", s.err
+}
+
+func (s *gamesServiceStub) Join(ctx context.Context, _ games.JoinGameCommand) error {
+	s.ctx = ctx
+	return s.err
+}
+
+func (s *gamesServiceStub) Leave(ctx context.Context, _ games.LeaveGameCommand) error {
+	s.ctx = ctx
+	return s.err
+}
+
+func (s *gamesServiceStub) Update(ctx context.Context, _ games.UpdateGameCommand) error {
+	s.ctx = ctx
+	return s.err
+}
+
+func (s *gamesServiceStub) Vote(ctx context.Context, _ games.VoteCommand) error {
+	s.ctx = ctx
+	return s.err
+}
+
+func (s *gamesServiceStub) UnVote(ctx context.Context, _ games.UnVoteCommand) error {
+	s.ctx = ctx
+	return s.err
+}
+
+func (s *gamesServiceStub) Reveal(ctx context.Context, _ games.RevealCardsCommand) error {
+	s.ctx = ctx
+	return s.err
+}
+
+func (s *gamesServiceStub) Restart(ctx context.Context, _ games.RestartGameCommand) error {
+	s.ctx = ctx
+	return s.err
+}
+
+func (s *gamesServiceStub) Revote(ctx context.Context, _ games.RevoteCommand) error {
+	s.ctx = ctx
+	return s.err
+}
+
+func (s *gamesServiceStub) Rearrange(ctx context.Context, _ games.RearrangeSeatsCommand) error {
+	s.ctx = ctx
+	return s.err
+}
+
+func (s *gamesServiceStub) ChangePasscode(ctx context.Context, _ games.ChangePasscodeCommand) error {
+	s.ctx = ctx
+	return s.err
+}
+
+func (s *gamesServiceStub) Spectate(ctx context.Context, _ games.JoinGameCommand) error {
+	s.ctx = ctx
+	return s.err
+}
+
+func (s *gamesServiceStub) Chat(ctx context.Context, _ games.ChatCommand) (*games.ChatMessage, error) {
+	s.ctx = ctx
+	return &games.ChatMessage{}, s.err
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"planningpoker/internal/domain/events"
+)
+
+// EventBus is an event bus decorator measuring publishing and consuming latency.
+type EventBus struct {
+	next    events.EventBus
+	metrics *Metrics
+}
+
+// NewEventBus wraps the event bus with latency measuring.
+func NewEventBus(next events.EventBus, m *Metrics) *EventBus {
+	return &EventBus{
+		next:    next,
+		metrics: m,
+	}
+}
+
+// Publish publishes the provided event.
+func (b *EventBus) Publish(ctx context.Context, event events.DomainEvent) error {
+	start := time.Now()
+	err := b.next.Publish(ctx, event)
+	b.metrics.publishDuration.WithLabelValues(event.EventType()).Observe(time.Since(start).Seconds())
+
+	return err
+}
+
+// Subscribe subscribes the consumer, each event consuming is measured.
+func (b *EventBus) Subscribe(consumer events.Consumer, eventTypes ...string) {
+	b.next.Subscribe(func(ctx context.Context, event events.DomainEvent) {
+		start := time.Now()
+		consumer(ctx, event)
+		b.metrics.dispatchDuration.WithLabelValues(event.EventType()).Observe(time.Since(start).Seconds())
+	}, eventTypes...)
+}
@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"planningpoker/internal/domain/games"
+)
+
+const (
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+// GamesService is a games service decorator counting processed commands.
+type GamesService struct {
+	next    games.Commander
+	metrics *Metrics
+}
+
+// NewGamesService wraps the games service with commands counting.
+func NewGamesService(next games.Commander, m *Metrics) *GamesService {
+	return &GamesService{
+		next:    next,
+		metrics: m,
+	}
+}
+
+// Create creates a game.
+func (s *GamesService) Create(ctx context.Context, cmd games.CreateGameCommand) (string, error) {
+	id, err := s.next.Create(ctx, cmd)
+	s.count("create", err)
+	return id, err
+}
+
+// Join adds a player to the game.
+func (s *GamesService) Join(ctx context.Context, cmd games.JoinGameCommand) error {
+	return s.count("join", s.next.Join(ctx, cmd))
+}
+
+// Leave removes a player from the game.
+func (s *GamesService) Leave(ctx context.Context, cmd games.LeaveGameCommand) error {
+	return s.count("leave", s.next.Leave(ctx, cmd))
+}
+
+// Update updates a game.
+func (s *GamesService) Update(ctx context.Context, cmd games.UpdateGameCommand) error {
+	return s.count("update", s.next.Update(ctx, cmd))
+}
+
+// Vote votes for a card.
+func (s *GamesService) Vote(ctx context.Context, cmd games.VoteCommand) error {
+	return s.count("vote", s.next.Vote(ctx, cmd))
+}
+
+// UnVote removes a vote.
+func (s *GamesService) UnVote(ctx context.Context, cmd games.UnVoteCommand) error {
+	return s.count("unvote", s.next.UnVote(ctx, cmd))
+}
+
+// Reveal reveals the cards.
+func (s *GamesService) Reveal(ctx context.Context, cmd games.RevealCardsCommand) error {
+	return s.count("reveal", s.next.Reveal(ctx, cmd))
+}
+
+// Restart restarts the game.
+func (s *GamesService) Restart(ctx context.Context, cmd games.RestartGameCommand) error {
+	return s.count("restart", s.next.Restart(ctx, cmd))
+}
+
+// Revote starts voting on the same ticket again.
+func (s *GamesService) Revote(ctx context.Context, cmd games.RevoteCommand) error {
+	return s.count("revote", s.next.Revote(ctx, cmd))
+}
+
+// Rearrange changes the seats order.
+func (s *GamesService) Rearrange(ctx context.Context, cmd games.RearrangeSeatsCommand) error {
+	return s.count("rearrange", s.next.Rearrange(ctx, cmd))
+}
+
+// ChangePasscode rotates the join passcode.
+func (s *GamesService) ChangePasscode(ctx context.Context, cmd games.ChangePasscodeCommand) error {
+	return s.count("change_passcode", s.next.ChangePasscode(ctx, cmd))
+}
+
+// Spectate checks the user can watch the game.
+func (s *GamesService) Spectate(ctx context.Context, cmd games.JoinGameCommand) error {
+	return s.count("spectate", s.next.Spectate(ctx, cmd))
+}
+
+// Chat sends a chat message or a reaction.
+func (s *GamesService) Chat(ctx context.Context, cmd games.ChatCommand) (*games.ChatMessage, error) {
+	msg, err := s.next.Chat(ctx, cmd)
+	s.count("chat", err)
+	return msg, err
+}
+
+func (s *GamesService) count(command string, err error) error {
+	result := resultSuccess
+	if err != nil {
+		result = resultFailure
+	}
+	s.metrics.commands.WithLabelValues(command, result).Inc()
+
+	return err
+}
+
+// gamesStats is a contract to fetch the current games statistics.
+type gamesStats interface {
+	// GetActiveGamesPlayersCount returns the number of players of every running game.
+	GetActiveGamesPlayersCount(ctx context.Context) ([]int, error)
+}
+
+// gamesCollector collects games gauges on scrape, so they are never stale.
+type gamesCollector struct {
+	stats   gamesStats
+	active  *prometheus.Desc
+	players *prometheus.Desc
+}
+
+// playersBuckets are upper bounds of the players per game histogram.
+var playersBuckets = []float64{1, 2, 3, 5, 8, 13, 21}
+
+// WatchGames exposes the number of active games and players per game.
+func (m *Metrics) WatchGames(stats gamesStats) {
+	m.registry.MustRegister(&gamesCollector{
+		stats: stats,
+		active: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "active_games"),
+			"Number of running games.", nil, nil,
+		),
+		players: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "game_players"),
+			"Number of players per running game.", nil, nil,
+		),
+	})
+}
+
+// Describe implements prometheus.Collector.
+func (c *gamesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.active
+	ch <- c.players
+}
+
+// Collect implements prometheus.Collector.
+func (c *gamesCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.stats.GetActiveGamesPlayersCount(context.Background())
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.active, err)
+		return
+	}
+
+	buckets := make(map[float64]uint64, len(playersBuckets))
+	for _, b := range playersBuckets {
+		buckets[b] = 0
+	}
+	sum := 0
+	for _, n := range counts {
+		sum += n
+		for _, b := range playersBuckets {
+			if float64(n) <= b {
+				buckets[b]++
+			}
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.active, prometheus.GaugeValue, float64(len(counts)))
+	ch <- prometheus.MustNewConstHistogram(c.players, uint64(len(counts)), float64(sum), buckets)
+}
+
+// socketsCounter is a contract to fetch the number of connected sockets.
+type socketsCounter interface {
+	ConnectionsCount() int
+}
+
+// WatchSockets exposes the number of connected sockets.
+func (m *Metrics) WatchSockets(counter socketsCounter) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "connected_sockets",
+		Help:      "Number of connected sockets.",
+	}, func() float64 {
+		return float64(counter.ConnectionsCount())
+	}))
+}
+
+// ObserveLockWait records the time spent waiting for the exclusive game modification lock.
+func (m *Metrics) ObserveLockWait(wait time.Duration) {
+	m.lockWait.Observe(wait.Seconds())
+}
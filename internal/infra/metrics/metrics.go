@@ -0,0 +1,79 @@
+// Package metrics contains prometheus instrumentation of the service.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "poker"
+
+// latencyBuckets are finer than the default ones, since in-process operations are expected to be fast.
+var latencyBuckets = []float64{.0001, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1}
+
+// Metrics keeps all the service collectors in its own registry.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpDuration     *prometheus.HistogramVec
+	commands         *prometheus.CounterVec
+	publishDuration  *prometheus.HistogramVec
+	dispatchDuration *prometheus.HistogramVec
+	lockWait         prometheus.Histogram
+}
+
+// NewMetrics creates and registers all the service collectors.
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP requests duration by route and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		commands: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "commands_total",
+			Help:      "Game commands by type and result.",
+		}, []string{"command", "result"}),
+		publishDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "eventbus_publish_duration_seconds",
+			Help:      "Domain events publishing duration by event type.",
+			Buckets:   latencyBuckets,
+		}, []string{"event"}),
+		dispatchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "eventbus_dispatch_duration_seconds",
+			Help:      "Domain events consuming duration by event type.",
+			Buckets:   latencyBuckets,
+		}, []string{"event"}),
+		lockWait: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "games_lock_wait_seconds",
+			Help:      "Time spent waiting for the exclusive game modification lock.",
+			Buckets:   latencyBuckets,
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpDuration,
+		m.commands,
+		m.publishDuration,
+		m.dispatchDuration,
+		m.lockWait,
+	)
+
+	return m
+}
+
+// Handler returns an HTTP handler exposing the metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
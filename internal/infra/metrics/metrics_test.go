@@ -0,0 +1,170 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/infra/metrics"
+	"planningpoker/test"
+)
+
+func TestGamesService_CountsCommands(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.NewMetrics()
+	s := metrics.NewGamesService(&gamesServiceStub{err: errors.New("failed")}, m)
+
+	assert.Error(t, s.Vote(context.Background(), games.VoteCommand{}))
+	assert.Error(t, s.Vote(context.Background(), games.VoteCommand{}))
+
+	s = metrics.NewGamesService(&gamesServiceStub{}, m)
+	assert.NoError(t, s.Vote(context.Background(), games.VoteCommand{}))
+	_, err := s.Create(context.Background(), games.CreateGameCommand{})
+	assert.NoError(t, err)
+
+	out := scrape(t, m)
+	assert.Contains(t, out, `poker_commands_total{command="vote",result="failure"} 2`)
+	assert.Contains(t, out, `poker_commands_total{command="vote",result="success"} 1`)
+	assert.Contains(t, out, `poker_commands_total{command="create",result="success"} 1`)
+}
+
+func TestMetrics_HTTPMiddleware(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.NewMetrics()
+	r := gin.New()
+	r.Use(m.HTTPMiddleware())
+	r.GET("/api/v1/games/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	for _, url := range []string{"/api/v1/games/1", "/api/v1/games/2", "/unknown"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, url, nil))
+	}
+
+	out := scrape(t, m)
+	assert.Contains(t, out, `poker_http_request_duration_seconds_count{method="GET",route="/api/v1/games/:id",status="204"} 2`)
+	assert.Contains(t, out, `poker_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`)
+}
+
+func TestMetrics_WatchGamesAndSockets(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.NewMetrics()
+	m.WatchGames(gamesStatsStub{1, 3, 3})
+	m.WatchSockets(socketsCounterStub(5))
+	m.ObserveLockWait(0)
+
+	out := scrape(t, m)
+	assert.Contains(t, out, "poker_active_games 3")
+	assert.Contains(t, out, `poker_game_players_bucket{le="2"} 1`)
+	assert.Contains(t, out, `poker_game_players_bucket{le="3"} 3`)
+	assert.Contains(t, out, "poker_game_players_sum 7")
+	assert.Contains(t, out, "poker_connected_sockets 5")
+	assert.Contains(t, out, "poker_games_lock_wait_seconds_count 1")
+}
+
+func TestEventBus_MeasuresLatency(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.NewMetrics()
+	recorder := &test.EventsRecorder{}
+	bus := metrics.NewEventBus(recorder, m)
+
+	require.NoError(t, bus.Publish(context.Background(), events.NewDomainEventBuilder(events.EventTypeGameUpdated).Build()))
+	assert.Len(t, recorder.Events(), 1)
+
+	out := scrape(t, m)
+	assert.Contains(t, out, `poker_eventbus_publish_duration_seconds_count{event="game:updated"} 1`)
+}
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+type gamesStatsStub []int
+
+func (s gamesStatsStub) GetActiveGamesPlayersCount(context.Context) ([]int, error) {
+	return s, nil
+}
+
+type socketsCounterStub int
+
+func (s socketsCounterStub) ConnectionsCount() int {
+	return int(s)
+}
+
+type gamesServiceStub struct {
+	err error
+}
+
+func (s *gamesServiceStub) Create(context.Context, games.CreateGameCommand) (string, error) {
+	return "id", s.err
+}
+
+func (s *gamesServiceStub) Join(context.Context, games.JoinGameCommand) error {
+	return s.err
+}
+
+func (s *gamesServiceStub) Leave(context.Context, games.LeaveGameCommand) error {
+	return s.err
+}
+
+func (s *gamesServiceStub) Update(context.Context, games.UpdateGameCommand) error {
+	return s.err
+}
+
+func (s *gamesServiceStub) Vote(context.Context, games.VoteCommand) error {
+	return s.err
+}
+
+func (s *gamesServiceStub) UnVote(context.Context, games.UnVoteCommand) error {
+	return s.err
+}
+
+func (s *gamesServiceStub) Reveal(context.Context, games.RevealCardsCommand) error {
+	return s.err
+}
+
+func (s *gamesServiceStub) Restart(context.Context, games.RestartGameCommand) error {
+	return s.err
+}
+
+func (s *gamesServiceStub) Revote(context.Context, games.RevoteCommand) error {
+	return s.err
+}
+
+func (s *gamesServiceStub) Rearrange(context.Context, games.RearrangeSeatsCommand) error {
+	return s.err
+}
+
+func (s *gamesServiceStub) ChangePasscode(context.Context, games.ChangePasscodeCommand) error {
+	return s.err
+}
+
+func (s *gamesServiceStub) Spectate(context.Context, games.JoinGameCommand) error {
+	return s.err
+}
+
+func (s *gamesServiceStub) Chat(context.Context, games.ChatCommand) (*games.ChatMessage, error) {
+	return &games.ChatMessage{}, s.err
+}
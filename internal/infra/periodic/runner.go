@@ -0,0 +1,57 @@
+// Package periodic contains a runner of background jobs repeated at a fixed interval.
+package periodic
+
+import (
+	"sync"
+	"time"
+)
+
+// Runner calls the job in background every interval until it is stopped.
+type Runner struct {
+	job      func(now time.Time)
+	interval time.Duration
+	// runOnStart calls the job right away, without waiting for the first interval.
+	runOnStart bool
+	stop       chan struct{}
+	wg         sync.WaitGroup
+}
+
+// NewRunner creates a new runner instance, the interval should be positive.
+func NewRunner(interval time.Duration, runOnStart bool, job func(now time.Time)) *Runner {
+	return &Runner{
+		job:        job,
+		interval:   interval,
+		runOnStart: runOnStart,
+		stop:       make(chan struct{}),
+	}
+}
+
+// Start runs the job in background until Stop is called.
+func (r *Runner) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		if r.runOnStart {
+			r.job(time.Now())
+		}
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				r.job(now)
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background job and waits for the running call to finish.
+func (r *Runner) Stop() {
+	close(r.stop)
+	r.wg.Wait()
+}
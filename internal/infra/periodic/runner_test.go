@@ -0,0 +1,69 @@
+package periodic_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"planningpoker/internal/infra/periodic"
+)
+
+func TestRunner(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		interval   time.Duration
+		runOnStart bool
+	}{
+		"run every interval": {
+			interval: time.Millisecond,
+		},
+		"run on start": {
+			interval:   time.Hour,
+			runOnStart: true,
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			calls := make(chan time.Time, 100)
+			r := periodic.NewRunner(tt.interval, tt.runOnStart, func(now time.Time) {
+				calls <- now
+			})
+
+			r.Start()
+			select {
+			case <-calls:
+			case <-time.After(time.Second):
+				t.Fatal("the job was not called")
+			}
+			r.Stop()
+
+			// no calls are made after Stop returned
+			n := len(calls)
+			time.Sleep(10 * time.Millisecond)
+			assert.Equal(t, n, len(calls))
+		})
+	}
+}
+
+func TestRunner_StopWaitsForRunningJob(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	finished := false
+	r := periodic.NewRunner(time.Hour, true, func(time.Time) {
+		close(started)
+		time.Sleep(10 * time.Millisecond)
+		finished = true
+	})
+
+	r.Start()
+	<-started
+	r.Stop()
+
+	assert.True(t, finished)
+}
@@ -0,0 +1,99 @@
+// Package ratelimit contains token bucket rate limiting of clients.
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// purgeInterval is how often idle buckets are dropped, so the number of tracked clients stays bounded.
+const purgeInterval = time.Minute
+
+// ErrLimited is returned when the client exceeded the rate limit.
+var ErrLimited = errors.New("too many requests, try again later")
+
+// Limiter is a set of token buckets keyed by a client identity, e.g. an IP or a user ID.
+// A nil limiter allows everything, so limiting can be disabled by configuration.
+type Limiter struct {
+	rate  float64
+	burst float64
+
+	m         sync.Mutex
+	buckets   map[string]*bucket
+	lastPurge time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter creates a limiter refilling perSecond tokens every second up to burst tokens,
+// a non-positive rate disables limiting and nil is returned.
+func NewLimiter(perSecond float64, burst int) *Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+
+	return &Limiter{
+		rate:    perSecond,
+		burst:   math.Max(float64(burst), 1),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow takes a token of the client, false means that the client should be rejected.
+func (l *Limiter) Allow(key string) bool {
+	if l == nil {
+		return true
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	now := time.Now()
+	l.purge(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+
+	return true
+}
+
+// RetryAfter returns a time after which a rejected client gets a token again.
+func (l *Limiter) RetryAfter() time.Duration {
+	if l == nil {
+		return 0
+	}
+
+	return time.Duration(float64(time.Second) / l.rate)
+}
+
+// purge drops buckets which are full again, they are indistinguishable from new ones.
+// It should be called under the lock.
+func (l *Limiter) purge(now time.Time) {
+	if now.Sub(l.lastPurge) < purgeInterval {
+		return
+	}
+	l.lastPurge = now
+
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"planningpoker/internal/infra/ratelimit"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	t.Parallel()
+
+	l := ratelimit.NewLimiter(0.001, 2)
+
+	assert.True(t, l.Allow("a"))
+	assert.True(t, l.Allow("a"))
+	assert.False(t, l.Allow("a"), "the burst should be exhausted")
+	assert.True(t, l.Allow("b"), "clients should have separate buckets")
+	assert.Equal(t, 1000*time.Second, l.RetryAfter())
+}
+
+func TestLimiter_Refill(t *testing.T) {
+	t.Parallel()
+
+	l := ratelimit.NewLimiter(100, 1)
+
+	assert.True(t, l.Allow("a"))
+	assert.False(t, l.Allow("a"))
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, l.Allow("a"))
+}
+
+func TestLimiter_Disabled(t *testing.T) {
+	t.Parallel()
+
+	l := ratelimit.NewLimiter(0, 1)
+
+	assert.Nil(t, l)
+	for i := 0; i < 100; i++ {
+		assert.True(t, l.Allow("a"))
+	}
+	assert.Zero(t, l.RetryAfter())
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Proxies is a set of trusted reverse proxies. The client IP is read from the proxy headers only when
+// the peer is a trusted proxy, otherwise any client could get a fresh bucket by spoofing the headers.
+// A nil set trusts nobody.
+type Proxies struct {
+	nets []*net.IPNet
+}
+
+// NewProxies parses IPs and CIDRs of the trusted proxies, an empty list trusts nobody and nil is returned.
+func NewProxies(list []string) (*Proxies, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	p := &Proxies{nets: make([]*net.IPNet, 0, len(list))}
+	for _, s := range list {
+		ipNet, err := parseProxy(s)
+		if err != nil {
+			return nil, err
+		}
+		p.nets = append(p.nets, ipNet)
+	}
+
+	return p, nil
+}
+
+// ClientIP returns the IP of the client, remoteAddr is the host:port of the peer.
+// X-Forwarded-For is walked from the right, so the first hop not added by a trusted proxy is the client,
+// the hops on the left of it are controlled by the client.
+func (p *Proxies) ClientIP(remoteAddr string, header http.Header) string {
+	ip := remoteAddr
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		ip = host
+	}
+	if !p.trusts(ip) {
+		return ip
+	}
+
+	if forwarded := header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				break
+			}
+			ip = hop
+			if !p.trusts(hop) {
+				break
+			}
+		}
+		return ip
+	}
+
+	if realIP := strings.TrimSpace(header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+
+	return ip
+}
+
+func (p *Proxies) trusts(ip string) bool {
+	if p == nil {
+		return false
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range p.nets {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseProxy parses a CIDR or a single IP, which is a network of one address.
+func parseProxy(s string) (*net.IPNet, error) {
+	if _, ipNet, err := net.ParseCIDR(s); err == nil {
+		return ipNet, nil
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("trusted proxy %q should be an IP or a CIDR", s)
+	}
+	bits := 8 * net.IPv6len
+	if v4 := ip.To4(); v4 != nil {
+		ip, bits = v4, 8*net.IPv4len
+	}
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
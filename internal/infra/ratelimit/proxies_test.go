@@ -0,0 +1,104 @@
+package ratelimit_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/infra/ratelimit"
+)
+
+func TestProxies_ClientIP(t *testing.T) {
+	t.Parallel()
+
+	proxies, err := ratelimit.NewProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		proxies    *ratelimit.Proxies
+		remoteAddr string
+		header     http.Header
+		expIP      string
+	}{
+		"peer without headers": {
+			proxies:    proxies,
+			remoteAddr: "203.0.113.7:5000",
+			expIP:      "203.0.113.7",
+		},
+		"untrusted peer headers are ignored": {
+			proxies:    proxies,
+			remoteAddr: "203.0.113.7:5000",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.1"}, "X-Real-Ip": {"198.51.100.2"}},
+			expIP:      "203.0.113.7",
+		},
+		"nobody is trusted by default": {
+			remoteAddr: "10.0.0.1:5000",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.1"}},
+			expIP:      "10.0.0.1",
+		},
+		"trusted peer forwarded for": {
+			proxies:    proxies,
+			remoteAddr: "10.0.0.1:5000",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.1"}},
+			expIP:      "198.51.100.1",
+		},
+		"hops added by the client are skipped": {
+			proxies:    proxies,
+			remoteAddr: "10.0.0.1:5000",
+			header:     http.Header{"X-Forwarded-For": {"1.1.1.1, 198.51.100.1, 192.168.1.1"}},
+			expIP:      "198.51.100.1",
+		},
+		"malformed hop stops the walk": {
+			proxies:    proxies,
+			remoteAddr: "10.0.0.1:5000",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.1, garbage, 10.0.0.2"}},
+			expIP:      "10.0.0.2",
+		},
+		"trusted peer real ip": {
+			proxies:    proxies,
+			remoteAddr: "10.0.0.1:5000",
+			header:     http.Header{"X-Real-Ip": {"198.51.100.2"}},
+			expIP:      "198.51.100.2",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expIP, tt.proxies.ClientIP(tt.remoteAddr, tt.header))
+		})
+	}
+}
+
+func TestProxies_SpoofedHeaderDoesNotResetBucket(t *testing.T) {
+	t.Parallel()
+
+	proxies, err := ratelimit.NewProxies([]string{"10.0.0.1"})
+	require.NoError(t, err)
+	l := ratelimit.NewLimiter(0.001, 1)
+
+	allow := func(forwardedFor string) bool {
+		header := http.Header{"X-Forwarded-For": {forwardedFor}}
+		return l.Allow(proxies.ClientIP("203.0.113.7:5000", header))
+	}
+
+	assert.True(t, allow("198.51.100.1"))
+	assert.False(t, allow("198.51.100.2"), "a spoofed header should not give a fresh bucket")
+}
+
+func TestNewProxies(t *testing.T) {
+	t.Parallel()
+
+	proxies, err := ratelimit.NewProxies(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, proxies)
+
+	_, err = ratelimit.NewProxies([]string{"::1", "fd00::/8"})
+	assert.NoError(t, err)
+
+	_, err = ratelimit.NewProxies([]string{"proxy.local"})
+	assert.EqualError(t, err, `trusted proxy "proxy.local" should be an IP or a CIDR`)
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"planningpoker/internal/domain/audit"
+)
+
+type auditEntryDTO struct {
+	Action      string            `json:"action"`
+	ActorID     string            `json:"actor_id,omitempty"`
+	ActorHandle string            `json:"actor_handle,omitempty"`
+	ActorName   string            `json:"actor_name,omitempty"`
+	Before      map[string]string `json:"before,omitempty"`
+	After       map[string]string `json:"after,omitempty"`
+	OccurredAt  time.Time         `json:"occurred_at"`
+}
+
+func (d auditEntryDTO) toDomain(gameID string) audit.Entry {
+	return audit.Entry{
+		GameID:      gameID,
+		Action:      d.Action,
+		ActorID:     d.ActorID,
+		ActorHandle: d.ActorHandle,
+		ActorName:   d.ActorName,
+		Before:      d.Before,
+		After:       d.After,
+		OccurredAt:  d.OccurredAt,
+	}
+}
+
+// AppendAuditEntry adds the entry to the game trail, the trail lives and dies with the game.
+func (r *MemoryGameRepository) AppendAuditEntry(_ context.Context, entry audit.Entry) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	// the game might be deleted before its last events are consumed
+	if _, ok := r.games[entry.GameID]; !ok {
+		return nil
+	}
+
+	r.audit[entry.GameID] = append(r.audit[entry.GameID], auditEntryDTO{
+		Action:      entry.Action,
+		ActorID:     entry.ActorID,
+		ActorHandle: entry.ActorHandle,
+		ActorName:   entry.ActorName,
+		Before:      entry.Before,
+		After:       entry.After,
+		OccurredAt:  entry.OccurredAt,
+	})
+
+	return nil
+}
+
+// GetAuditLog returns the game trail in the order of appending.
+func (r *MemoryGameRepository) GetAuditLog(_ context.Context, gameID string) ([]audit.Entry, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	list := make([]audit.Entry, len(r.audit[gameID]))
+	for i, dto := range r.audit[gameID] {
+		list[i] = dto.toDomain(gameID)
+	}
+
+	return list, nil
+}
+
+// dumpAudit returns encoded trails of all the stored games.
+func (r *MemoryGameRepository) dumpAudit() (map[string]json.RawMessage, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	out := make(map[string]json.RawMessage, len(r.audit))
+	for id, list := range r.audit {
+		raw, err := json.Marshal(list)
+		if err != nil {
+			return nil, fmt.Errorf("game %s audit: %w", id, err)
+		}
+		out[id] = raw
+	}
+
+	return out, nil
+}
+
+// restoreAudit replaces trails of all the stored games, trails of unknown games are dropped.
+func (r *MemoryGameRepository) restoreAudit(list map[string]json.RawMessage) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.audit = make(map[string][]auditEntryDTO, len(list))
+	for id, raw := range list {
+		if _, ok := r.games[id]; !ok {
+			continue
+		}
+
+		var entries []auditEntryDTO
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return fmt.Errorf("game %s audit: %w", id, err)
+		}
+		r.audit[id] = entries
+	}
+
+	return nil
+}
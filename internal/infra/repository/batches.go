@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"planningpoker/internal/domain/batches"
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/infra/logging"
+)
+
+type ticketDTO struct {
+	ID    string            `json:"id"`
+	Name  string            `json:"name"`
+	URL   string            `json:"url,omitempty"`
+	Votes map[string]string `json:"votes"`
+	// RevealedAt is omitted until the ticket is revealed.
+	RevealedAt *time.Time `json:"revealed_at,omitempty"`
+}
+
+type batchDTO struct {
+	ID            string               `json:"id"`
+	Name          string               `json:"name"`
+	FacilitatorID string               `json:"facilitator_id"`
+	TeamID        string               `json:"team_id,omitempty"`
+	CardsDeck     cardsDeckDTO         `json:"cards_deck"`
+	Deadline      time.Time            `json:"deadline"`
+	Participants  map[string]time.Time `json:"participants"`
+	Tickets       []ticketDTO          `json:"tickets"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
+
+func newBatchDTO(batch *batches.Batch) batchDTO {
+	dto := batchDTO{
+		ID:            batch.ID(),
+		Name:          batch.Name(),
+		FacilitatorID: batch.FacilitatorID(),
+		TeamID:        batch.TeamID(),
+		CardsDeck:     newCardsDeckDTO(batch.CardsDeck()),
+		Deadline:      batch.Deadline(),
+		Participants:  make(map[string]time.Time, len(batch.Participants())),
+		Tickets:       make([]ticketDTO, len(batch.Tickets())),
+		CreatedAt:     batch.CreatedAt(),
+	}
+
+	for id, joinedAt := range batch.Participants() {
+		dto.Participants[id] = joinedAt
+	}
+	for i, t := range batch.Tickets() {
+		dto.Tickets[i] = ticketDTO{ID: t.ID, Name: t.Name, URL: t.URL, Votes: make(map[string]string, len(t.Votes))}
+		for uid, card := range t.Votes {
+			dto.Tickets[i].Votes[uid] = card.Type()
+		}
+		if t.IsRevealed() {
+			revealedAt := t.RevealedAt
+			dto.Tickets[i].RevealedAt = &revealedAt
+		}
+	}
+
+	return dto
+}
+
+func (d batchDTO) toDomain() (*batches.Batch, error) {
+	deck, err := d.CardsDeck.toDomain()
+	if err != nil {
+		return nil, err
+	}
+
+	participants := make(map[string]time.Time, len(d.Participants))
+	for id, joinedAt := range d.Participants {
+		participants[id] = joinedAt
+	}
+
+	tickets := make([]*batches.Ticket, len(d.Tickets))
+	for i, t := range d.Tickets {
+		tickets[i] = &batches.Ticket{ID: t.ID, Name: t.Name, URL: t.URL, Votes: make(map[string]games.Card, len(t.Votes))}
+		for uid, card := range t.Votes {
+			tickets[i].Votes[uid] = games.Card(card)
+		}
+		if t.RevealedAt != nil {
+			tickets[i].RevealedAt = *t.RevealedAt
+		}
+	}
+
+	return batches.NewRaw(
+		d.ID, d.Name, d.FacilitatorID, d.TeamID, *deck, d.Deadline, participants, tickets, d.CreatedAt,
+	), nil
+}
+
+// isOverdue checks if the deadline has passed by now and some tickets are not revealed yet.
+func (d batchDTO) isOverdue(now time.Time) bool {
+	if now.Before(d.Deadline) {
+		return false
+	}
+	for _, t := range d.Tickets {
+		if t.RevealedAt == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MemoryBatchRepository is a simple in-memory batches repository.
+type MemoryBatchRepository struct {
+	bm       sync.Mutex
+	m        sync.RWMutex
+	batches  map[string][]byte
+	eventBus events.EventBus
+	logger   *logrus.Entry
+}
+
+// NewMemoryBatchRepository creates a new in-memory repository instance.
+func NewMemoryBatchRepository(bus events.EventBus, logger *logrus.Entry) *MemoryBatchRepository {
+	return &MemoryBatchRepository{
+		batches:  make(map[string][]byte),
+		eventBus: bus,
+		logger:   logger,
+	}
+}
+
+// ModifyExclusively does exclusive blocking modification, so no other goroutines can modify batches concurrently.
+func (r *MemoryBatchRepository) ModifyExclusively(ctx context.Context, id string, cb func(*batches.Batch) error) error {
+	r.bm.Lock()
+	defer r.bm.Unlock()
+
+	batch, err := r.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("batch fetching: %w", err)
+	}
+	if batch == nil {
+		return batches.ErrBatchNotFound
+	}
+
+	if err := cb(batch); err != nil {
+		return err
+	}
+
+	if err := r.Save(ctx, batch); err != nil {
+		return fmt.Errorf("batch save: %w", err)
+	}
+
+	return nil
+}
+
+// Save persists the batch.
+func (r *MemoryBatchRepository) Save(ctx context.Context, batch *batches.Batch) error {
+	raw, err := json.Marshal(newBatchDTO(batch))
+	if err != nil {
+		return err
+	}
+
+	r.m.Lock()
+	r.batches[batch.ID()] = raw
+	r.m.Unlock()
+
+	for _, e := range batch.GetEvents() {
+		if err := r.eventBus.Publish(ctx, e); err != nil {
+			// this is a simple handler, which provides at most once delivery
+			// in case if a bus is broken, it will log the error and continue
+			r.logger.WithContext(ctx).WithError(err).WithField(logging.FieldBatchID, batch.ID()).Error("unable to publish a batch event")
+		}
+	}
+
+	return nil
+}
+
+// Get retrieves the batch.
+func (r *MemoryBatchRepository) Get(_ context.Context, id string) (*batches.Batch, error) {
+	r.m.RLock()
+	raw, ok := r.batches[id]
+	r.m.RUnlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	dto := batchDTO{}
+	if err := json.Unmarshal(raw, &dto); err != nil {
+		return nil, err
+	}
+
+	return dto.toDomain()
+}
+
+// GetBatchesByParticipantID returns all batches the user participates in.
+func (r *MemoryBatchRepository) GetBatchesByParticipantID(_ context.Context, userID string) ([]batches.Batch, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	list := make([]batches.Batch, 0)
+	for id, raw := range r.batches {
+		dto := batchDTO{}
+		if err := json.Unmarshal(raw, &dto); err != nil {
+			return nil, fmt.Errorf("batch %s: %w", id, err)
+		}
+		if _, ok := dto.Participants[userID]; !ok {
+			continue
+		}
+
+		batch, err := dto.toDomain()
+		if err != nil {
+			return nil, fmt.Errorf("batch %s: %w", id, err)
+		}
+		list = append(list, *batch)
+	}
+
+	return list, nil
+}
+
+// GetOverdueBatchIDs returns IDs of batches with the deadline before now and tickets which are not revealed yet.
+func (r *MemoryBatchRepository) GetOverdueBatchIDs(_ context.Context, now time.Time) ([]string, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	ids := make([]string, 0)
+	for id, raw := range r.batches {
+		dto := batchDTO{}
+		if err := json.Unmarshal(raw, &dto); err != nil {
+			return nil, fmt.Errorf("batch %s: %w", id, err)
+		}
+		if dto.isOverdue(now) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// dump returns a copy of all the stored batches.
+func (r *MemoryBatchRepository) dump() map[string]json.RawMessage {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	out := make(map[string]json.RawMessage, len(r.batches))
+	for id, raw := range r.batches {
+		out[id] = raw
+	}
+
+	return out
+}
+
+// restore replaces all the stored batches, no events are published.
+func (r *MemoryBatchRepository) restore(list map[string]json.RawMessage) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.batches = make(map[string][]byte, len(list))
+	for id, raw := range list {
+		dto := batchDTO{}
+		if err := json.Unmarshal(raw, &dto); err != nil {
+			return fmt.Errorf("batch %s: %w", id, err)
+		}
+		r.batches[id] = raw
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package repository_test
+
+import (
+	"testing"
+
+	"planningpoker/internal/domain/batches"
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/infra/repository"
+	"planningpoker/test"
+)
+
+func TestMemoryBatchRepository_Contract(t *testing.T) {
+	t.Parallel()
+
+	test.BatchRepositoryContract(t, func(bus events.EventBus) batches.Repository {
+		return repository.NewMemoryBatchRepository(bus, test.NewLogger())
+	})
+}
@@ -2,31 +2,66 @@ package repository
 
 import (
 	"fmt"
+	"time"
 
 	"planningpoker/internal/domain/games"
 )
 
-type cardsDeckDTO struct {
+type dimensionDTO struct {
 	Name  string   `json:"name"`
 	Cards []string `json:"cards"`
 }
 
+// cardsDeckDTO keeps plain decks in the same shape they always had, dimensions are added for dimensional decks only.
+type cardsDeckDTO struct {
+	Name       string         `json:"name"`
+	Cards      []string       `json:"cards"`
+	Dimensions []dimensionDTO `json:"dimensions,omitempty"`
+	Formula    string         `json:"formula,omitempty"`
+}
+
 func newCardsDeckDTO(d games.CardsDeck) cardsDeckDTO {
 	dto := cardsDeckDTO{
-		Name:  d.Name(),
-		Cards: make([]string, len(d.Cards())),
+		Name:    d.Name(),
+		Cards:   cardTypes(d.Cards()),
+		Formula: d.Formula(),
 	}
 
-	for i, c := range d.Cards() {
-		dto.Cards[i] = c.Type()
+	for _, dim := range d.Dimensions() {
+		dto.Dimensions = append(dto.Dimensions, dimensionDTO{Name: dim.Name(), Cards: cardTypes(dim.Cards())})
 	}
 
 	return dto
 }
 
 func (d cardsDeckDTO) toDomain() (*games.CardsDeck, error) {
-	cards := make([]games.Card, len(d.Cards))
-	for i, v := range d.Cards {
+	if len(d.Dimensions) == 0 {
+		cards, err := newCards(d.Cards)
+		if err != nil {
+			return nil, err
+		}
+		return games.NewCardsDeck(d.Name, cards)
+	}
+
+	dimensions := make([]games.Dimension, len(d.Dimensions))
+	for i, dim := range d.Dimensions {
+		cards, err := newCards(dim.Cards)
+		if err != nil {
+			return nil, err
+		}
+		dimension, err := games.NewDimension(dim.Name, cards)
+		if err != nil {
+			return nil, fmt.Errorf("dimension creation: %w", err)
+		}
+		dimensions[i] = *dimension
+	}
+
+	return games.NewDimensionalCardsDeck(d.Name, dimensions, d.Formula)
+}
+
+func newCards(types []string) ([]games.Card, error) {
+	cards := make([]games.Card, len(types))
+	for i, v := range types {
 		c, err := games.NewCard(v)
 		if err != nil {
 			return nil, fmt.Errorf("card creation: %w", err)
@@ -34,14 +69,53 @@ func (d cardsDeckDTO) toDomain() (*games.CardsDeck, error) {
 		cards[i] = *c
 	}
 
-	return games.NewCardsDeck(d.Name, cards)
+	return cards, nil
+}
+
+func cardTypes(cards []games.Card) []string {
+	types := make([]string, len(cards))
+	for i, c := range cards {
+		types[i] = c.Type()
+	}
+
+	return types
+}
+
+// newVotedCardsDTO encodes cards voted per dimension, nil is returned for plain votes.
+func newVotedCardsDTO(cards map[string]games.Card) map[string]string {
+	if len(cards) == 0 {
+		return nil
+	}
+
+	dto := make(map[string]string, len(cards))
+	for name, c := range cards {
+		dto[name] = c.Type()
+	}
+
+	return dto
+}
+
+func votedCardsToDomain(dto map[string]string) map[string]games.Card {
+	if len(dto) == 0 {
+		return nil
+	}
+
+	cards := make(map[string]games.Card, len(dto))
+	for name, c := range dto {
+		cards[name] = games.Card(c)
+	}
+
+	return cards
 }
 
 type playerDTO struct {
-	VotedCard  string `json:"voted_card"`
-	CanReveal  bool   `json:"can_reveal"`
-	Confidence string `json:"confidence"`
-	Active     bool   `json:"active"`
+	VotedCard  string            `json:"voted_card"`
+	VotedCards map[string]string `json:"voted_cards,omitempty"`
+	CanReveal  bool              `json:"can_reveal"`
+	Confidence string            `json:"confidence"`
+	Active     bool              `json:"active"`
+	Seat       int               `json:"seat"`
+	Handle     string            `json:"handle"`
 }
 
 func (d playerDTO) toDomain() (*games.Player, error) {
@@ -57,12 +131,56 @@ func (d playerDTO) toDomain() (*games.Player, error) {
 
 	return &games.Player{
 		VotedCard:  votedCard,
+		VotedCards: votedCardsToDomain(d.VotedCards),
 		CanReveal:  d.CanReveal,
 		Confidence: d.Confidence,
 		Active:     d.Active,
+		Seat:       d.Seat,
+		Handle:     d.Handle,
 	}, nil
 }
 
+type attemptVoteDTO struct {
+	UserID string            `json:"user_id"`
+	Handle string            `json:"handle"`
+	Card   string            `json:"card"`
+	Cards  map[string]string `json:"cards,omitempty"`
+}
+
+type attemptDTO struct {
+	Number int              `json:"number"`
+	Votes  []attemptVoteDTO `json:"votes"`
+}
+
+func newAttemptDTOs(attempts []games.Attempt) []attemptDTO {
+	if len(attempts) == 0 {
+		return nil
+	}
+
+	list := make([]attemptDTO, len(attempts))
+	for i, a := range attempts {
+		list[i] = attemptDTO{Number: a.Number, Votes: make([]attemptVoteDTO, len(a.Votes))}
+		for j, v := range a.Votes {
+			list[i].Votes[j] = attemptVoteDTO{
+				UserID: v.UserID, Handle: v.Handle, Card: v.Card.Type(), Cards: newVotedCardsDTO(v.Cards),
+			}
+		}
+	}
+
+	return list
+}
+
+func (d attemptDTO) toDomain() games.Attempt {
+	a := games.Attempt{Number: d.Number, Votes: make([]games.AttemptVote, len(d.Votes))}
+	for i, v := range d.Votes {
+		a.Votes[i] = games.AttemptVote{
+			UserID: v.UserID, Handle: v.Handle, Card: games.Card(v.Card), Cards: votedCardsToDomain(v.Cards),
+		}
+	}
+
+	return a
+}
+
 type gameDTO struct {
 	ID                string               `json:"id"`
 	Name              string               `json:"name"`
@@ -71,6 +189,14 @@ type gameDTO struct {
 	Players           map[string]playerDTO `json:"players"`
 	State             string               `json:"state"`
 	EveryoneCanReveal bool                 `json:"everyone_can_reveal"`
+	FacilitatorID     string               `json:"facilitator_id"`
+	LastActivityAt    time.Time            `json:"last_activity_at"`
+	PasscodeHash      []byte               `json:"passcode_hash,omitempty"`
+	TeamID            string               `json:"team_id,omitempty"`
+	Attempts          []attemptDTO         `json:"attempts,omitempty"`
+	RevoteThreshold   int                  `json:"revote_threshold,omitempty"`
+	Anonymous         bool                 `json:"anonymous,omitempty"`
+	ChatDisabled      bool                 `json:"chat_disabled,omitempty"`
 }
 
 func (d gameDTO) toDomain() (*games.Game, error) {
@@ -87,7 +213,15 @@ func (d gameDTO) toDomain() (*games.Game, error) {
 		}
 	}
 
-	game := games.NewRaw(d.ID, d.Name, d.TicketURL, *deck, players, d.State, d.EveryoneCanReveal)
+	var attempts []games.Attempt
+	for _, a := range d.Attempts {
+		attempts = append(attempts, a.toDomain())
+	}
+
+	game := games.NewRaw(
+		d.ID, d.Name, d.TicketURL, *deck, players, d.State, d.EveryoneCanReveal, d.FacilitatorID, d.LastActivityAt,
+		d.PasscodeHash, d.TeamID, attempts, d.RevoteThreshold, d.Anonymous, d.ChatDisabled,
+	)
 
 	return game, err
 }
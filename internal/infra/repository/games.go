@@ -1,14 +1,20 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"planningpoker/internal/domain/events"
 	"planningpoker/internal/domain/games"
+	"planningpoker/internal/infra/logging"
+	"planningpoker/internal/infra/tracing"
 )
 
 // MemoryGameRepository is a simple in-memory linear games repository.
@@ -17,43 +23,84 @@ type MemoryGameRepository struct {
 	m        sync.RWMutex
 	games    map[string][]byte
 	eventBus events.EventBus
+	logger   *logrus.Entry
+	// playerGames is a secondary index of game IDs by player ID.
+	playerGames map[string]map[string]struct{}
+	// gamePlayers keeps indexed player IDs of each game, so the index can be cleaned up on changes.
+	gamePlayers map[string][]string
+	// facilitatorGames is a secondary index of IDs of not archived games by facilitator ID.
+	facilitatorGames map[string]map[string]struct{}
+	// gameFacilitators keeps the indexed facilitator ID of each game, so the index can be cleaned up on changes.
+	gameFacilitators map[string]string
+	// activePlayers keeps the number of players of every running game, so metrics are collected without decoding.
+	activePlayers map[string]int
+	// audit keeps the append-only trail of every game.
+	audit map[string][]auditEntryDTO
+	// lockWaitObserver receives the time spent waiting for the exclusive modification lock.
+	lockWaitObserver func(time.Duration)
 }
 
 // NewMemoryGameRepository creates a new in-memory repository instance.
-func NewMemoryGameRepository(bus events.EventBus) *MemoryGameRepository {
+func NewMemoryGameRepository(bus events.EventBus, logger *logrus.Entry) *MemoryGameRepository {
 	return &MemoryGameRepository{
-		games:    make(map[string][]byte),
-		eventBus: bus,
+		games:       make(map[string][]byte),
+		eventBus:    bus,
+		logger:      logger,
+		playerGames: make(map[string]map[string]struct{}),
+		gamePlayers: make(map[string][]string),
+		audit:       make(map[string][]auditEntryDTO),
+
+		facilitatorGames: make(map[string]map[string]struct{}),
+		gameFacilitators: make(map[string]string),
+		activePlayers:    make(map[string]int),
 	}
 }
 
 // ModifyExclusively does exclusive blocking modification, so no other goroutines can modify the database exclusively
 // quick and dirty implementation, should evolve in something blocking on an external database level...
-func (r *MemoryGameRepository) ModifyExclusively(id string, cb func(*games.Game) error) error {
+func (r *MemoryGameRepository) ModifyExclusively(ctx context.Context, id string, cb func(*games.Game) error) (err error) {
+	ctx, span := tracing.Start(ctx, "repository.ModifyExclusively", tracing.AttrGameID.String(id))
+	defer func() { tracing.End(span, err) }()
+
+	start := time.Now()
 	r.gm.Lock()
 	defer r.gm.Unlock()
+	wait := time.Since(start)
+	span.AddEvent("lock acquired", trace.WithAttributes(attribute.Int64("lock.wait_us", wait.Microseconds())))
+	if r.lockWaitObserver != nil {
+		r.lockWaitObserver(wait)
+	}
 
-	game, err := r.Get(id)
+	game, err := r.Get(ctx, id)
 	if err != nil {
 		return fmt.Errorf("game fetching: %w", err)
 	}
 	if game == nil {
-		return errors.New("game not found")
+		return games.ErrGameNotFound
 	}
 
 	if err := cb(game); err != nil {
 		return err
 	}
 
-	if err := r.Save(game); err != nil {
+	if err := r.Save(ctx, game); err != nil {
 		return fmt.Errorf("game save: %w", err)
 	}
 
 	return nil
 }
 
+// ObserveLockWait sets an observer of the time spent waiting for the exclusive modification lock.
+// It should be called before the repository is used.
+func (r *MemoryGameRepository) ObserveLockWait(observer func(wait time.Duration)) {
+	r.lockWaitObserver = observer
+}
+
 // Save persists the game.
-func (r *MemoryGameRepository) Save(game *games.Game) error {
+func (r *MemoryGameRepository) Save(ctx context.Context, game *games.Game) (err error) {
+	ctx, span := tracing.Start(ctx, "repository.Save", tracing.AttrGameID.String(game.ID()))
+	defer func() { tracing.End(span, err) }()
+
 	dto := gameDTO{
 		ID:                game.ID(),
 		Name:              game.Name(),
@@ -62,6 +109,14 @@ func (r *MemoryGameRepository) Save(game *games.Game) error {
 		Players:           make(map[string]playerDTO),
 		State:             game.State(),
 		EveryoneCanReveal: game.EveryoneCanReveal(),
+		FacilitatorID:     game.FacilitatorID(),
+		LastActivityAt:    game.LastActivityAt(),
+		PasscodeHash:      game.PasscodeHash(),
+		TeamID:            game.TeamID(),
+		Attempts:          newAttemptDTOs(game.Attempts()),
+		RevoteThreshold:   game.RevoteThreshold(),
+		Anonymous:         game.IsAnonymous(),
+		ChatDisabled:      game.IsChatDisabled(),
 	}
 
 	for id, p := range game.Players() {
@@ -71,9 +126,12 @@ func (r *MemoryGameRepository) Save(game *games.Game) error {
 		}
 		dto.Players[id] = playerDTO{
 			VotedCard:  votedCard,
+			VotedCards: newVotedCardsDTO(p.VotedCards),
 			CanReveal:  p.CanReveal,
 			Active:     p.Active,
 			Confidence: p.Confidence,
+			Seat:       p.Seat,
+			Handle:     p.Handle,
 		}
 	}
 
@@ -85,13 +143,16 @@ func (r *MemoryGameRepository) Save(game *games.Game) error {
 	r.m.Lock()
 	defer r.m.Unlock()
 	r.games[game.ID()] = raw
+	r.indexPlayers(game.ID(), game.PlayerIDs())
+	r.indexFacilitator(game.ID(), facilitatorOf(game))
+	r.countActivePlayers(game)
 
 	for _, e := range game.GetEvents() {
-		if err := r.eventBus.Publish(e); err != nil {
+		if err := r.eventBus.Publish(ctx, e); err != nil {
 			// this is a simple handler, which provides at most once delivery
 			// in case if a bus is broken, it will log the error and continue
 			// TODO: implement outbox pattern in order to mitigate potential distributed transactions
-			logrus.Errorf("failed to publish a game event: %v", err)
+			r.logger.WithContext(ctx).WithError(err).WithField(logging.FieldGameID, game.ID()).Error("unable to publish a game event")
 		}
 	}
 
@@ -99,7 +160,7 @@ func (r *MemoryGameRepository) Save(game *games.Game) error {
 }
 
 // Get retrieves the game.
-func (r *MemoryGameRepository) Get(id string) (*games.Game, error) {
+func (r *MemoryGameRepository) Get(_ context.Context, id string) (*games.Game, error) {
 	r.m.RLock()
 	raw, ok := r.games[id]
 	r.m.RUnlock()
@@ -117,24 +178,55 @@ func (r *MemoryGameRepository) Get(id string) (*games.Game, error) {
 	return dto.toDomain()
 }
 
+// Delete removes the game together with its audit trail.
+func (r *MemoryGameRepository) Delete(_ context.Context, id string) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	delete(r.games, id)
+	delete(r.audit, id)
+	r.indexPlayers(id, nil)
+	r.indexFacilitator(id, "")
+	delete(r.activePlayers, id)
+
+	return nil
+}
+
+// GetIdleGameIDs returns IDs of all games without any activity since specific time.
+func (r *MemoryGameRepository) GetIdleGameIDs(_ context.Context, since time.Time) ([]string, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	ids := make([]string, 0)
+	for id, j := range r.games {
+		dto := gameDTO{}
+		if err := json.Unmarshal(j, &dto); err != nil {
+			return nil, err
+		}
+		if dto.LastActivityAt.Before(since) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
 // GetActiveGamesByPlayerID returns all games where specific user is an active participant.
-func (r *MemoryGameRepository) GetActiveGamesByPlayerID(playerID string) ([]games.Game, error) {
+// Only games of the player are decoded, thanks to the player index.
+func (r *MemoryGameRepository) GetActiveGamesByPlayerID(_ context.Context, playerID string) ([]games.Game, error) {
 	r.m.RLock()
 	defer r.m.RUnlock()
 
-	list := make([]games.Game, 0)
-	for _, j := range r.games {
+	list := make([]games.Game, 0, len(r.playerGames[playerID]))
+	for id := range r.playerGames[playerID] {
 		dto := gameDTO{}
-		err := json.Unmarshal(j, &dto)
+		err := json.Unmarshal(r.games[id], &dto)
 		if err != nil {
 			return nil, err
 		}
 		if dto.State != games.GameStateStarted {
 			continue
 		}
-		if _, ok := dto.Players[playerID]; !ok {
-			continue
-		}
 
 		g, err := dto.toDomain()
 		if err != nil {
@@ -146,3 +238,159 @@ func (r *MemoryGameRepository) GetActiveGamesByPlayerID(playerID string) ([]game
 
 	return list, nil
 }
+
+// CountGamesByFacilitatorID returns the number of not archived games facilitated by specific user.
+func (r *MemoryGameRepository) CountGamesByFacilitatorID(_ context.Context, facilitatorID string) (int, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	return len(r.facilitatorGames[facilitatorID]), nil
+}
+
+// GetGamesByTeamID returns all games of the team, both running and archived ones.
+func (r *MemoryGameRepository) GetGamesByTeamID(_ context.Context, teamID string) ([]games.Game, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	list := make([]games.Game, 0)
+	for id, raw := range r.games {
+		dto := gameDTO{}
+		if err := json.Unmarshal(raw, &dto); err != nil {
+			return nil, fmt.Errorf("game %s: %w", id, err)
+		}
+		if dto.TeamID != teamID {
+			continue
+		}
+
+		g, err := dto.toDomain()
+		if err != nil {
+			return nil, fmt.Errorf("game %s: %w", id, err)
+		}
+		list = append(list, *g)
+	}
+
+	return list, nil
+}
+
+// GetActiveGamesPlayersCount returns the number of players of every running game.
+func (r *MemoryGameRepository) GetActiveGamesPlayersCount(_ context.Context) ([]int, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	counts := make([]int, 0, len(r.activePlayers))
+	for _, n := range r.activePlayers {
+		counts = append(counts, n)
+	}
+
+	return counts, nil
+}
+
+// indexPlayers replaces indexed players of the game, nil players removes the game from the index.
+// It should be called under the write lock.
+func (r *MemoryGameRepository) indexPlayers(gameID string, playerIDs []string) {
+	for _, pid := range r.gamePlayers[gameID] {
+		delete(r.playerGames[pid], gameID)
+		if len(r.playerGames[pid]) == 0 {
+			delete(r.playerGames, pid)
+		}
+	}
+
+	if len(playerIDs) == 0 {
+		delete(r.gamePlayers, gameID)
+		return
+	}
+
+	for _, pid := range playerIDs {
+		if r.playerGames[pid] == nil {
+			r.playerGames[pid] = make(map[string]struct{})
+		}
+		r.playerGames[pid][gameID] = struct{}{}
+	}
+	r.gamePlayers[gameID] = playerIDs
+}
+
+// indexFacilitator replaces the indexed facilitator of the game, empty facilitator removes the game from the index.
+// It should be called under the write lock.
+func (r *MemoryGameRepository) indexFacilitator(gameID, facilitatorID string) {
+	if prev, ok := r.gameFacilitators[gameID]; ok {
+		delete(r.facilitatorGames[prev], gameID)
+		if len(r.facilitatorGames[prev]) == 0 {
+			delete(r.facilitatorGames, prev)
+		}
+	}
+
+	if facilitatorID == "" {
+		delete(r.gameFacilitators, gameID)
+		return
+	}
+
+	if r.facilitatorGames[facilitatorID] == nil {
+		r.facilitatorGames[facilitatorID] = make(map[string]struct{})
+	}
+	r.facilitatorGames[facilitatorID][gameID] = struct{}{}
+	r.gameFacilitators[gameID] = facilitatorID
+}
+
+// countActivePlayers keeps the number of players of the running game, other games are not counted.
+// It should be called under the write lock.
+func (r *MemoryGameRepository) countActivePlayers(game *games.Game) {
+	if game.State() != games.GameStateStarted {
+		delete(r.activePlayers, game.ID())
+		return
+	}
+
+	r.activePlayers[game.ID()] = len(game.Players())
+}
+
+// facilitatorOf returns the facilitator to index the game by, archived games are not indexed.
+func facilitatorOf(game *games.Game) string {
+	if game.State() == games.GameStateArchived {
+		return ""
+	}
+
+	return game.FacilitatorID()
+}
+
+// dump returns a copy of all the stored games.
+func (r *MemoryGameRepository) dump() map[string]json.RawMessage {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	out := make(map[string]json.RawMessage, len(r.games))
+	for id, raw := range r.games {
+		out[id] = raw
+	}
+
+	return out
+}
+
+// restore replaces all the stored games, no events are published.
+func (r *MemoryGameRepository) restore(list map[string]json.RawMessage) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.games = make(map[string][]byte, len(list))
+	r.playerGames = make(map[string]map[string]struct{})
+	r.gamePlayers = make(map[string][]string)
+	r.facilitatorGames = make(map[string]map[string]struct{})
+	r.gameFacilitators = make(map[string]string)
+	r.activePlayers = make(map[string]int)
+
+	for id, raw := range list {
+		dto := gameDTO{}
+		if err := json.Unmarshal(raw, &dto); err != nil {
+			return fmt.Errorf("game %s: %w", id, err)
+		}
+		g, err := dto.toDomain()
+		if err != nil {
+			return fmt.Errorf("game %s: %w", id, err)
+		}
+
+		r.games[id] = raw
+		r.indexPlayers(id, g.PlayerIDs())
+		r.indexFacilitator(id, facilitatorOf(g))
+		r.countActivePlayers(g)
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"planningpoker/internal/domain/audit"
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/teams"
+	"planningpoker/internal/infra/repository"
+	"planningpoker/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryGameRepository_Contract(t *testing.T) {
+	t.Parallel()
+
+	test.GameRepositoryContract(t, func(bus events.EventBus) games.GameRepository {
+		return repository.NewMemoryGameRepository(bus, test.NewLogger())
+	})
+}
+
+func TestMemoryGameRepository_PlayersIndex(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	repo := repository.NewMemoryGameRepository(eventBusStub{}, test.NewLogger())
+
+	game1 := test.NewTestGame(t, test.NewSimpleGame(t, true)).UserJoins(test.User1).UserJoins(test.User2).Instance()
+	game2 := test.NewTestGame(t, test.NewSimpleGame(t, true)).UserJoins(test.User1).Instance()
+	require.NoError(t, repo.Save(ctx, game1))
+	require.NoError(t, repo.Save(ctx, game2))
+
+	assertActiveGames(t, repo, test.User1, game1.ID(), game2.ID())
+	assertActiveGames(t, repo, test.User2, game1.ID())
+
+	test.NewTestGame(t, game1).UserLeaves(test.User2).ShouldSucceed()
+	require.NoError(t, repo.Save(ctx, game1))
+	assertActiveGames(t, repo, test.User2)
+
+	test.NewTestGame(t, game2).UserReveals(test.User1).ShouldSucceed()
+	require.NoError(t, repo.Save(ctx, game2))
+	assertActiveGames(t, repo, test.User1, game1.ID())
+
+	require.NoError(t, repo.Delete(ctx, game1.ID()))
+	assertActiveGames(t, repo, test.User1)
+}
+
+func TestMemoryGameRepository_GetActiveGamesPlayersCount(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	repo := repository.NewMemoryGameRepository(eventBusStub{}, test.NewLogger())
+	running := test.NewTestGame(t, test.NewSimpleGame(t, true)).UserJoins(test.User1).UserJoins(test.User2).Instance()
+	finished := test.NewTestGame(t, test.NewSimpleGame(t, true)).UserJoins(test.User1).UserReveals(test.User1).Instance()
+	require.NoError(t, repo.Save(ctx, running))
+	require.NoError(t, repo.Save(ctx, finished))
+
+	counts, err := repo.GetActiveGamesPlayersCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, counts)
+
+	// the counts follow changes of the games
+	test.NewTestGame(t, running).UserJoins(test.User3)
+	test.NewTestGame(t, finished).UserRestartsGame(test.User1)
+	require.NoError(t, repo.Save(ctx, running))
+	require.NoError(t, repo.Save(ctx, finished))
+	counts, err = repo.GetActiveGamesPlayersCount(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int{3, 1}, counts)
+
+	require.NoError(t, repo.Delete(ctx, running.ID()))
+	counts, err = repo.GetActiveGamesPlayersCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, counts)
+}
+
+func TestMemoryGameRepository_GetGamesByTeamID(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	repo := repository.NewMemoryGameRepository(eventBusStub{}, test.NewLogger())
+	team := test.NewTeam(t, test.User1)
+	cmd, err := team.NewGameCommand(teams.CreateGameCommand{TeamID: team.ID(), UserID: test.User1})
+	require.NoError(t, err)
+	teamGame := games.NewGame(*cmd)
+	require.NoError(t, repo.Save(ctx, teamGame))
+	require.NoError(t, repo.Save(ctx, test.NewSimpleGame(t, true)))
+
+	list, err := repo.GetGamesByTeamID(ctx, team.ID())
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, teamGame.ID(), list[0].ID())
+	assert.Equal(t, team.ID(), list[0].TeamID())
+
+	list, err = repo.GetGamesByTeamID(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestMemoryGameRepository_AuditLog(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	repo := repository.NewMemoryGameRepository(eventBusStub{}, test.NewLogger())
+	game := test.NewSimpleGame(t, true)
+	require.NoError(t, repo.Save(ctx, game))
+
+	entries := []audit.Entry{
+		{GameID: game.ID(), Action: games.ActionJoin, ActorID: test.User1, OccurredAt: time.Now()},
+		{GameID: game.ID(), Action: games.ActionUpdate, ActorID: test.User1, Before: map[string]string{"name": "a"},
+			After: map[string]string{"name": "b"}, OccurredAt: time.Now()},
+		{GameID: "unknown", Action: games.ActionJoin},
+	}
+	for _, e := range entries {
+		require.NoError(t, repo.AppendAuditEntry(ctx, e))
+	}
+
+	list, err := repo.GetAuditLog(ctx, game.ID())
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, entries[1].After, list[1].After)
+	assert.True(t, entries[1].OccurredAt.Equal(list[1].OccurredAt))
+
+	list, err = repo.GetAuditLog(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	require.NoError(t, repo.Delete(ctx, game.ID()))
+	require.NoError(t, repo.Save(ctx, game))
+	list, err = repo.GetAuditLog(ctx, game.ID())
+	require.NoError(t, err)
+	assert.Empty(t, list, "the trail should be deleted with the game")
+}
+
+func BenchmarkMemoryGameRepository_GetActiveGamesByPlayerID(b *testing.B) {
+	ctx := context.Background()
+	for _, n := range []int{100, 1000, 10000} {
+		b.Run(fmt.Sprintf("games=%d", n), func(b *testing.B) {
+			repo := repository.NewMemoryGameRepository(eventBusStub{}, test.NewLogger())
+			deck, err := games.NewCardsDeck("deck", []games.Card{"XS", "S"})
+			require.NoError(b, err)
+
+			for i := 0; i < n; i++ {
+				cmd, err := games.NewCreateGameCommand("game", "", "", *deck, true)
+				require.NoError(b, err)
+				game := games.NewGame(*cmd)
+
+				// every game has its own players and only one game has the benchmarked player
+				playerID := fmt.Sprintf("player-%d", i)
+				if i == n/2 {
+					playerID = test.User1
+				}
+				join, err := games.NewJoinGameCommand(game.ID(), playerID)
+				require.NoError(b, err)
+				require.NoError(b, game.Join(*join))
+				require.NoError(b, repo.Save(ctx, game))
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				list, err := repo.GetActiveGamesByPlayerID(ctx, test.User1)
+				if err != nil || len(list) != 1 {
+					b.Fatalf("unexpected result: %d games, %v", len(list), err)
+				}
+			}
+		})
+	}
+}
+
+func assertActiveGames(t *testing.T, repo *repository.MemoryGameRepository, playerID string, gameIDs ...string) {
+	t.Helper()
+	ctx := context.Background()
+
+	list, err := repo.GetActiveGamesByPlayerID(ctx, playerID)
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(list))
+	for _, g := range list {
+		ids = append(ids, g.ID())
+	}
+	assert.ElementsMatch(t, gameIDs, ids)
+}
+
+type eventBusStub struct{}
+
+func (e eventBusStub) Publish(context.Context, events.DomainEvent) error {
+	return nil
+}
+
+func (e eventBusStub) Subscribe(events.Consumer, ...string) {
+}
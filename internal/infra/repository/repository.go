@@ -1,2 +1,3 @@
-// Package repository contains in-memory repositories implementation for all aggregates.
+// Package repository contains in-memory repositories implementation for all aggregates
+// and a file snapshot persisting them between restarts.
 package repository
@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/rooms"
+	"planningpoker/internal/infra/logging"
+)
+
+type roomSettingsDTO struct {
+	CardsDeck         cardsDeckDTO `json:"cards_deck"`
+	EveryoneCanReveal bool         `json:"everyone_can_reveal"`
+}
+
+type sessionDTO struct {
+	GameID    string    `json:"game_id"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+type roomDTO struct {
+	ID        string               `json:"id"`
+	Slug      string               `json:"slug"`
+	Name      string               `json:"name"`
+	OwnerID   string               `json:"owner_id"`
+	Members   map[string]time.Time `json:"members"`
+	Settings  roomSettingsDTO      `json:"settings"`
+	Sessions  []sessionDTO         `json:"sessions"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+func newRoomDTO(room *rooms.Room) roomDTO {
+	dto := roomDTO{
+		ID:      room.ID(),
+		Slug:    room.Slug(),
+		Name:    room.Name(),
+		OwnerID: room.OwnerID(),
+		Members: make(map[string]time.Time, len(room.Members())),
+		Settings: roomSettingsDTO{
+			CardsDeck:         newCardsDeckDTO(room.Settings().CardsDeck),
+			EveryoneCanReveal: room.Settings().EveryoneCanReveal,
+		},
+		Sessions:  make([]sessionDTO, len(room.Sessions())),
+		CreatedAt: room.CreatedAt(),
+	}
+
+	for id, joinedAt := range room.Members() {
+		dto.Members[id] = joinedAt
+	}
+	for i, s := range room.Sessions() {
+		dto.Sessions[i] = sessionDTO{GameID: s.GameID, StartedAt: s.StartedAt}
+	}
+
+	return dto
+}
+
+func (d roomDTO) toDomain() (*rooms.Room, error) {
+	deck, err := d.Settings.CardsDeck.toDomain()
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[string]time.Time, len(d.Members))
+	for id, joinedAt := range d.Members {
+		members[id] = joinedAt
+	}
+
+	sessions := make([]rooms.Session, len(d.Sessions))
+	for i, s := range d.Sessions {
+		sessions[i] = rooms.Session{GameID: s.GameID, StartedAt: s.StartedAt}
+	}
+
+	settings := rooms.Settings{CardsDeck: *deck, EveryoneCanReveal: d.Settings.EveryoneCanReveal}
+
+	return rooms.NewRaw(d.ID, d.Slug, d.Name, d.OwnerID, members, settings, sessions, d.CreatedAt), nil
+}
+
+// MemoryRoomRepository is a simple in-memory rooms repository with a unique slug index.
+type MemoryRoomRepository struct {
+	tm    sync.Mutex
+	m     sync.RWMutex
+	rooms map[string][]byte
+	// slugs maps room slugs to room IDs.
+	slugs map[string]string
+	// sessionRooms maps session game IDs to room IDs.
+	sessionRooms map[string]string
+	// roomSessions keeps indexed session game IDs of each room, so the index can be cleaned up on changes.
+	roomSessions map[string][]string
+	eventBus     events.EventBus
+	logger       *logrus.Entry
+}
+
+// NewMemoryRoomRepository creates a new in-memory repository instance.
+func NewMemoryRoomRepository(bus events.EventBus, logger *logrus.Entry) *MemoryRoomRepository {
+	return &MemoryRoomRepository{
+		rooms:        make(map[string][]byte),
+		slugs:        make(map[string]string),
+		sessionRooms: make(map[string]string),
+		roomSessions: make(map[string][]string),
+		eventBus:     bus,
+		logger:       logger,
+	}
+}
+
+// ModifyExclusively does exclusive blocking modification, so no other goroutines can modify rooms concurrently.
+func (r *MemoryRoomRepository) ModifyExclusively(ctx context.Context, id string, cb func(*rooms.Room) error) error {
+	r.tm.Lock()
+	defer r.tm.Unlock()
+
+	room, err := r.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("room fetching: %w", err)
+	}
+	if room == nil {
+		return rooms.ErrRoomNotFound
+	}
+
+	if err := cb(room); err != nil {
+		return err
+	}
+
+	if err := r.Save(ctx, room); err != nil {
+		return fmt.Errorf("room save: %w", err)
+	}
+
+	return nil
+}
+
+// Save persists the room, the slug index is checked and updated atomically with the room itself.
+func (r *MemoryRoomRepository) Save(ctx context.Context, room *rooms.Room) error {
+	raw, err := json.Marshal(newRoomDTO(room))
+	if err != nil {
+		return err
+	}
+
+	r.m.Lock()
+	if id, ok := r.slugs[room.Slug()]; ok && id != room.ID() {
+		r.m.Unlock()
+		return rooms.ErrSlugTaken
+	}
+	r.rooms[room.ID()] = raw
+	r.slugs[room.Slug()] = room.ID()
+	r.indexSessions(room.ID(), room.Sessions())
+	r.m.Unlock()
+
+	for _, e := range room.GetEvents() {
+		if err := r.eventBus.Publish(ctx, e); err != nil {
+			// this is a simple handler, which provides at most once delivery
+			// in case if a bus is broken, it will log the error and continue
+			r.logger.WithContext(ctx).WithError(err).WithField(logging.FieldRoomID, room.ID()).Error("unable to publish a room event")
+		}
+	}
+
+	return nil
+}
+
+// Get retrieves the room.
+func (r *MemoryRoomRepository) Get(_ context.Context, id string) (*rooms.Room, error) {
+	r.m.RLock()
+	raw, ok := r.rooms[id]
+	r.m.RUnlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	dto := roomDTO{}
+	if err := json.Unmarshal(raw, &dto); err != nil {
+		return nil, err
+	}
+
+	return dto.toDomain()
+}
+
+// GetBySlug returns the room with the slug, nil if there is no such room.
+func (r *MemoryRoomRepository) GetBySlug(ctx context.Context, slug string) (*rooms.Room, error) {
+	r.m.RLock()
+	id, ok := r.slugs[slug]
+	r.m.RUnlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	return r.Get(ctx, id)
+}
+
+// GetBySessionGameID returns the room which remembers the game as its session, nil if there is no such room.
+func (r *MemoryRoomRepository) GetBySessionGameID(ctx context.Context, gameID string) (*rooms.Room, error) {
+	r.m.RLock()
+	id, ok := r.sessionRooms[gameID]
+	r.m.RUnlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	return r.Get(ctx, id)
+}
+
+// GetRoomsByMemberID returns all rooms the user opened.
+func (r *MemoryRoomRepository) GetRoomsByMemberID(_ context.Context, userID string) ([]rooms.Room, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	list := make([]rooms.Room, 0)
+	for id, raw := range r.rooms {
+		dto := roomDTO{}
+		if err := json.Unmarshal(raw, &dto); err != nil {
+			return nil, fmt.Errorf("room %s: %w", id, err)
+		}
+		if _, ok := dto.Members[userID]; !ok {
+			continue
+		}
+
+		room, err := dto.toDomain()
+		if err != nil {
+			return nil, fmt.Errorf("room %s: %w", id, err)
+		}
+		list = append(list, *room)
+	}
+
+	return list, nil
+}
+
+// dump returns a copy of all the stored rooms.
+func (r *MemoryRoomRepository) dump() map[string]json.RawMessage {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	out := make(map[string]json.RawMessage, len(r.rooms))
+	for id, raw := range r.rooms {
+		out[id] = raw
+	}
+
+	return out
+}
+
+// restore replaces all the stored rooms and rebuilds the slug and session indexes, no events are published.
+func (r *MemoryRoomRepository) restore(list map[string]json.RawMessage) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.rooms = make(map[string][]byte, len(list))
+	r.slugs = make(map[string]string, len(list))
+	r.sessionRooms = make(map[string]string)
+	r.roomSessions = make(map[string][]string, len(list))
+	for id, raw := range list {
+		dto := roomDTO{}
+		if err := json.Unmarshal(raw, &dto); err != nil {
+			return fmt.Errorf("room %s: %w", id, err)
+		}
+		if other, ok := r.slugs[dto.Slug]; ok {
+			return fmt.Errorf("room %s: slug %q is taken by room %s", id, dto.Slug, other)
+		}
+		room, err := dto.toDomain()
+		if err != nil {
+			return fmt.Errorf("room %s: %w", id, err)
+		}
+		r.rooms[id] = raw
+		r.slugs[dto.Slug] = id
+		r.indexSessions(id, room.Sessions())
+	}
+
+	return nil
+}
+
+// indexSessions replaces indexed session games of the room, it should be called under the write lock.
+func (r *MemoryRoomRepository) indexSessions(roomID string, sessions []rooms.Session) {
+	for _, gameID := range r.roomSessions[roomID] {
+		delete(r.sessionRooms, gameID)
+	}
+
+	ids := make([]string, len(sessions))
+	for i, s := range sessions {
+		ids[i] = s.GameID
+		r.sessionRooms[s.GameID] = roomID
+	}
+	r.roomSessions[roomID] = ids
+}
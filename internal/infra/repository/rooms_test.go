@@ -0,0 +1,44 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/rooms"
+	"planningpoker/internal/infra/repository"
+	"planningpoker/test"
+)
+
+func TestMemoryRoomRepository_Contract(t *testing.T) {
+	t.Parallel()
+
+	test.RoomRepositoryContract(t, func(bus events.EventBus) rooms.Repository {
+		return repository.NewMemoryRoomRepository(bus, test.NewLogger())
+	})
+}
+
+func TestMemoryRoomRepository_GetBySessionGameID(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	repo := repository.NewMemoryRoomRepository(&test.EventsRecorder{}, test.NewLogger())
+	room := test.NewRoom(t, "slug", test.User1)
+	for i := 0; i < rooms.MaxSessions+1; i++ {
+		room.StartSession(fmt.Sprintf("game-%d", i))
+	}
+	require.NoError(t, repo.Save(ctx, room))
+
+	got, err := repo.GetBySessionGameID(ctx, "game-1")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, room.ID(), got.ID())
+
+	got, err = repo.GetBySessionGameID(ctx, "game-0")
+	require.NoError(t, err)
+	assert.Nil(t, got, "forgotten sessions should leave the index")
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"planningpoker/internal/domain/games"
+)
+
+type roundVoteDTO struct {
+	UserID     string            `json:"user_id"`
+	Card       string            `json:"card"`
+	Cards      map[string]string `json:"cards,omitempty"`
+	Confidence string            `json:"confidence,omitempty"`
+}
+
+type roundDTO struct {
+	TeamID     string         `json:"team_id,omitempty"`
+	TicketName string         `json:"ticket_name"`
+	TicketURL  string         `json:"ticket_url,omitempty"`
+	Cards      []string       `json:"cards"`
+	Votes      []roundVoteDTO `json:"votes"`
+	RevealedAt time.Time      `json:"revealed_at"`
+	Anonymous  bool           `json:"anonymous,omitempty"`
+}
+
+func newRoundDTO(round games.Round) roundDTO {
+	dto := roundDTO{
+		TeamID:     round.TeamID,
+		TicketName: round.TicketName,
+		TicketURL:  round.TicketURL,
+		Cards:      cardTypes(round.Cards),
+		Votes:      make([]roundVoteDTO, len(round.Votes)),
+		RevealedAt: round.RevealedAt,
+		Anonymous:  round.Anonymous,
+	}
+
+	for i, v := range round.Votes {
+		dto.Votes[i] = roundVoteDTO{
+			UserID: v.UserID, Card: v.Card.Type(), Cards: newVotedCardsDTO(v.Cards), Confidence: v.Confidence,
+		}
+	}
+
+	return dto
+}
+
+func (d roundDTO) toDomain(gameID string) games.Round {
+	round := games.Round{
+		GameID:     gameID,
+		TeamID:     d.TeamID,
+		TicketName: d.TicketName,
+		TicketURL:  d.TicketURL,
+		Cards:      make([]games.Card, len(d.Cards)),
+		Votes:      make([]games.RoundVote, len(d.Votes)),
+		RevealedAt: d.RevealedAt,
+		Anonymous:  d.Anonymous,
+	}
+
+	for i, c := range d.Cards {
+		round.Cards[i] = games.Card(c)
+	}
+	for i, v := range d.Votes {
+		round.Votes[i] = games.RoundVote{
+			UserID: v.UserID, Card: games.Card(v.Card), Cards: votedCardsToDomain(v.Cards), Confidence: v.Confidence,
+		}
+	}
+
+	return round
+}
+
+// MemoryRoundRepository is a simple in-memory history of revealed rounds.
+// Unlike the audit trail, rounds are kept after their games are deleted until they are deleted explicitly,
+// so room reports cover past sessions.
+type MemoryRoundRepository struct {
+	m      sync.RWMutex
+	rounds map[string][]roundDTO
+}
+
+// NewMemoryRoundRepository creates a new in-memory repository instance.
+func NewMemoryRoundRepository() *MemoryRoundRepository {
+	return &MemoryRoundRepository{
+		rounds: make(map[string][]roundDTO),
+	}
+}
+
+// AppendRound adds the round to the game history.
+func (r *MemoryRoundRepository) AppendRound(_ context.Context, round games.Round) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.rounds[round.GameID] = append(r.rounds[round.GameID], newRoundDTO(round))
+
+	return nil
+}
+
+// DeleteRounds removes the history of the game.
+func (r *MemoryRoundRepository) DeleteRounds(_ context.Context, gameID string) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	delete(r.rounds, gameID)
+
+	return nil
+}
+
+// GetRoundsByGameIDs returns rounds of all the games in the order of appending.
+func (r *MemoryRoundRepository) GetRoundsByGameIDs(_ context.Context, gameIDs []string) ([]games.Round, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	list := make([]games.Round, 0)
+	seen := make(map[string]bool, len(gameIDs))
+	for _, id := range gameIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		for _, dto := range r.rounds[id] {
+			list = append(list, dto.toDomain(id))
+		}
+	}
+
+	return list, nil
+}
+
+// dump returns encoded histories of all the games.
+func (r *MemoryRoundRepository) dump() (map[string]json.RawMessage, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	out := make(map[string]json.RawMessage, len(r.rounds))
+	for id, list := range r.rounds {
+		raw, err := json.Marshal(list)
+		if err != nil {
+			return nil, fmt.Errorf("game %s rounds: %w", id, err)
+		}
+		out[id] = raw
+	}
+
+	return out, nil
+}
+
+// restore replaces histories of all the games.
+func (r *MemoryRoundRepository) restore(list map[string]json.RawMessage) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.rounds = make(map[string][]roundDTO, len(list))
+	for id, raw := range list {
+		var rounds []roundDTO
+		if err := json.Unmarshal(raw, &rounds); err != nil {
+			return fmt.Errorf("game %s rounds: %w", id, err)
+		}
+		r.rounds[id] = rounds
+	}
+
+	return nil
+}
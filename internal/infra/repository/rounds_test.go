@@ -0,0 +1,51 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/infra/repository"
+	"planningpoker/test"
+)
+
+func TestMemoryRoundRepository(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	repo := repository.NewMemoryRoundRepository()
+	first := games.Round{
+		GameID:     "g1",
+		TeamID:     "team",
+		TicketName: "ticket",
+		TicketURL:  "https://example.com",
+		Cards:      []games.Card{"1", "2"},
+		Votes:      []games.RoundVote{{UserID: test.User1, Card: "2", Confidence: games.ConfidenceHigh}},
+		RevealedAt: time.Now(),
+	}
+	require.NoError(t, repo.AppendRound(ctx, first))
+	require.NoError(t, repo.AppendRound(ctx, games.Round{GameID: "g2", TicketName: "second"}))
+	require.NoError(t, repo.AppendRound(ctx, games.Round{GameID: "g3", TicketName: "other"}))
+
+	list, err := repo.GetRoundsByGameIDs(ctx, []string{"g1", "g2", "g1", "unknown"})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, first.GameID, list[0].GameID)
+	assert.Equal(t, first.TeamID, list[0].TeamID)
+	assert.Equal(t, first.TicketName, list[0].TicketName)
+	assert.Equal(t, first.TicketURL, list[0].TicketURL)
+	assert.Equal(t, first.Cards, list[0].Cards)
+	assert.Equal(t, first.Votes, list[0].Votes)
+	assert.True(t, first.RevealedAt.Equal(list[0].RevealedAt))
+	assert.Equal(t, "second", list[1].TicketName)
+
+	require.NoError(t, repo.DeleteRounds(ctx, "g1"))
+	list, err = repo.GetRoundsByGameIDs(ctx, []string{"g1", "g2"})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "g2", list[0].GameID)
+}
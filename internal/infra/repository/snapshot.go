@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const snapshotVersion = 1
+
+type snapshotDTO struct {
+	Version int                        `json:"version"`
+	Games   map[string]json.RawMessage `json:"games"`
+	Users   map[string]json.RawMessage `json:"users"`
+	// Audit contains trails of games, it is missing in snapshots made before the audit was introduced.
+	Audit map[string]json.RawMessage `json:"audit,omitempty"`
+	// Teams are missing in snapshots made before teams were introduced.
+	Teams map[string]json.RawMessage `json:"teams,omitempty"`
+	// Rooms are missing in snapshots made before rooms were introduced.
+	Rooms map[string]json.RawMessage `json:"rooms,omitempty"`
+	// Rounds are missing in snapshots made before the round history was introduced.
+	Rounds map[string]json.RawMessage `json:"rounds,omitempty"`
+	// Batches are missing in snapshots made before asynchronous voting was introduced.
+	Batches map[string]json.RawMessage `json:"batches,omitempty"`
+}
+
+// FileSnapshot persists in-memory repositories into a single JSON file, so data survives restarts.
+type FileSnapshot struct {
+	path    string
+	games   *MemoryGameRepository
+	users   *MemoryUserRepository
+	teams   *MemoryTeamRepository
+	rooms   *MemoryRoomRepository
+	rounds  *MemoryRoundRepository
+	batches *MemoryBatchRepository
+}
+
+// NewFileSnapshot creates a new snapshot of the provided repositories stored at the path.
+func NewFileSnapshot(
+	path string,
+	gr *MemoryGameRepository,
+	ur *MemoryUserRepository,
+	tr *MemoryTeamRepository,
+	rr *MemoryRoomRepository,
+	rdr *MemoryRoundRepository,
+	br *MemoryBatchRepository,
+) (*FileSnapshot, error) {
+	if path == "" {
+		return nil, errors.New("snapshot path should be provided")
+	}
+
+	if gr == nil {
+		return nil, errors.New("games repository should be provided")
+	}
+
+	if ur == nil {
+		return nil, errors.New("users repository should be provided")
+	}
+
+	if tr == nil {
+		return nil, errors.New("teams repository should be provided")
+	}
+
+	if rr == nil {
+		return nil, errors.New("rooms repository should be provided")
+	}
+
+	if rdr == nil {
+		return nil, errors.New("rounds repository should be provided")
+	}
+
+	if br == nil {
+		return nil, errors.New("batches repository should be provided")
+	}
+
+	return &FileSnapshot{
+		path:    path,
+		games:   gr,
+		users:   ur,
+		teams:   tr,
+		rooms:   rr,
+		rounds:  rdr,
+		batches: br,
+	}, nil
+}
+
+// Restore loads the repositories from the snapshot file, a missing file is not an error.
+func (s *FileSnapshot) Restore() error {
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("snapshot reading: %w", err)
+	}
+
+	dto := snapshotDTO{}
+	if err := json.Unmarshal(raw, &dto); err != nil {
+		return fmt.Errorf("snapshot decoding: %w", err)
+	}
+
+	if dto.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", dto.Version)
+	}
+
+	if err := s.games.restore(dto.Games); err != nil {
+		return fmt.Errorf("games restoring: %w", err)
+	}
+
+	if err := s.games.restoreAudit(dto.Audit); err != nil {
+		return fmt.Errorf("audit restoring: %w", err)
+	}
+
+	if err := s.users.restore(dto.Users); err != nil {
+		return fmt.Errorf("users restoring: %w", err)
+	}
+
+	if err := s.teams.restore(dto.Teams); err != nil {
+		return fmt.Errorf("teams restoring: %w", err)
+	}
+
+	if err := s.rooms.restore(dto.Rooms); err != nil {
+		return fmt.Errorf("rooms restoring: %w", err)
+	}
+
+	if err := s.rounds.restore(dto.Rounds); err != nil {
+		return fmt.Errorf("rounds restoring: %w", err)
+	}
+
+	if err := s.batches.restore(dto.Batches); err != nil {
+		return fmt.Errorf("batches restoring: %w", err)
+	}
+
+	return nil
+}
+
+// Flush writes the current repositories state to the snapshot file.
+// The file is replaced atomically, so a crash during flush never corrupts the previous snapshot.
+func (s *FileSnapshot) Flush() error {
+	auditLog, err := s.games.dumpAudit()
+	if err != nil {
+		return fmt.Errorf("audit encoding: %w", err)
+	}
+
+	rounds, err := s.rounds.dump()
+	if err != nil {
+		return fmt.Errorf("rounds encoding: %w", err)
+	}
+
+	raw, err := json.Marshal(snapshotDTO{
+		Version: snapshotVersion,
+		Games:   s.games.dump(),
+		Users:   s.users.dump(),
+		Audit:   auditLog,
+		Teams:   s.teams.dump(),
+		Rooms:   s.rooms.dump(),
+		Rounds:  rounds,
+		Batches: s.batches.dump(),
+	})
+	if err != nil {
+		return fmt.Errorf("snapshot encoding: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("snapshot file creation: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("snapshot writing: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("snapshot syncing: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("snapshot closing: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("snapshot replacing: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"planningpoker/internal/domain/audit"
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/users"
+	"planningpoker/internal/infra/repository"
+	"planningpoker/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSnapshot_FlushAndRestore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "poker.json")
+
+	gr := repository.NewMemoryGameRepository(eventBusStub{}, test.NewLogger())
+	ur := repository.NewMemoryUserRepository(eventBusStub{}, test.NewLogger())
+	game := test.NewTestGame(t, test.NewSimpleGame(t, true)).UserJoins(test.User1).Instance()
+	user := users.NewRaw(test.User1, "name", time.Now())
+	require.NoError(t, gr.Save(ctx, game))
+	require.NoError(t, ur.Save(ctx, *user))
+	require.NoError(t, gr.AppendAuditEntry(ctx, audit.Entry{GameID: game.ID(), Action: games.ActionJoin, ActorName: "name"}))
+	tr := repository.NewMemoryTeamRepository(eventBusStub{}, test.NewLogger())
+	team := test.NewTeam(t, test.User1)
+	require.NoError(t, tr.Save(ctx, team))
+	rr := repository.NewMemoryRoomRepository(eventBusStub{}, test.NewLogger())
+	room := test.NewRoom(t, "payments-team", test.User1)
+	room.StartSession(game.ID())
+	require.NoError(t, rr.Save(ctx, room))
+	rdr := repository.NewMemoryRoundRepository()
+	round := games.Round{GameID: game.ID(), TicketName: "ticket", Cards: []games.Card{"1"}, RevealedAt: time.Now()}
+	require.NoError(t, rdr.AppendRound(ctx, round))
+	br := repository.NewMemoryBatchRepository(eventBusStub{}, test.NewLogger())
+	batch := test.NewBatch(t, test.User1, "ticket")
+	require.NoError(t, br.Save(ctx, batch))
+
+	snapshot, err := repository.NewFileSnapshot(path, gr, ur, tr, rr, rdr, br)
+	require.NoError(t, err)
+	require.NoError(t, snapshot.Flush())
+
+	restoredGames := repository.NewMemoryGameRepository(eventBusStub{}, test.NewLogger())
+	restoredUsers := repository.NewMemoryUserRepository(eventBusStub{}, test.NewLogger())
+	restoredTeams := repository.NewMemoryTeamRepository(eventBusStub{}, test.NewLogger())
+	restoredRooms := repository.NewMemoryRoomRepository(eventBusStub{}, test.NewLogger())
+	restoredRounds := repository.NewMemoryRoundRepository()
+	restoredBatches := repository.NewMemoryBatchRepository(eventBusStub{}, test.NewLogger())
+	restored, err := repository.NewFileSnapshot(
+		path, restoredGames, restoredUsers, restoredTeams, restoredRooms, restoredRounds, restoredBatches,
+	)
+	require.NoError(t, err)
+	require.NoError(t, restored.Restore())
+
+	gotGame, err := restoredGames.Get(ctx, game.ID())
+	require.NoError(t, err)
+	require.NotNil(t, gotGame)
+	assert.Equal(t, game.Players(), gotGame.Players())
+	assertActiveGames(t, restoredGames, test.User1, game.ID())
+	counts, err := restoredGames.GetActiveGamesPlayersCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, counts)
+
+	trail, err := restoredGames.GetAuditLog(ctx, game.ID())
+	require.NoError(t, err)
+	require.Len(t, trail, 1)
+	assert.Equal(t, "name", trail[0].ActorName)
+
+	gotUser, err := restoredUsers.Get(ctx, user.ID())
+	require.NoError(t, err)
+	require.NotNil(t, gotUser)
+	assert.Equal(t, user.Name(), gotUser.Name())
+
+	gotTeam, err := restoredTeams.Get(ctx, team.ID())
+	require.NoError(t, err)
+	require.NotNil(t, gotTeam)
+	assert.Equal(t, team.MemberIDs(), gotTeam.MemberIDs())
+	assert.Equal(t, team.JoinCode(), gotTeam.JoinCode())
+
+	gotRoom, err := restoredRooms.GetBySlug(ctx, room.Slug())
+	require.NoError(t, err)
+	require.NotNil(t, gotRoom)
+	assert.Equal(t, room.ID(), gotRoom.ID())
+	gotRoom, err = restoredRooms.GetBySessionGameID(ctx, game.ID())
+	require.NoError(t, err)
+	require.NotNil(t, gotRoom)
+	assert.Equal(t, room.ID(), gotRoom.ID())
+
+	gotRounds, err := restoredRounds.GetRoundsByGameIDs(ctx, []string{game.ID()})
+	require.NoError(t, err)
+	require.Len(t, gotRounds, 1)
+	assert.Equal(t, round.TicketName, gotRounds[0].TicketName)
+
+	gotBatch, err := restoredBatches.Get(ctx, batch.ID())
+	require.NoError(t, err)
+	require.NotNil(t, gotBatch)
+	assert.Equal(t, batch.Tickets()[0].ID, gotBatch.Tickets()[0].ID)
+}
+
+func TestFileSnapshot_RestoresPlainDecks(t *testing.T) {
+	t.Parallel()
+
+	// games are stored in the same shape as before dimensional decks were introduced
+	content := `{"version": 1, "games": {"game-id": {
+		"id": "game-id", "name": "name", "ticket_url": "", "state": "finished",
+		"cards_deck": {"name": "T-shirt", "cards": ["XS", "S"]},
+		"players": {"user-id": {"voted_card": "S", "can_reveal": true, "confidence": "normal", "active": true}},
+		"everyone_can_reveal": true, "facilitator_id": "user-id", "last_activity_at": "2024-01-01T00:00:00Z"
+	}}, "users": {}}`
+	path := filepath.Join(t.TempDir(), "poker.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	gr := repository.NewMemoryGameRepository(eventBusStub{}, test.NewLogger())
+	snapshot, err := repository.NewFileSnapshot(
+		path,
+		gr,
+		repository.NewMemoryUserRepository(eventBusStub{}, test.NewLogger()),
+		repository.NewMemoryTeamRepository(eventBusStub{}, test.NewLogger()),
+		repository.NewMemoryRoomRepository(eventBusStub{}, test.NewLogger()),
+		repository.NewMemoryRoundRepository(),
+		repository.NewMemoryBatchRepository(eventBusStub{}, test.NewLogger()),
+	)
+	require.NoError(t, err)
+	require.NoError(t, snapshot.Restore())
+
+	game, err := gr.Get(context.Background(), "game-id")
+	require.NoError(t, err)
+	require.NotNil(t, game)
+	assert.False(t, game.CardsDeck().IsDimensional())
+	assert.Equal(t, []games.Card{"XS", "S"}, game.CardsDeck().Cards())
+	require.NotNil(t, game.Players()["user-id"].VotedCard)
+	assert.Equal(t, games.Card("S"), *game.Players()["user-id"].VotedCard)
+	assert.Nil(t, game.Players()["user-id"].VotedCards)
+}
+
+func TestFileSnapshot_Restore(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		content  string
+		expError string
+	}{
+		"missing file": {},
+		"broken file": {
+			content:  "{",
+			expError: "snapshot decoding: unexpected end of JSON input",
+		},
+		"unknown version": {
+			content:  `{"version": 100}`,
+			expError: "unsupported snapshot version 100",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "poker.json")
+			if tt.content != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			}
+
+			snapshot, err := repository.NewFileSnapshot(
+				path,
+				repository.NewMemoryGameRepository(eventBusStub{}, test.NewLogger()),
+				repository.NewMemoryUserRepository(eventBusStub{}, test.NewLogger()),
+				repository.NewMemoryTeamRepository(eventBusStub{}, test.NewLogger()),
+				repository.NewMemoryRoomRepository(eventBusStub{}, test.NewLogger()),
+				repository.NewMemoryRoundRepository(),
+				repository.NewMemoryBatchRepository(eventBusStub{}, test.NewLogger()),
+			)
+			require.NoError(t, err)
+
+			err = snapshot.Restore()
+			if tt.expError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expError)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/teams"
+	"planningpoker/internal/infra/logging"
+)
+
+type memberDTO struct {
+	Role     string    `json:"role"`
+	Handle   string    `json:"handle"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type teamSettingsDTO struct {
+	CardsDeck         cardsDeckDTO `json:"cards_deck"`
+	EveryoneCanReveal bool         `json:"everyone_can_reveal"`
+}
+
+type teamDTO struct {
+	ID        string               `json:"id"`
+	Name      string               `json:"name"`
+	Members   map[string]memberDTO `json:"members"`
+	Settings  teamSettingsDTO      `json:"settings"`
+	JoinCode  string               `json:"join_code"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+func newTeamDTO(team *teams.Team) teamDTO {
+	dto := teamDTO{
+		ID:      team.ID(),
+		Name:    team.Name(),
+		Members: make(map[string]memberDTO, len(team.Members())),
+		Settings: teamSettingsDTO{
+			CardsDeck:         newCardsDeckDTO(team.Settings().CardsDeck),
+			EveryoneCanReveal: team.Settings().EveryoneCanReveal,
+		},
+		JoinCode:  team.JoinCode(),
+		CreatedAt: team.CreatedAt(),
+	}
+
+	for id, m := range team.Members() {
+		dto.Members[id] = memberDTO{Role: m.Role, Handle: m.Handle, JoinedAt: m.JoinedAt}
+	}
+
+	return dto
+}
+
+func (d teamDTO) toDomain() (*teams.Team, error) {
+	deck, err := d.Settings.CardsDeck.toDomain()
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[string]*teams.Member, len(d.Members))
+	for id, m := range d.Members {
+		members[id] = &teams.Member{Role: m.Role, Handle: m.Handle, JoinedAt: m.JoinedAt}
+	}
+
+	settings := teams.Settings{CardsDeck: *deck, EveryoneCanReveal: d.Settings.EveryoneCanReveal}
+
+	return teams.NewRaw(d.ID, d.Name, members, settings, d.JoinCode, d.CreatedAt), nil
+}
+
+// MemoryTeamRepository is a simple in-memory linear teams repository.
+type MemoryTeamRepository struct {
+	tm       sync.Mutex
+	m        sync.RWMutex
+	teams    map[string][]byte
+	eventBus events.EventBus
+	logger   *logrus.Entry
+}
+
+// NewMemoryTeamRepository creates a new in-memory repository instance.
+func NewMemoryTeamRepository(bus events.EventBus, logger *logrus.Entry) *MemoryTeamRepository {
+	return &MemoryTeamRepository{
+		teams:    make(map[string][]byte),
+		eventBus: bus,
+		logger:   logger,
+	}
+}
+
+// ModifyExclusively does exclusive blocking modification, so no other goroutines can modify teams concurrently.
+func (r *MemoryTeamRepository) ModifyExclusively(ctx context.Context, id string, cb func(*teams.Team) error) error {
+	r.tm.Lock()
+	defer r.tm.Unlock()
+
+	team, err := r.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("team fetching: %w", err)
+	}
+	if team == nil {
+		return teams.ErrTeamNotFound
+	}
+
+	if err := cb(team); err != nil {
+		return err
+	}
+
+	if err := r.Save(ctx, team); err != nil {
+		return fmt.Errorf("team save: %w", err)
+	}
+
+	return nil
+}
+
+// Save persists the team.
+func (r *MemoryTeamRepository) Save(ctx context.Context, team *teams.Team) error {
+	raw, err := json.Marshal(newTeamDTO(team))
+	if err != nil {
+		return err
+	}
+
+	r.m.Lock()
+	r.teams[team.ID()] = raw
+	r.m.Unlock()
+
+	for _, e := range team.GetEvents() {
+		if err := r.eventBus.Publish(ctx, e); err != nil {
+			// this is a simple handler, which provides at most once delivery
+			// in case if a bus is broken, it will log the error and continue
+			r.logger.WithContext(ctx).WithError(err).WithField(logging.FieldTeamID, team.ID()).Error("unable to publish a team event")
+		}
+	}
+
+	return nil
+}
+
+// Get retrieves the team.
+func (r *MemoryTeamRepository) Get(_ context.Context, id string) (*teams.Team, error) {
+	r.m.RLock()
+	raw, ok := r.teams[id]
+	r.m.RUnlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	dto := teamDTO{}
+	if err := json.Unmarshal(raw, &dto); err != nil {
+		return nil, err
+	}
+
+	return dto.toDomain()
+}
+
+// GetByJoinCode returns the team with the join code, nil if there is no such team.
+func (r *MemoryTeamRepository) GetByJoinCode(_ context.Context, joinCode string) (*teams.Team, error) {
+	var found *teams.Team
+	err := r.each(func(dto teamDTO) (bool, error) {
+		if dto.JoinCode != joinCode {
+			return true, nil
+		}
+		team, err := dto.toDomain()
+		found = team
+		return false, err
+	})
+
+	return found, err
+}
+
+// GetTeamsByMemberID returns all teams of the user.
+func (r *MemoryTeamRepository) GetTeamsByMemberID(_ context.Context, userID string) ([]teams.Team, error) {
+	list := make([]teams.Team, 0)
+	err := r.each(func(dto teamDTO) (bool, error) {
+		if _, ok := dto.Members[userID]; !ok {
+			return true, nil
+		}
+		team, err := dto.toDomain()
+		if err != nil {
+			return false, err
+		}
+		list = append(list, *team)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// each decodes stored teams one by one until the callback asks to stop or fails.
+func (r *MemoryTeamRepository) each(cb func(dto teamDTO) (bool, error)) error {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	for id, raw := range r.teams {
+		dto := teamDTO{}
+		if err := json.Unmarshal(raw, &dto); err != nil {
+			return fmt.Errorf("team %s: %w", id, err)
+		}
+		next, err := cb(dto)
+		if err != nil {
+			return fmt.Errorf("team %s: %w", id, err)
+		}
+		if !next {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// dump returns a copy of all the stored teams.
+func (r *MemoryTeamRepository) dump() map[string]json.RawMessage {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	out := make(map[string]json.RawMessage, len(r.teams))
+	for id, raw := range r.teams {
+		out[id] = raw
+	}
+
+	return out
+}
+
+// restore replaces all the stored teams, no events are published.
+func (r *MemoryTeamRepository) restore(list map[string]json.RawMessage) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.teams = make(map[string][]byte, len(list))
+	for id, raw := range list {
+		if !json.Valid(raw) {
+			return fmt.Errorf("team %s: invalid json", id)
+		}
+		r.teams[id] = raw
+	}
+
+	return nil
+}
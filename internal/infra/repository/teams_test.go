@@ -0,0 +1,18 @@
+package repository_test
+
+import (
+	"testing"
+
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/teams"
+	"planningpoker/internal/infra/repository"
+	"planningpoker/test"
+)
+
+func TestMemoryTeamRepository_Contract(t *testing.T) {
+	t.Parallel()
+
+	test.TeamRepositoryContract(t, func(bus events.EventBus) teams.Repository {
+		return repository.NewMemoryTeamRepository(bus, test.NewLogger())
+	})
+}
@@ -1,37 +1,46 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
+
 	"planningpoker/internal/domain/events"
 	"planningpoker/internal/domain/users"
+	"planningpoker/internal/infra/logging"
 )
 
 type userDTO struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Token string `json:"token"`
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Token      string    `json:"token"`
+	LastSeenAt time.Time `json:"last_seen_at"`
 }
 
 // MemoryUserRepository is a simple in-memory linear users repository.
 type MemoryUserRepository struct {
+	um       sync.Mutex
 	m        sync.RWMutex
 	users    map[string][]byte
 	eventBus events.EventBus
+	logger   *logrus.Entry
 }
 
 // NewMemoryUserRepository creates an in-memory users repository instance.
-func NewMemoryUserRepository(eventBus events.EventBus) *MemoryUserRepository {
+func NewMemoryUserRepository(eventBus events.EventBus, logger *logrus.Entry) *MemoryUserRepository {
 	return &MemoryUserRepository{
 		users:    make(map[string][]byte),
 		eventBus: eventBus,
+		logger:   logger,
 	}
 }
 
 // Get retrieves the user by ID.
-func (r *MemoryUserRepository) Get(id string) (*users.User, error) {
+func (r *MemoryUserRepository) Get(_ context.Context, id string) (*users.User, error) {
 	r.m.RLock()
 	raw, ok := r.users[id]
 	r.m.RUnlock()
@@ -46,14 +55,14 @@ func (r *MemoryUserRepository) Get(id string) (*users.User, error) {
 		return nil, err
 	}
 
-	return users.NewRaw(dto.ID, dto.Name), nil
+	return users.NewRaw(dto.ID, dto.Name, dto.LastSeenAt), nil
 }
 
 // GetMany retrieves many users.
-func (r *MemoryUserRepository) GetMany(ids []string) ([]users.User, error) {
+func (r *MemoryUserRepository) GetMany(ctx context.Context, ids []string) ([]users.User, error) {
 	list := make([]users.User, 0, len(ids))
 	for _, id := range ids {
-		u, err := r.Get(id)
+		u, err := r.Get(ctx, id)
 		if err != nil {
 			return nil, err
 		}
@@ -66,11 +75,36 @@ func (r *MemoryUserRepository) GetMany(ids []string) ([]users.User, error) {
 	return list, nil
 }
 
+// ModifyExclusively does exclusive blocking modification, so no other goroutines can modify users concurrently.
+func (r *MemoryUserRepository) ModifyExclusively(ctx context.Context, id string, cb func(*users.User) error) error {
+	r.um.Lock()
+	defer r.um.Unlock()
+
+	user, err := r.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("user fetching: %w", err)
+	}
+	if user == nil {
+		return users.ErrUserNotFound
+	}
+
+	if err := cb(user); err != nil {
+		return err
+	}
+
+	if err := r.Save(ctx, *user); err != nil {
+		return fmt.Errorf("user save: %w", err)
+	}
+
+	return nil
+}
+
 // Save persists the user.
-func (r *MemoryUserRepository) Save(user users.User) error {
+func (r *MemoryUserRepository) Save(ctx context.Context, user users.User) error {
 	dto := userDTO{
-		ID:   user.ID(),
-		Name: user.Name(),
+		ID:         user.ID(),
+		Name:       user.Name(),
+		LastSeenAt: user.LastSeenAt(),
 	}
 
 	raw, err := json.Marshal(dto)
@@ -83,12 +117,70 @@ func (r *MemoryUserRepository) Save(user users.User) error {
 	r.users[user.ID()] = raw
 
 	for _, e := range user.GetEvents() {
-		if err := r.eventBus.Publish(e); err != nil {
+		if err := r.eventBus.Publish(ctx, e); err != nil {
 			// this is a simple handler, which provides at most once delivery
 			// in case if a bus is broken, it will log the error and continue
 			// TODO: implement outbox pattern in order to mitigate potential distributed transactions
-			logrus.Errorf("failed to publish a game event: %v", err)
+			r.logger.WithContext(ctx).WithError(err).WithField(logging.FieldUserID, user.ID()).Error("unable to publish a user event")
+		}
+	}
+
+	return nil
+}
+
+// Delete removes the user.
+func (r *MemoryUserRepository) Delete(_ context.Context, id string) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	delete(r.users, id)
+
+	return nil
+}
+
+// GetNotSeenSince returns IDs of all users who were not seen since specific time.
+func (r *MemoryUserRepository) GetNotSeenSince(_ context.Context, since time.Time) ([]string, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	ids := make([]string, 0)
+	for id, raw := range r.users {
+		dto := userDTO{}
+		if err := json.Unmarshal(raw, &dto); err != nil {
+			return nil, err
+		}
+		if dto.LastSeenAt.Before(since) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// dump returns a copy of all the stored users.
+func (r *MemoryUserRepository) dump() map[string]json.RawMessage {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	out := make(map[string]json.RawMessage, len(r.users))
+	for id, raw := range r.users {
+		out[id] = raw
+	}
+
+	return out
+}
+
+// restore replaces all the stored users, no events are published.
+func (r *MemoryUserRepository) restore(list map[string]json.RawMessage) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.users = make(map[string][]byte, len(list))
+	for id, raw := range list {
+		if !json.Valid(raw) {
+			return fmt.Errorf("user %s: invalid json", id)
 		}
+		r.users[id] = raw
 	}
 
 	return nil
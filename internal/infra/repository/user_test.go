@@ -0,0 +1,18 @@
+package repository_test
+
+import (
+	"testing"
+
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/users"
+	"planningpoker/internal/infra/repository"
+	"planningpoker/test"
+)
+
+func TestMemoryUserRepository_Contract(t *testing.T) {
+	t.Parallel()
+
+	test.UserRepositoryContract(t, func(bus events.EventBus) users.Repository {
+		return repository.NewMemoryUserRepository(bus, test.NewLogger())
+	})
+}
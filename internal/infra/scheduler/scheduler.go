@@ -0,0 +1,53 @@
+// Package scheduler contains a background reveal of asynchronous voting tickets at their deadlines.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"planningpoker/internal/infra/periodic"
+)
+
+type batchesService interface {
+	RevealOverdue(ctx context.Context, now time.Time) error
+}
+
+// Scheduler periodically reveals tickets of batches which deadline has passed.
+// A ticket is revealed at most one interval after its deadline.
+type Scheduler struct {
+	*periodic.Runner
+	batchesService batchesService
+	logger         *logrus.Entry
+}
+
+// NewScheduler creates a new scheduler instance.
+// Deadlines which passed while the service was down are handled right away on start.
+func NewScheduler(bs batchesService, interval time.Duration, logger *logrus.Entry) (*Scheduler, error) {
+	if bs == nil {
+		return nil, errors.New("batches service should be provided")
+	}
+	if interval <= 0 {
+		return nil, errors.New("interval should be positive")
+	}
+	if logger == nil {
+		return nil, errors.New("logger should be provided")
+	}
+
+	s := &Scheduler{
+		batchesService: bs,
+		logger:         logger,
+	}
+	s.Runner = periodic.NewRunner(interval, true, s.Tick)
+
+	return s, nil
+}
+
+// Tick reveals tickets of all the batches overdue by now.
+func (s *Scheduler) Tick(now time.Time) {
+	if err := s.batchesService.RevealOverdue(context.Background(), now); err != nil {
+		s.logger.WithError(err).Error("overdue batches reveal failed")
+	}
+}
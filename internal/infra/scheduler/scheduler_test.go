@@ -0,0 +1,118 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/infra/scheduler"
+	"planningpoker/test"
+)
+
+func TestNewScheduler(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		batchesService batchesService
+		interval       time.Duration
+		logger         *logrus.Entry
+		expError       string
+	}{
+		"success": {
+			batchesService: newBatchesServiceStub(nil),
+			interval:       time.Minute,
+			logger:         test.NewLogger(),
+		},
+		"fail on no batches service": {
+			interval: time.Minute,
+			logger:   test.NewLogger(),
+			expError: "batches service should be provided",
+		},
+		"fail on not positive interval": {
+			batchesService: newBatchesServiceStub(nil),
+			logger:         test.NewLogger(),
+			expError:       "interval should be positive",
+		},
+		"fail on no logger": {
+			batchesService: newBatchesServiceStub(nil),
+			interval:       time.Minute,
+			expError:       "logger should be provided",
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := scheduler.NewScheduler(tt.batchesService, tt.interval, tt.logger)
+
+			if tt.expError != "" {
+				assert.EqualError(t, err, tt.expError)
+				assert.Nil(t, s)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, s)
+			}
+		})
+	}
+}
+
+func TestScheduler_Tick(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	for name, err := range map[string]error{"success": nil, "failed reveal is logged": errors.New("failed")} {
+		err := err
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			bs := newBatchesServiceStub(err)
+			s, err := scheduler.NewScheduler(bs, time.Minute, test.NewLogger())
+			require.NoError(t, err)
+
+			s.Tick(now)
+
+			assert.Equal(t, now, <-bs.calls)
+		})
+	}
+}
+
+func TestScheduler_StartStop(t *testing.T) {
+	t.Parallel()
+
+	bs := newBatchesServiceStub(nil)
+	s, err := scheduler.NewScheduler(bs, time.Hour, test.NewLogger())
+	require.NoError(t, err)
+
+	s.Start()
+	select {
+	case <-bs.calls:
+	case <-time.After(time.Second):
+		t.Fatal("overdue batches should be revealed on start")
+	}
+	s.Stop()
+
+	assert.Empty(t, bs.calls)
+}
+
+type batchesService interface {
+	RevealOverdue(ctx context.Context, now time.Time) error
+}
+
+type batchesServiceStub struct {
+	calls chan time.Time
+	err   error
+}
+
+func newBatchesServiceStub(err error) *batchesServiceStub {
+	return &batchesServiceStub{calls: make(chan time.Time, 10), err: err}
+}
+
+func (s *batchesServiceStub) RevealOverdue(_ context.Context, now time.Time) error {
+	s.calls <- now
+	return s.err
+}
@@ -0,0 +1,126 @@
+package tracing
+
+import (
+	"context"
+
+	"planningpoker/internal/domain/games"
+)
+
+// GamesService is a games service decorator tracing every command.
+type GamesService struct {
+	next games.Commander
+}
+
+// NewGamesService wraps the games service with tracing.
+func NewGamesService(next games.Commander) *GamesService {
+	return &GamesService{
+		next: next,
+	}
+}
+
+// Create creates a game.
+func (s *GamesService) Create(ctx context.Context, cmd games.CreateGameCommand) (id string, err error) {
+	ctx, span := Start(ctx, "games.Create", AttrUserID.String(cmd.UserID))
+	defer func() { End(span, err) }()
+
+	id, err = s.next.Create(ctx, cmd)
+	span.SetAttributes(AttrGameID.String(id))
+
+	return id, err
+}
+
+// Join adds a player to the game.
+func (s *GamesService) Join(ctx context.Context, cmd games.JoinGameCommand) (err error) {
+	ctx, span := Start(ctx, "games.Join", AttrGameID.String(cmd.GameID), AttrUserID.String(cmd.UserID))
+	defer func() { End(span, err) }()
+
+	return s.next.Join(ctx, cmd)
+}
+
+// Leave removes a player from the game.
+func (s *GamesService) Leave(ctx context.Context, cmd games.LeaveGameCommand) (err error) {
+	ctx, span := Start(ctx, "games.Leave", AttrGameID.String(cmd.GameID), AttrUserID.String(cmd.UserID))
+	defer func() { End(span, err) }()
+
+	return s.next.Leave(ctx, cmd)
+}
+
+// Update updates a game.
+func (s *GamesService) Update(ctx context.Context, cmd games.UpdateGameCommand) (err error) {
+	ctx, span := Start(ctx, "games.Update", AttrGameID.String(cmd.GameID), AttrUserID.String(cmd.UserID))
+	defer func() { End(span, err) }()
+
+	return s.next.Update(ctx, cmd)
+}
+
+// Vote votes for a card.
+func (s *GamesService) Vote(ctx context.Context, cmd games.VoteCommand) (err error) {
+	ctx, span := Start(ctx, "games.Vote", AttrGameID.String(cmd.GameID), AttrUserID.String(cmd.UserID))
+	defer func() { End(span, err) }()
+
+	return s.next.Vote(ctx, cmd)
+}
+
+// UnVote removes a vote.
+func (s *GamesService) UnVote(ctx context.Context, cmd games.UnVoteCommand) (err error) {
+	ctx, span := Start(ctx, "games.UnVote", AttrGameID.String(cmd.GameID), AttrUserID.String(cmd.UserID))
+	defer func() { End(span, err) }()
+
+	return s.next.UnVote(ctx, cmd)
+}
+
+// Reveal reveals the cards.
+func (s *GamesService) Reveal(ctx context.Context, cmd games.RevealCardsCommand) (err error) {
+	ctx, span := Start(ctx, "games.Reveal", AttrGameID.String(cmd.GameID), AttrUserID.String(cmd.UserID))
+	defer func() { End(span, err) }()
+
+	return s.next.Reveal(ctx, cmd)
+}
+
+// Restart restarts the game.
+func (s *GamesService) Restart(ctx context.Context, cmd games.RestartGameCommand) (err error) {
+	ctx, span := Start(ctx, "games.Restart", AttrGameID.String(cmd.GameID), AttrUserID.String(cmd.UserID))
+	defer func() { End(span, err) }()
+
+	return s.next.Restart(ctx, cmd)
+}
+
+// Revote starts voting on the same ticket again.
+func (s *GamesService) Revote(ctx context.Context, cmd games.RevoteCommand) (err error) {
+	ctx, span := Start(ctx, "games.Revote", AttrGameID.String(cmd.GameID), AttrUserID.String(cmd.UserID))
+	defer func() { End(span, err) }()
+
+	return s.next.Revote(ctx, cmd)
+}
+
+// Rearrange changes the seats order.
+func (s *GamesService) Rearrange(ctx context.Context, cmd games.RearrangeSeatsCommand) (err error) {
+	ctx, span := Start(ctx, "games.Rearrange", AttrGameID.String(cmd.GameID), AttrUserID.String(cmd.UserID))
+	defer func() { End(span, err) }()
+
+	return s.next.Rearrange(ctx, cmd)
+}
+
+// ChangePasscode rotates the join passcode.
+func (s *GamesService) ChangePasscode(ctx context.Context, cmd games.ChangePasscodeCommand) (err error) {
+	ctx, span := Start(ctx, "games.ChangePasscode", AttrGameID.String(cmd.GameID), AttrUserID.String(cmd.UserID))
+	defer func() { End(span, err) }()
+
+	return s.next.ChangePasscode(ctx, cmd)
+}
+
+// Spectate checks the user can watch the game.
+func (s *GamesService) Spectate(ctx context.Context, cmd games.JoinGameCommand) (err error) {
+	ctx, span := Start(ctx, "games.Spectate", AttrGameID.String(cmd.GameID), AttrUserID.String(cmd.UserID))
+	defer func() { End(span, err) }()
+
+	return s.next.Spectate(ctx, cmd)
+}
+
+// Chat sends a chat message or a reaction.
+func (s *GamesService) Chat(ctx context.Context, cmd games.ChatCommand) (msg *games.ChatMessage, err error) {
+	ctx, span := Start(ctx, "games.Chat", AttrGameID.String(cmd.GameID), AttrUserID.String(cmd.UserID))
+	defer func() { End(span, err) }()
+
+	return s.next.Chat(ctx, cmd)
+}
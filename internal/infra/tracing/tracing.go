@@ -0,0 +1,105 @@
+// Package tracing contains OpenTelemetry tracing setup and instrumentation helpers.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	serviceName         = "planningpoker"
+	instrumentationName = "planningpoker"
+)
+
+var (
+	// AttrGameID is a game ID span attribute.
+	AttrGameID = attribute.Key("game.id")
+	// AttrUserID is a user ID span attribute.
+	AttrUserID = attribute.Key("user.id")
+	// AttrEventType is a domain event type span attribute.
+	AttrEventType = attribute.Key("event.type")
+	// AttrAggregateID is a domain event aggregate ID span attribute.
+	AttrAggregateID = attribute.Key("event.aggregate_id")
+)
+
+// Setup installs the global tracer provider exporting spans with the exporter and the W3C trace context propagator.
+// The returned function flushes pending spans and stops the provider.
+func Setup(exporter sdktrace.SpanExporter, sampleRatio float64) func(context.Context) error {
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return tp.Shutdown
+}
+
+// NewStdoutExporter creates an exporter writing spans as JSON to the writer.
+func NewStdoutExporter(w io.Writer) (sdktrace.SpanExporter, error) {
+	exp, err := stdouttrace.New(stdouttrace.WithWriter(w))
+	if err != nil {
+		return nil, fmt.Errorf("stdout exporter: %w", err)
+	}
+
+	return exp, nil
+}
+
+// NewOTLPExporter creates an exporter sending spans to the OTLP HTTP collector at the endpoint (host:port).
+func NewOTLPExporter(ctx context.Context, endpoint string, insecure bool) (sdktrace.SpanExporter, error) {
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	if insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	exp, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("otlp exporter: %w", err)
+	}
+
+	return exp, nil
+}
+
+// Start starts a new span, it is a child of the span in ctx if any.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the error if any and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject writes the trace context of ctx into the carrier, e.g. domain event metadata.
+func Inject(ctx context.Context, carrier map[string]string) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(carrier))
+}
+
+// ExtractHTTP returns a copy of ctx continuing the trace context of HTTP headers.
+func ExtractHTTP(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Extract returns a copy of ctx continuing the trace context stored in the carrier.
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
@@ -0,0 +1,50 @@
+package transformers
+
+import (
+	"crypto/rand"
+	"math/big"
+	"sort"
+
+	"planningpoker/internal/domain/state"
+)
+
+// revealedCardResponse is a revealed card of an anonymous game, it has no player.
+type revealedCardResponse struct {
+	Card  string            `json:"card,omitempty"`
+	Cards map[string]string `json:"cards,omitempty"`
+}
+
+// newRevealedCardsResponse lists the revealed cards of all players in random order,
+// so neither names nor seats can be matched with cards.
+func newRevealedCardsResponse(gState state.GameState) []revealedCardResponse {
+	list := make([]revealedCardResponse, 0, len(gState.Players))
+	for _, p := range gState.Players {
+		if p.VotedCard == nil && len(p.VotedCards) == 0 {
+			continue
+		}
+		c := revealedCardResponse{Cards: newCardsResponse(p.VotedCards, false)}
+		if p.VotedCard != nil {
+			c.Card = p.VotedCard.Type()
+		}
+		list = append(list, c)
+	}
+
+	// cards are ordered before shuffling, so the seats order does not leak even if shuffling fails
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Card < list[j].Card
+	})
+	shuffle(len(list), func(i, j int) { list[i], list[j] = list[j], list[i] })
+
+	return list
+}
+
+// shuffle randomizes the order of n elements with the Fisher-Yates algorithm.
+func shuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return
+		}
+		swap(i, int(j.Int64()))
+	}
+}
@@ -0,0 +1,49 @@
+package transformers
+
+import (
+	"errors"
+
+	"planningpoker/internal/domain"
+	"planningpoker/internal/infra/ratelimit"
+)
+
+const (
+	// ErrorCodeInternal is a code for all errors which are not domain errors, the details are never exposed.
+	ErrorCodeInternal = "internal_error"
+	// ErrorCodeBadRequest is a code for malformed requests.
+	ErrorCodeBadRequest = "bad_request"
+	// ErrorCodeUnauthorized is a code for failed authentication.
+	ErrorCodeUnauthorized = "unauthorized"
+	// ErrorCodeRateLimited is a code for requests rejected by rate limiting.
+	ErrorCodeRateLimited = "rate_limited"
+)
+
+// ErrorResponse is a structured error payload shared by HTTP and socket APIs.
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewErrorResponse creates an error response, domain and rate limiting errors are exposed with their codes
+// and messages, any other error is reported as an internal one.
+func NewErrorResponse(err error) ErrorResponse {
+	if errors.Is(err, ratelimit.ErrLimited) {
+		return ErrorResponse{
+			Code:    ErrorCodeRateLimited,
+			Message: err.Error(),
+		}
+	}
+
+	var de *domain.Error
+	if errors.As(err, &de) {
+		return ErrorResponse{
+			Code:    de.Code(),
+			Message: err.Error(),
+		}
+	}
+
+	return ErrorResponse{
+		Code:    ErrorCodeInternal,
+		Message: "internal error",
+	}
+}
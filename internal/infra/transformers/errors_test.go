@@ -0,0 +1,48 @@
+package transformers_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/users"
+	"planningpoker/internal/infra/ratelimit"
+	"planningpoker/internal/infra/transformers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		err     error
+		expResp transformers.ErrorResponse
+	}{
+		"domain error": {
+			err:     games.ErrNotAPlayer,
+			expResp: transformers.ErrorResponse{Code: "not_a_player", Message: "user is not a player"},
+		},
+		"wrapped domain error": {
+			err:     fmt.Errorf("user creation: %w", users.ErrEmptyName),
+			expResp: transformers.ErrorResponse{Code: "invalid_name", Message: "user creation: user name should be provided"},
+		},
+		"rate limiting error": {
+			err:     ratelimit.ErrLimited,
+			expResp: transformers.ErrorResponse{Code: "rate_limited", Message: "too many requests, try again later"},
+		},
+		"non domain error is hidden": {
+			err:     errors.New("connection refused"),
+			expResp: transformers.ErrorResponse{Code: transformers.ErrorCodeInternal, Message: "internal error"},
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expResp, transformers.NewErrorResponse(tt.err))
+		})
+	}
+}
@@ -6,68 +6,246 @@ import (
 	"planningpoker/internal/domain/state"
 )
 
+// GameStateSchemaVersion is a version of the game state response schema.
+// It should be increased on every change which is not backward compatible for clients.
+const GameStateSchemaVersion = 3
+
 // PlayerStateResponse is a response payload for a player.
 type PlayerStateResponse struct {
-	Name       string `json:"name"`
-	VotedCard  string `json:"voted_card"`
-	Confidence string `json:"confidence"`
+	// ID is a public player handle, stable within the game. It is never an auth token.
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	VotedCard   string `json:"voted_card"`
+	Confidence  string `json:"confidence"`
+	Seat        int    `json:"seat"`
+	Facilitator bool   `json:"facilitator"`
+	CanReveal   bool   `json:"can_reveal"`
+	Active      bool   `json:"active"`
+	Voted       bool   `json:"voted"`
+	// VotedCards are cards per dimension name in dimensional decks, they are hidden the same way as VotedCard.
+	VotedCards map[string]string `json:"voted_cards,omitempty"`
 }
 
 func newPlayerStateResponse(gState state.GameState, pState state.PlayerState) PlayerStateResponse {
 	resp := PlayerStateResponse{
-		Name: pState.Name,
+		ID:          pState.Handle,
+		Name:        pState.Name,
+		Seat:        pState.Seat,
+		Facilitator: pState.Facilitator,
+		CanReveal:   pState.CanReveal,
+		Active:      pState.Active,
+		Voted:       pState.VotedCard != nil || len(pState.VotedCards) > 0,
 	}
-	if pState.VotedCard != nil {
-		if gState.State != games.GameStateFinished {
+	if !resp.Voted {
+		return resp
+	}
+
+	// cards of anonymous games stay hidden next to names, they are listed separately once revealed
+	if gState.State != games.GameStateFinished || gState.Anonymous {
+		if pState.VotedCard != nil {
 			resp.VotedCard = games.NewUnrevealedCard().Type()
-		} else {
-			resp.VotedCard = pState.VotedCard.Type()
-			resp.Confidence = pState.Confidence
 		}
+		resp.VotedCards = newCardsResponse(pState.VotedCards, true)
+		return resp
+	}
+
+	if pState.VotedCard != nil {
+		resp.VotedCard = pState.VotedCard.Type()
 	}
+	resp.VotedCards = newCardsResponse(pState.VotedCards, false)
+	resp.Confidence = pState.Confidence
 	return resp
 }
 
-type cardsDeckResponse struct {
+// newCardsResponse encodes cards voted per dimension, hidden cards are replaced with the unrevealed one.
+func newCardsResponse(cards map[string]games.Card, hidden bool) map[string]string {
+	if len(cards) == 0 {
+		return nil
+	}
+
+	resp := make(map[string]string, len(cards))
+	for name, c := range cards {
+		if hidden {
+			c = games.NewUnrevealedCard()
+		}
+		resp[name] = c.Type()
+	}
+	return resp
+}
+
+type dimensionResponse struct {
 	Name  string   `json:"name"`
 	Cards []string `json:"cards"`
 }
 
+type cardsDeckResponse struct {
+	Name       string              `json:"name"`
+	Cards      []string            `json:"cards"`
+	Dimensions []dimensionResponse `json:"dimensions,omitempty"`
+	Formula    string              `json:"formula,omitempty"`
+}
+
 func newCardsDeckResponse(cd games.CardsDeck) cardsDeckResponse {
-	resp := cardsDeckResponse{Name: cd.Name()}
+	resp := cardsDeckResponse{Name: cd.Name(), Formula: cd.Formula()}
 	for _, c := range cd.Cards() {
 		resp.Cards = append(resp.Cards, c.Type())
 	}
+	for _, d := range cd.Dimensions() {
+		dim := dimensionResponse{Name: d.Name()}
+		for _, c := range d.Cards() {
+			dim.Cards = append(dim.Cards, c.Type())
+		}
+		resp.Dimensions = append(resp.Dimensions, dim)
+	}
 	return resp
 }
 
-// GameStateResponse is a response payload with game state.
+type dimensionEstimateResponse struct {
+	// Name is empty for plain decks.
+	Name      string   `json:"name"`
+	Votes     int      `json:"votes"`
+	Lowest    string   `json:"lowest"`
+	Highest   string   `json:"highest"`
+	MostVoted string   `json:"most_voted"`
+	Average   *float64 `json:"average,omitempty"`
+	Spread    int      `json:"spread"`
+}
+
+type estimateResponse struct {
+	Dimensions []dimensionEstimateResponse `json:"dimensions"`
+	Final      *float64                    `json:"final,omitempty"`
+}
+
+func newEstimateResponse(e *games.Estimate) *estimateResponse {
+	if e == nil {
+		return nil
+	}
+
+	resp := &estimateResponse{
+		Dimensions: make([]dimensionEstimateResponse, len(e.Dimensions)),
+		Final:      e.Final,
+	}
+	for i, d := range e.Dimensions {
+		resp.Dimensions[i] = dimensionEstimateResponse{
+			Name:      d.Dimension,
+			Votes:     d.Votes,
+			Lowest:    d.Lowest.Type(),
+			Highest:   d.Highest.Type(),
+			MostVoted: d.MostVoted.Type(),
+			Average:   d.Average,
+			Spread:    d.Spread,
+		}
+	}
+	return resp
+}
+
+type attemptVoteResponse struct {
+	// ID is a public player handle, the player might have left the game since the attempt.
+	// ID and Name are empty in anonymous games.
+	ID    string            `json:"id"`
+	Name  string            `json:"name"`
+	Card  string            `json:"card"`
+	Cards map[string]string `json:"cards,omitempty"`
+}
+
+type attemptResponse struct {
+	Number int                   `json:"number"`
+	Votes  []attemptVoteResponse `json:"votes"`
+}
+
+// newAttemptResponses creates attempt responses, votes of anonymous games are shuffled and have no players.
+func newAttemptResponses(list []state.AttemptState, anonymous bool) []attemptResponse {
+	resp := make([]attemptResponse, len(list))
+	for i, a := range list {
+		resp[i] = attemptResponse{Number: a.Number, Votes: make([]attemptVoteResponse, len(a.Votes))}
+		for j, v := range a.Votes {
+			resp[i].Votes[j] = attemptVoteResponse{Card: v.Card.Type(), Cards: newCardsResponse(v.Cards, false)}
+			if !anonymous {
+				resp[i].Votes[j].ID, resp[i].Votes[j].Name = v.Handle, v.Name
+			}
+		}
+		if anonymous {
+			votes := resp[i].Votes
+			shuffle(len(votes), func(i, j int) { votes[i], votes[j] = votes[j], votes[i] })
+		}
+	}
+	return resp
+}
+
+// GameStateResponse is a response payload with game state, it is the same for everyone watching the game.
+// Fields personal for a player are sent separately in PersonalStateResponse.
 type GameStateResponse struct {
-	Name       string                `json:"name"`
-	TicketURL  string                `json:"ticket_url"`
-	CardsDeck  cardsDeckResponse     `json:"cards_deck"`
-	Players    []PlayerStateResponse `json:"players"`
-	State      string                `json:"state"`
-	VotedCard  string                `json:"voted_card"`
-	Confidence string                `json:"confidence"`
-	CanReveal  bool                  `json:"can_reveal"`
+	Version   int                   `json:"version"`
+	Name      string                `json:"name"`
+	TicketURL string                `json:"ticket_url"`
+	CardsDeck cardsDeckResponse     `json:"cards_deck"`
+	Players   []PlayerStateResponse `json:"players"`
+	State     string                `json:"state"`
+	Protected bool                  `json:"protected"`
+	// Attempt is a number of the current voting attempt of the ticket, previous ones are listed in Attempts.
+	Attempt         int               `json:"attempt"`
+	Attempts        []attemptResponse `json:"attempts"`
+	RevoteSuggested bool              `json:"revote_suggested"`
+	// Estimate is a summary of votes per dimension, it is sent once the cards are revealed.
+	Estimate *estimateResponse `json:"estimate,omitempty"`
+	// Anonymous is true when revealed cards are listed in RevealedCards without players.
+	Anonymous     bool                   `json:"anonymous"`
+	RevealedCards []revealedCardResponse `json:"revealed_cards,omitempty"`
+	// ChatDisabled is true when chat messages and reactions are turned off in the game.
+	ChatDisabled bool `json:"chat_disabled"`
 }
 
 // NewGameStateResponse creates a new game state response.
-func NewGameStateResponse(state state.GameState, player state.PlayerState) GameStateResponse {
+func NewGameStateResponse(state state.GameState) GameStateResponse {
 	resp := GameStateResponse{
-		Name:      state.Name,
-		TicketURL: state.TicketURL,
-		CardsDeck: newCardsDeckResponse(state.CardsDeck),
-		State:     state.State,
-		CanReveal: player.CanReveal,
+		Version:         GameStateSchemaVersion,
+		Name:            state.Name,
+		TicketURL:       state.TicketURL,
+		CardsDeck:       newCardsDeckResponse(state.CardsDeck),
+		State:           state.State,
+		Protected:       state.Protected,
+		Attempt:         1,
+		Attempts:        newAttemptResponses(state.Attempts, state.Anonymous),
+		RevoteSuggested: state.RevoteSuggested,
+		Estimate:        newEstimateResponse(state.Estimate),
+		Anonymous:       state.Anonymous,
+		ChatDisabled:    state.ChatDisabled,
 	}
-	if player.VotedCard != nil {
-		resp.VotedCard = player.VotedCard.Type()
-		resp.Confidence = player.Confidence
+	if state.Anonymous && state.State == games.GameStateFinished {
+		resp.RevealedCards = newRevealedCardsResponse(state)
+	}
+	if n := len(state.Attempts); n > 0 {
+		resp.Attempt = state.Attempts[n-1].Number + 1
 	}
 	for _, p := range state.Players {
 		resp.Players = append(resp.Players, newPlayerStateResponse(state, p))
 	}
 	return resp
 }
+
+// PersonalStateResponse is a response payload with the game state fields known to the player only.
+type PersonalStateResponse struct {
+	// Handle is the player ID in GameStateResponse players, so the client can find itself there.
+	Handle     string `json:"handle"`
+	VotedCard  string `json:"voted_card"`
+	Confidence string `json:"confidence"`
+	CanReveal  bool   `json:"can_reveal"`
+	// VotedCards are cards of the player per dimension name in dimensional decks.
+	VotedCards map[string]string `json:"voted_cards,omitempty"`
+}
+
+// NewPersonalStateResponse creates a new personal state response of the player.
+func NewPersonalStateResponse(player state.PlayerState) PersonalStateResponse {
+	resp := PersonalStateResponse{
+		Handle:     player.Handle,
+		CanReveal:  player.CanReveal,
+		VotedCards: newCardsResponse(player.VotedCards, false),
+	}
+	if player.VotedCard != nil || len(player.VotedCards) > 0 {
+		resp.Confidence = player.Confidence
+	}
+	if player.VotedCard != nil {
+		resp.VotedCard = player.VotedCard.Type()
+	}
+	return resp
+}
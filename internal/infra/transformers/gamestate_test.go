@@ -0,0 +1,267 @@
+package transformers_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/state"
+	"planningpoker/internal/infra/transformers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGameStateResponse(t *testing.T) {
+	t.Parallel()
+
+	card := games.Card("XS")
+	facilitator := state.PlayerState{
+		UserID:      "user-1",
+		Handle:      "handle-1",
+		Name:        "Alex",
+		VotedCard:   &card,
+		Confidence:  games.ConfidenceNormal,
+		CanReveal:   true,
+		Active:      true,
+		Facilitator: true,
+		Seat:        0,
+	}
+	player := state.PlayerState{
+		UserID: "user-2",
+		Handle: "handle-2",
+		Name:   "Alex",
+		Active: true,
+		Seat:   1,
+	}
+
+	testCases := map[string]struct {
+		gameState  string
+		expPlayers []transformers.PlayerStateResponse
+	}{
+		"cards are hidden in running game": {
+			gameState: games.GameStateStarted,
+			expPlayers: []transformers.PlayerStateResponse{
+				{
+					ID: "handle-1", Name: "Alex", VotedCard: "*", Seat: 0,
+					Facilitator: true, CanReveal: true, Active: true, Voted: true,
+				},
+				{
+					ID: "handle-2", Name: "Alex", Seat: 1, Active: true,
+				},
+			},
+		},
+		"cards are shown in finished game": {
+			gameState: games.GameStateFinished,
+			expPlayers: []transformers.PlayerStateResponse{
+				{
+					ID: "handle-1", Name: "Alex", VotedCard: "XS", Confidence: games.ConfidenceNormal, Seat: 0,
+					Facilitator: true, CanReveal: true, Active: true, Voted: true,
+				},
+				{
+					ID: "handle-2", Name: "Alex", Seat: 1, Active: true,
+				},
+			},
+		},
+	}
+
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			gState := state.GameState{
+				GameID:    "game",
+				Name:      "name",
+				TicketURL: "https://example.com",
+				CardsDeck: newTestDeck(t),
+				Players:   []state.PlayerState{facilitator, player},
+				State:     tt.gameState,
+			}
+
+			resp := transformers.NewGameStateResponse(gState)
+
+			assert.Equal(t, transformers.GameStateSchemaVersion, resp.Version)
+			assert.Equal(t, "name", resp.Name)
+			assert.Equal(t, "https://example.com", resp.TicketURL)
+			assert.Equal(t, tt.gameState, resp.State)
+			assert.Equal(t, tt.expPlayers, resp.Players)
+		})
+	}
+}
+
+func TestNewPersonalStateResponse(t *testing.T) {
+	t.Parallel()
+
+	card := games.Card("XS")
+	resp := transformers.NewPersonalStateResponse(state.PlayerState{
+		UserID: "user-1", Handle: "handle-1", VotedCard: &card, Confidence: games.ConfidenceHigh, CanReveal: true,
+	})
+	assert.Equal(t, transformers.PersonalStateResponse{
+		Handle: "handle-1", VotedCard: "XS", Confidence: games.ConfidenceHigh, CanReveal: true,
+	}, resp)
+
+	// the confidence of a player who did not vote is meaningless
+	resp = transformers.NewPersonalStateResponse(state.PlayerState{Handle: "handle-2", Confidence: games.ConfidenceNormal})
+	assert.Equal(t, transformers.PersonalStateResponse{Handle: "handle-2"}, resp)
+
+	raw, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "user-")
+}
+
+func TestNewGameStateResponse_UserIDsAreNotExposed(t *testing.T) {
+	t.Parallel()
+
+	me := state.PlayerState{UserID: "secret-user-id", Handle: "handle", Name: "Alex"}
+	gState := state.GameState{
+		CardsDeck: newTestDeck(t),
+		Players:   []state.PlayerState{me},
+		State:     games.GameStateStarted,
+	}
+
+	resp := transformers.NewGameStateResponse(gState)
+
+	require.Len(t, resp.Players, 1)
+	assert.Equal(t, "handle", resp.Players[0].ID)
+	assert.NotContains(t, resp.Players[0].ID, me.UserID)
+}
+
+func TestNewGameStateResponse_Attempts(t *testing.T) {
+	t.Parallel()
+
+	me := state.PlayerState{UserID: "user-1", Handle: "handle-1", Name: "Alex"}
+	gState := state.GameState{
+		CardsDeck: newTestDeck(t),
+		Players:   []state.PlayerState{me},
+		State:     games.GameStateFinished,
+		Attempts: []state.AttemptState{
+			{Number: 1, Votes: []state.AttemptVoteState{{Handle: "handle-1", Name: "Alex", Card: "XS"}}},
+			{Number: 2, Votes: []state.AttemptVoteState{{Handle: "handle-1", Name: "Alex", Card: "S"}}},
+		},
+		RevoteSuggested: true,
+	}
+
+	resp := transformers.NewGameStateResponse(gState)
+	assert.Equal(t, 3, resp.Attempt)
+	assert.True(t, resp.RevoteSuggested)
+
+	raw, err := json.Marshal(resp.Attempts)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"number": 1, "votes": [{"id": "handle-1", "name": "Alex", "card": "XS"}]},
+		{"number": 2, "votes": [{"id": "handle-1", "name": "Alex", "card": "S"}]}
+	]`, string(raw))
+
+	// the first attempt of a ticket has no previous attempts
+	resp = transformers.NewGameStateResponse(state.GameState{CardsDeck: newTestDeck(t)})
+	assert.Equal(t, 1, resp.Attempt)
+	assert.Empty(t, resp.Attempts)
+}
+
+func TestNewGameStateResponse_DimensionalVotes(t *testing.T) {
+	t.Parallel()
+
+	complexity, err := games.NewDimension("complexity", []games.Card{"1", "2"})
+	require.NoError(t, err)
+	deck, err := games.NewDimensionalCardsDeck("axes", []games.Dimension{*complexity}, games.FormulaSum)
+	require.NoError(t, err)
+
+	me := state.PlayerState{UserID: "user-1", Handle: "handle-1", VotedCards: map[string]games.Card{"complexity": "2"}}
+	other := state.PlayerState{UserID: "user-2", Handle: "handle-2", VotedCards: map[string]games.Card{"complexity": "1"}}
+	gState := state.GameState{CardsDeck: *deck, Players: []state.PlayerState{me, other}, State: games.GameStateStarted}
+
+	resp := transformers.NewGameStateResponse(gState)
+	assert.Equal(t, map[string]string{"complexity": "2"}, transformers.NewPersonalStateResponse(me).VotedCards)
+	assert.Equal(t, map[string]string{"complexity": "*"}, resp.Players[0].VotedCards)
+	assert.Equal(t, map[string]string{"complexity": "*"}, resp.Players[1].VotedCards)
+	assert.True(t, resp.Players[1].Voted)
+	assert.Empty(t, resp.Players[1].VotedCard)
+	assert.Nil(t, resp.Estimate)
+
+	average := 1.5
+	gState.State = games.GameStateFinished
+	gState.Estimate = &games.Estimate{
+		Dimensions: []games.DimensionEstimate{{
+			Dimension: "complexity", Votes: 2, Lowest: "1", Highest: "2", MostVoted: "2", Average: &average, Spread: 1,
+		}},
+		Final: &average,
+	}
+
+	resp = transformers.NewGameStateResponse(gState)
+	assert.Equal(t, map[string]string{"complexity": "1"}, resp.Players[1].VotedCards)
+
+	raw, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"dimensions": [
+			{"name": "complexity", "votes": 2, "lowest": "1", "highest": "2", "most_voted": "2", "average": 1.5, "spread": 1}
+		],
+		"final": 1.5
+	}`, estimateJSON(t, raw))
+	assert.Contains(t, string(raw), `"dimensions":[{"name":"complexity","cards":["1","2"]}],"formula":"sum"`)
+}
+
+func TestNewGameStateResponse_Anonymous(t *testing.T) {
+	t.Parallel()
+
+	xs, s := games.Card("XS"), games.Card("S")
+	me := state.PlayerState{UserID: "user-1", Handle: "handle-1", Name: "Alex", VotedCard: &xs, Confidence: "high"}
+	other := state.PlayerState{UserID: "user-2", Handle: "handle-2", Name: "Sam", VotedCard: &s, Confidence: "low"}
+	idle := state.PlayerState{UserID: "user-3", Handle: "handle-3", Name: "Kim"}
+	gState := state.GameState{
+		CardsDeck: newTestDeck(t),
+		Players:   []state.PlayerState{me, other, idle},
+		State:     games.GameStateStarted,
+		Anonymous: true,
+		Attempts: []state.AttemptState{{Number: 1, Votes: []state.AttemptVoteState{
+			{Handle: "handle-1", Name: "Alex", Card: "S"},
+			{Handle: "handle-2", Name: "Sam", Card: "S"},
+		}}},
+	}
+
+	// everyone sees who has voted before the reveal
+	resp := transformers.NewGameStateResponse(gState)
+	assert.True(t, resp.Anonymous)
+	assert.True(t, resp.Players[1].Voted)
+	assert.False(t, resp.Players[2].Voted)
+	assert.Empty(t, resp.RevealedCards)
+
+	gState.State = games.GameStateFinished
+	resp = transformers.NewGameStateResponse(gState)
+
+	// own card is still known to the player
+	assert.Equal(t, "XS", transformers.NewPersonalStateResponse(me).VotedCard)
+	for _, p := range resp.Players {
+		assert.NotContains(t, []string{"XS", "S"}, p.VotedCard, p.Name)
+		assert.Empty(t, p.Confidence, p.Name)
+	}
+
+	cards := make([]string, 0, len(resp.RevealedCards))
+	for _, c := range resp.RevealedCards {
+		cards = append(cards, c.Card)
+	}
+	assert.ElementsMatch(t, []string{"XS", "S"}, cards)
+
+	raw, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), `"revealed_cards":[{"card":`)
+	assert.NotContains(t, string(raw), `"name":"Alex","card"`)
+	assert.NotContains(t, string(raw), `"id":"handle-2","name":"Sam","card"`)
+	require.Len(t, resp.Attempts, 1)
+	assert.ElementsMatch(t, []string{"", ""}, []string{resp.Attempts[0].Votes[0].Name, resp.Attempts[0].Votes[1].Name})
+}
+
+func estimateJSON(t *testing.T, raw []byte) string {
+	var resp map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(raw, &resp))
+
+	return string(resp["estimate"])
+}
+
+func newTestDeck(t *testing.T) games.CardsDeck {
+	deck, err := games.NewCardsDeck("T-shirt", []games.Card{"XS", "S"})
+	require.NoError(t, err)
+
+	return *deck
+}
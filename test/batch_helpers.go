@@ -0,0 +1,22 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/batches"
+)
+
+// NewBatch creates a testing batch of named tickets published by specific user, the deadline is in a day.
+func NewBatch(t *testing.T, facilitatorID string, tickets ...string) *batches.Batch {
+	list := make([]batches.TicketData, len(tickets))
+	for i, name := range tickets {
+		list[i] = batches.TicketData{Name: name}
+	}
+
+	cmd, err := batches.NewCreateBatchCommand("batch", facilitatorID, NewTestDeck(t), list, time.Now().Add(24*time.Hour))
+	require.NoError(t, err)
+	return batches.NewBatch(*cmd, nil)
+}
@@ -1,6 +1,7 @@
 package test_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,14 +11,16 @@ import (
 	"planningpoker/internal/domain/users"
 	"planningpoker/internal/infra/eventbus"
 	"planningpoker/internal/infra/repository"
+	"planningpoker/test"
 )
 
 func TestWorkflow(t *testing.T) {
+	ctx := context.Background()
 	eventBus := eventbus.NewInternalBus()
-	gamesRepo := repository.NewMemoryGameRepository(eventBus)
-	usersRepo := repository.NewMemoryUserRepository(eventBus)
+	gamesRepo := repository.NewMemoryGameRepository(eventBus, test.NewLogger())
+	usersRepo := repository.NewMemoryUserRepository(eventBus, test.NewLogger())
 
-	gamesService, err := games.NewService(gamesRepo, eventBus)
+	gamesService, err := games.NewService(gamesRepo, eventBus, games.Limits{}, test.NewLogger())
 	require.NoError(t, err)
 	require.NotNil(t, gamesService)
 
@@ -25,45 +28,45 @@ func TestWorkflow(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, usersService)
 
-	stateService, err := state.NewService(gamesRepo, usersRepo, publisherStub{}, eventBus)
+	stateService, err := state.NewService(gamesRepo, usersRepo, publisherStub{}, eventBus, test.NewLogger())
 	require.NoError(t, err)
 	require.NotNil(t, stateService)
 
 	regCmd, err := users.NewRegisterCommand("John")
 	require.NoError(t, err)
-	user1, err := usersService.Register(*regCmd)
+	user1, err := usersService.Register(ctx, *regCmd)
 	require.NoError(t, err)
 	require.NotNil(t, user1)
 
 	regCmd, err = users.NewRegisterCommand("Mike")
 	require.NoError(t, err)
-	user2, err := usersService.Register(*regCmd)
+	user2, err := usersService.Register(ctx, *regCmd)
 	require.NoError(t, err)
 	require.NotNil(t, user2)
 
-	cmd, err := games.NewCreateGameCommand("a", "b", user1.ID(), newTestCardsDeck(t), false)
+	cmd, err := games.NewCreateGameCommand("a", "https://b.example.com", user1.ID(), newTestCardsDeck(t), false)
 	require.NoError(t, err)
 
-	gameID, err := gamesService.Create(*cmd)
+	gameID, err := gamesService.Create(ctx, *cmd)
 	require.NoError(t, err)
 	require.NotEmpty(t, gameID)
 
 	joinCmd, err := games.NewJoinGameCommand(gameID, user2.ID())
 	require.NoError(t, err)
-	err = gamesService.Join(*joinCmd)
+	err = gamesService.Join(ctx, *joinCmd)
 	require.NoError(t, err)
 
 	voteCmd, err := games.NewVoteCommand(gameID, user1.ID(), "XS", games.ConfidenceNormal)
 	require.NoError(t, err)
-	err = gamesService.Vote(*voteCmd)
+	err = gamesService.Vote(ctx, *voteCmd)
 	require.NoError(t, err)
 
 	voteCmd, err = games.NewVoteCommand(gameID, user2.ID(), "?", games.ConfidenceNormal)
 	require.NoError(t, err)
-	err = gamesService.Vote(*voteCmd)
+	err = gamesService.Vote(ctx, *voteCmd)
 	require.NoError(t, err)
 
-	st, err := stateService.GameState(gameID)
+	st, err := stateService.GameState(ctx, gameID)
 	require.NoError(t, err)
 	require.NotNil(t, st)
 
@@ -71,10 +74,10 @@ func TestWorkflow(t *testing.T) {
 
 	revealCmd, err := games.NewRevealCardsCommand(gameID, user1.ID())
 	require.NoError(t, err)
-	err = gamesService.Reveal(*revealCmd)
+	err = gamesService.Reveal(ctx, *revealCmd)
 	require.NoError(t, err)
 
-	st, err = stateService.GameState(gameID)
+	st, err = stateService.GameState(ctx, gameID)
 	require.NoError(t, err)
 	require.NotNil(t, st)
 
@@ -82,6 +85,43 @@ func TestWorkflow(t *testing.T) {
 	assert.Equal(t, "finished", st.State)
 }
 
+func TestGamesLimit(t *testing.T) {
+	ctx := context.Background()
+	const maxGames = 3
+	eventBus := eventbus.NewInternalBus()
+	gamesRepo := repository.NewMemoryGameRepository(eventBus, test.NewLogger())
+
+	gamesService, err := games.NewService(gamesRepo, eventBus, games.Limits{MaxGamesPerUser: maxGames}, test.NewLogger())
+	require.NoError(t, err)
+
+	cmd, err := games.NewCreateGameCommand("a", "", test.User1, newTestCardsDeck(t), false)
+	require.NoError(t, err)
+
+	ids := make([]string, 0, maxGames)
+	for i := 0; i < maxGames; i++ {
+		id, err := gamesService.Create(ctx, *cmd)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	// the facilitator leaving a game does not free a slot, the game is still there
+	leaveCmd, err := games.NewLeaveGameCommand(ids[0], test.User1)
+	require.NoError(t, err)
+	require.NoError(t, gamesService.Leave(ctx, *leaveCmd))
+
+	_, err = gamesService.Create(ctx, *cmd)
+	assert.ErrorIs(t, err, games.ErrTooManyGames)
+
+	// archiving does
+	archived, err := gamesRepo.Get(ctx, ids[1])
+	require.NoError(t, err)
+	archived.Archive()
+	require.NoError(t, gamesRepo.Save(ctx, archived))
+
+	_, err = gamesService.Create(ctx, *cmd)
+	assert.NoError(t, err)
+}
+
 func newTestCardsDeck(t *testing.T) games.CardsDeck {
 	types := []string{"XS", "?"}
 	cards := make([]games.Card, len(types))
@@ -99,6 +139,10 @@ func newTestCardsDeck(t *testing.T) games.CardsDeck {
 
 type publisherStub struct{}
 
-func (p publisherStub) SendToPlayer(gameState state.GameState, userID string) error {
+func (p publisherStub) SendToGame(context.Context, state.GameState) error {
+	return nil
+}
+
+func (p publisherStub) SendToPlayer(_ context.Context, gameState state.GameState, userID string) error {
 	return nil
 }
@@ -20,6 +20,8 @@ var (
 	User1 = "user-id-1"
 	// User2 is a dummy id for testing user.
 	User2 = "user-id-2"
+	// User3 is a dummy id for testing user.
+	User3 = "user-id-3"
 )
 
 // NewTestGame creates a new testing game.
@@ -55,6 +57,32 @@ func (g *Game) UserJoins(uid string) *Game {
 	return g
 }
 
+// UserJoinsWithPasscode performs user joining the protected game.
+func (g *Game) UserJoinsWithPasscode(uid, passcode string) *Game {
+	cmd, err := games.NewJoinGameCommand(g.game.ID(), uid)
+	require.NoError(g.t, err)
+	cmd.Passcode = passcode
+	g.lastError = g.game.Join(*cmd)
+	return g
+}
+
+// UserSpectates checks the user can watch the game with the passcode.
+func (g *Game) UserSpectates(uid, passcode string) *Game {
+	cmd, err := games.NewJoinGameCommand(g.game.ID(), uid)
+	require.NoError(g.t, err)
+	cmd.Passcode = passcode
+	g.lastError = g.game.CanSpectate(*cmd)
+	return g
+}
+
+// UserChangesPasscode rotates the game passcode, an empty passcode removes the protection.
+func (g *Game) UserChangesPasscode(uid, passcode string) *Game {
+	cmd, err := games.NewChangePasscodeCommand(g.game.ID(), uid, passcode)
+	require.NoError(g.t, err)
+	g.lastError = g.game.ChangePasscode(*cmd)
+	return g
+}
+
 // UserVotes performs a user vote.
 func (g *Game) UserVotes(uid, cardName string) *Game {
 	card, err := games.NewCard(cardName)
@@ -65,6 +93,18 @@ func (g *Game) UserVotes(uid, cardName string) *Game {
 	return g
 }
 
+// UserVotesDimensions performs a user vote in a dimensional game, cards are dimension names followed by cards.
+func (g *Game) UserVotesDimensions(uid string, cards ...string) *Game {
+	votes := make(map[string]games.Card, len(cards)/2)
+	for i := 0; i+1 < len(cards); i += 2 {
+		votes[cards[i]] = games.Card(cards[i+1])
+	}
+	cmd, err := games.NewDimensionalVoteCommand(g.game.ID(), uid, votes, games.ConfidenceNormal)
+	require.NoError(g.t, err)
+	g.lastError = g.game.Vote(*cmd)
+	return g
+}
+
 // UserLeaves performs a user leave from game.
 func (g *Game) UserLeaves(uid string) *Game {
 	cmd, err := games.NewLeaveGameCommand(g.game.ID(), uid)
@@ -89,6 +129,28 @@ func (g *Game) UserRestartsGame(uid string) *Game {
 	return g
 }
 
+// UserRevotes starts voting on the same ticket again.
+func (g *Game) UserRevotes(uid string) *Game {
+	cmd, err := games.NewRevoteCommand(g.game.ID(), uid)
+	require.NoError(g.t, err)
+	g.lastError = g.game.Revote(*cmd)
+	return g
+}
+
+// UserRearrangesSeats changes players seats, order contains current players positions.
+func (g *Game) UserRearrangesSeats(uid string, order ...int) *Game {
+	cmd, err := games.NewRearrangeSeatsCommand(g.game.ID(), uid, order)
+	require.NoError(g.t, err)
+	g.lastError = g.game.Rearrange(*cmd)
+	return g
+}
+
+// ShouldHavePlayersOrder asserts that players are seated in specific order.
+func (g *Game) ShouldHavePlayersOrder(uids ...string) *Game {
+	require.Equal(g.t, uids, g.game.PlayerIDs())
+	return g
+}
+
 // ShouldHaveVote asserts that specific user voted with specific card.
 func (g *Game) ShouldHaveVote(uid string, cardName string) *Game {
 	card, err := games.NewCard(cardName)
@@ -157,6 +219,40 @@ func NewSimpleGame(t *testing.T, everybodyCanReveal bool) *games.Game {
 	return games.NewGame(*cmd)
 }
 
+// NewFacilitatedGame creates a simple testing game created by specific user.
+func NewFacilitatedGame(t *testing.T, facilitatorID string) *games.Game {
+	cmd, err := games.NewCreateGameCommand("", "", facilitatorID, NewTestDeck(t), false)
+	require.NoError(t, err)
+	return games.NewGame(*cmd)
+}
+
+// NewProtectedGame creates a testing game created by specific user and protected with the passcode.
+func NewProtectedGame(t *testing.T, facilitatorID, passcode string) *games.Game {
+	cmd, err := games.NewCreateGameCommand("", "", facilitatorID, NewTestDeck(t), false)
+	require.NoError(t, err)
+	require.NoError(t, cmd.ProtectWith(passcode))
+	return games.NewGame(*cmd)
+}
+
+// NewDimensionalGame creates a game with "complexity" and "effort" dimensions combined with the formula.
+func NewDimensionalGame(t *testing.T, formula string) *games.Game {
+	cmd, err := games.NewCreateGameCommand("", "", "", NewTestDimensionalDeck(t, formula), true)
+	require.NoError(t, err)
+	return games.NewGame(*cmd)
+}
+
+// NewTestDimensionalDeck creates a testing deck with "complexity" and "effort" dimensions.
+func NewTestDimensionalDeck(t *testing.T, formula string) games.CardsDeck {
+	complexity, err := games.NewDimension("complexity", []games.Card{"1", "2", "3", games.AbstainCard})
+	require.NoError(t, err)
+	effort, err := games.NewDimension("effort", []games.Card{"1", "2", "4", "8"})
+	require.NoError(t, err)
+	deck, err := games.NewDimensionalCardsDeck("axes", []games.Dimension{*complexity, *effort}, formula)
+	require.NoError(t, err)
+
+	return *deck
+}
+
 // NewTestDeck creates a simple testing cards deck.
 func NewTestDeck(t *testing.T) games.CardsDeck {
 	card1, err := games.NewCard("XS")
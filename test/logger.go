@@ -0,0 +1,15 @@
+package test
+
+import (
+	"io"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NewLogger creates a logger dropping all the entries.
+func NewLogger() *logrus.Entry {
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+
+	return logrus.NewEntry(l)
+}
@@ -0,0 +1,828 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/batches"
+	"planningpoker/internal/domain/events"
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/rooms"
+	"planningpoker/internal/domain/teams"
+	"planningpoker/internal/domain/users"
+)
+
+// concurrentWriters is a number of goroutines used to check exclusive modifications.
+const concurrentWriters = 20
+
+// EventsRecorder is an event bus which records all published events.
+type EventsRecorder struct {
+	m      sync.Mutex
+	events []events.DomainEvent
+}
+
+// Publish records the event.
+func (r *EventsRecorder) Publish(_ context.Context, e events.DomainEvent) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.events = append(r.events, e)
+	return nil
+}
+
+// Subscribe does nothing, the recorder has no consumers.
+func (r *EventsRecorder) Subscribe(events.Consumer, ...string) {}
+
+// Events returns all recorded events.
+func (r *EventsRecorder) Events() []events.DomainEvent {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return append([]events.DomainEvent(nil), r.events...)
+}
+
+// GameRepositoryContract runs the conformance suite every games.GameRepository implementation should pass.
+// newRepo should return a new empty repository publishing events to the provided bus.
+func GameRepositoryContract(t *testing.T, newRepo func(bus events.EventBus) games.GameRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	t.Run("get returns nil on not found", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		game, err := repo.Get(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, game)
+	})
+
+	t.Run("save and get round trip every field", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		card := games.Card("XS")
+		lastActivity := time.Now().Add(-time.Hour)
+		players := map[string]*games.Player{
+			User1: {VotedCard: &card, Confidence: games.ConfidenceNormal, CanReveal: true, Active: true, Seat: 1, Handle: "h1"},
+			User2: {VotedCard: nil, Confidence: "", CanReveal: false, Active: false, Seat: 0, Handle: "h2"},
+		}
+		attempts := []games.Attempt{{Number: 1, Votes: []games.AttemptVote{{UserID: User1, Handle: "h1", Card: "L"}}}}
+		game := games.NewRaw(
+			"game-id", "name", "https://example.com", NewTestDeck(t), players,
+			games.GameStateFinished, true, User1, lastActivity, []byte("hash"), "team-id", attempts, 2, true, true,
+		)
+
+		require.NoError(t, repo.Save(ctx, game))
+		got, err := repo.Get(ctx, game.ID())
+		require.NoError(t, err)
+		require.NotNil(t, got)
+
+		assert.Equal(t, game.ID(), got.ID())
+		assert.Equal(t, game.Name(), got.Name())
+		assert.Equal(t, game.TicketURL(), got.TicketURL())
+		assert.Equal(t, game.CardsDeck(), got.CardsDeck())
+		assert.Equal(t, game.Players(), got.Players())
+		assert.Equal(t, game.State(), got.State())
+		assert.Equal(t, game.EveryoneCanReveal(), got.EveryoneCanReveal())
+		assert.Equal(t, game.FacilitatorID(), got.FacilitatorID())
+		assert.Equal(t, game.PasscodeHash(), got.PasscodeHash())
+		assert.Equal(t, game.TeamID(), got.TeamID())
+		assert.Equal(t, game.Attempts(), got.Attempts())
+		assert.Equal(t, game.RevoteThreshold(), got.RevoteThreshold())
+		assert.Equal(t, game.IsAnonymous(), got.IsAnonymous())
+		assert.Equal(t, game.IsChatDisabled(), got.IsChatDisabled())
+		assert.True(t, game.LastActivityAt().Equal(got.LastActivityAt()))
+		assert.Empty(t, got.GetEvents())
+	})
+
+	t.Run("save and get round trip dimensional votes", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		game := NewTestGame(t, NewDimensionalGame(t, games.FormulaSum)).
+			UserJoins(User1).
+			UserVotesDimensions(User1, "complexity", "2", "effort", "8").
+			ShouldSucceed().
+			Instance()
+
+		require.NoError(t, repo.Save(ctx, game))
+		got, err := repo.Get(ctx, game.ID())
+		require.NoError(t, err)
+		require.NotNil(t, got)
+
+		assert.Equal(t, game.CardsDeck(), got.CardsDeck())
+		assert.Equal(t, game.Players(), got.Players())
+		assert.Equal(t, game.Estimate(), got.Estimate())
+	})
+
+	t.Run("save publishes aggregate events", func(t *testing.T) {
+		bus := &EventsRecorder{}
+		repo := newRepo(bus)
+		game := NewTestGame(t, NewSimpleGame(t, true)).UserJoins(User1).ShouldSucceed().Instance()
+
+		require.NoError(t, repo.Save(ctx, game))
+
+		list := bus.Events()
+		require.Len(t, list, len(game.GetEvents()))
+		for _, e := range list {
+			assert.Equal(t, events.EventTypeGameUpdated, e.EventType())
+			assert.Equal(t, game.ID(), e.AggregateID())
+		}
+	})
+
+	t.Run("modify exclusively returns not found", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		err := repo.ModifyExclusively(ctx, "unknown", func(*games.Game) error {
+			t.Fatalf("callback should not be called")
+			return nil
+		})
+		assert.True(t, errors.Is(err, games.ErrGameNotFound))
+	})
+
+	t.Run("modify exclusively saves changes and publishes events", func(t *testing.T) {
+		bus := &EventsRecorder{}
+		repo := newRepo(bus)
+		game := NewSimpleGame(t, true)
+		require.NoError(t, repo.Save(ctx, game))
+		published := len(bus.Events())
+
+		err := repo.ModifyExclusively(ctx, game.ID(), func(g *games.Game) error {
+			return NewTestGame(t, g).UserJoins(User1).lastError
+		})
+		require.NoError(t, err)
+
+		got, err := repo.Get(ctx, game.ID())
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.True(t, got.IsPlayer(User1))
+		assert.Len(t, bus.Events(), published+1)
+	})
+
+	t.Run("modify exclusively does not save on callback error", func(t *testing.T) {
+		bus := &EventsRecorder{}
+		repo := newRepo(bus)
+		game := NewSimpleGame(t, true)
+		require.NoError(t, repo.Save(ctx, game))
+		published := len(bus.Events())
+
+		cbErr := errors.New("callback failed")
+		err := repo.ModifyExclusively(ctx, game.ID(), func(g *games.Game) error {
+			NewTestGame(t, g).UserJoins(User1)
+			return cbErr
+		})
+		assert.True(t, errors.Is(err, cbErr))
+
+		got, err := repo.Get(ctx, game.ID())
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.False(t, got.IsPlayer(User1))
+		assert.Len(t, bus.Events(), published)
+	})
+
+	t.Run("modify exclusively does not lose concurrent updates", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		game := NewSimpleGame(t, true)
+		require.NoError(t, repo.Save(ctx, game))
+
+		var wg sync.WaitGroup
+		errs := make(chan error, concurrentWriters)
+		for i := 0; i < concurrentWriters; i++ {
+			wg.Add(1)
+			go func(uid string) {
+				defer wg.Done()
+				errs <- repo.ModifyExclusively(ctx, game.ID(), func(g *games.Game) error {
+					cmd, err := games.NewJoinGameCommand(g.ID(), uid)
+					if err != nil {
+						return err
+					}
+					return g.Join(*cmd)
+				})
+			}(fmt.Sprintf("user-%d", i))
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		got, err := repo.Get(ctx, game.ID())
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Len(t, got.Players(), concurrentWriters)
+	})
+
+	t.Run("delete removes the game", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		game := NewTestGame(t, NewSimpleGame(t, true)).UserJoins(User1).Instance()
+		require.NoError(t, repo.Save(ctx, game))
+
+		require.NoError(t, repo.Delete(ctx, game.ID()))
+		require.NoError(t, repo.Delete(ctx, "unknown"))
+
+		got, err := repo.Get(ctx, game.ID())
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		list, err := repo.GetActiveGamesByPlayerID(ctx, User1)
+		require.NoError(t, err)
+		assert.Empty(t, list)
+	})
+
+	t.Run("get active games by player id", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		running := NewTestGame(t, NewSimpleGame(t, true)).UserJoins(User1).Instance()
+		finished := NewTestGame(t, NewSimpleGame(t, true)).UserJoins(User1).UserReveals(User1).Instance()
+		other := NewTestGame(t, NewSimpleGame(t, true)).UserJoins(User2).Instance()
+		for _, g := range []*games.Game{running, finished, other} {
+			require.NoError(t, repo.Save(ctx, g))
+		}
+
+		list, err := repo.GetActiveGamesByPlayerID(ctx, User1)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, running.ID(), list[0].ID())
+
+		list, err = repo.GetActiveGamesByPlayerID(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Empty(t, list)
+	})
+
+	t.Run("count games by facilitator id", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		running := NewFacilitatedGame(t, User1)
+		finished := NewTestGame(t, NewFacilitatedGame(t, User1)).UserJoins(User1).UserReveals(User1).Instance()
+		archived := NewFacilitatedGame(t, User1)
+		archived.Archive()
+		deleted := NewFacilitatedGame(t, User1)
+		other := NewFacilitatedGame(t, User2)
+		for _, g := range []*games.Game{running, finished, archived, deleted, other} {
+			require.NoError(t, repo.Save(ctx, g))
+		}
+		require.NoError(t, repo.Delete(ctx, deleted.ID()))
+
+		count, err := repo.CountGamesByFacilitatorID(ctx, User1)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		running.Archive()
+		require.NoError(t, repo.Save(ctx, running))
+		count, err = repo.CountGamesByFacilitatorID(ctx, User1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		count, err = repo.CountGamesByFacilitatorID(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("get idle game ids", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		idle := games.NewRaw("idle", "", "", NewTestDeck(t), map[string]*games.Player{}, games.GameStateStarted, false, "",
+			time.Now().Add(-time.Hour), nil, "", nil, 0, false, false)
+		active := NewSimpleGame(t, true)
+		require.NoError(t, repo.Save(ctx, idle))
+		require.NoError(t, repo.Save(ctx, active))
+
+		ids, err := repo.GetIdleGameIDs(ctx, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, []string{idle.ID()}, ids)
+	})
+}
+
+// UserRepositoryContract runs the conformance suite every users.Repository implementation should pass.
+// newRepo should return a new empty repository publishing events to the provided bus.
+func UserRepositoryContract(t *testing.T, newRepo func(bus events.EventBus) users.Repository) {
+	t.Helper()
+	ctx := context.Background()
+
+	t.Run("get returns nil on not found", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		user, err := repo.Get(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, user)
+	})
+
+	t.Run("save and get round trip every field", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		user := users.NewRaw("user-id", "name", time.Now().Add(-time.Hour))
+
+		require.NoError(t, repo.Save(ctx, *user))
+		got, err := repo.Get(ctx, user.ID())
+		require.NoError(t, err)
+		require.NotNil(t, got)
+
+		assert.Equal(t, user.ID(), got.ID())
+		assert.Equal(t, user.Name(), got.Name())
+		assert.True(t, user.LastSeenAt().Equal(got.LastSeenAt()))
+		assert.Empty(t, got.GetEvents())
+	})
+
+	t.Run("save publishes aggregate events", func(t *testing.T) {
+		bus := &EventsRecorder{}
+		repo := newRepo(bus)
+		user, err := users.NewUser("name")
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Save(ctx, *user))
+
+		list := bus.Events()
+		require.Len(t, list, 1)
+		assert.Equal(t, events.EventTypeUserUpdated, list[0].EventType())
+		assert.Equal(t, user.ID(), list[0].AggregateID())
+	})
+
+	t.Run("concurrent saves are not lost", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+
+		var wg sync.WaitGroup
+		errs := make(chan error, concurrentWriters)
+		for i := 0; i < concurrentWriters; i++ {
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				errs <- repo.Save(ctx, *users.NewRaw(id, id, time.Now()))
+			}(fmt.Sprintf("user-%d", i))
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		for i := 0; i < concurrentWriters; i++ {
+			got, err := repo.Get(ctx, fmt.Sprintf("user-%d", i))
+			require.NoError(t, err)
+			assert.NotNil(t, got)
+		}
+	})
+
+	t.Run("modify exclusively fails on unknown user", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		err := repo.ModifyExclusively(ctx, "unknown", func(*users.User) error { return nil })
+		assert.ErrorIs(t, err, users.ErrUserNotFound)
+	})
+
+	t.Run("concurrent modifications are not lost", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		user := users.NewRaw("user-id", "name", time.Now().Add(-time.Hour))
+		require.NoError(t, repo.Save(ctx, *user))
+
+		var wg sync.WaitGroup
+		errs := make(chan error, concurrentWriters+1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- repo.ModifyExclusively(ctx, user.ID(), func(u *users.User) error {
+				return u.NameAs("renamed")
+			})
+		}()
+		for i := 0; i < concurrentWriters; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- repo.ModifyExclusively(ctx, user.ID(), func(u *users.User) error {
+					u.MarkSeen()
+					return nil
+				})
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		got, err := repo.Get(ctx, user.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "renamed", got.Name())
+		assert.True(t, got.LastSeenAt().After(user.LastSeenAt()))
+	})
+
+	t.Run("delete removes the user", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		user := users.NewRaw("user-id", "name", time.Now())
+		require.NoError(t, repo.Save(ctx, *user))
+
+		require.NoError(t, repo.Delete(ctx, user.ID()))
+		require.NoError(t, repo.Delete(ctx, "unknown"))
+
+		got, err := repo.Get(ctx, user.ID())
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("get not seen since", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		gone := users.NewRaw("gone", "name", time.Now().Add(-time.Hour))
+		active := users.NewRaw("active", "name", time.Now())
+		require.NoError(t, repo.Save(ctx, *gone))
+		require.NoError(t, repo.Save(ctx, *active))
+
+		ids, err := repo.GetNotSeenSince(ctx, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, []string{gone.ID()}, ids)
+	})
+}
+
+// TeamRepositoryContract runs the conformance suite every teams.Repository implementation should pass.
+// newRepo should return a new empty repository publishing events to the provided bus.
+func TeamRepositoryContract(t *testing.T, newRepo func(bus events.EventBus) teams.Repository) {
+	t.Helper()
+	ctx := context.Background()
+
+	t.Run("get returns nil on not found", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		team, err := repo.Get(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, team)
+
+		team, err = repo.GetByJoinCode(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, team)
+	})
+
+	t.Run("save and get round trip every field", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		joinedAt := time.Now().Add(-time.Hour)
+		members := map[string]*teams.Member{
+			User1: {Role: teams.RoleOwner, Handle: "h1", JoinedAt: joinedAt},
+			User2: {Role: teams.RoleMember, Handle: "h2", JoinedAt: joinedAt},
+		}
+		settings := teams.Settings{CardsDeck: NewTestDeck(t), EveryoneCanReveal: true}
+		team := teams.NewRaw("team-id", "name", members, settings, "code", joinedAt)
+
+		require.NoError(t, repo.Save(ctx, team))
+		got, err := repo.Get(ctx, team.ID())
+		require.NoError(t, err)
+		require.NotNil(t, got)
+
+		assert.Equal(t, team.ID(), got.ID())
+		assert.Equal(t, team.Name(), got.Name())
+		assert.Equal(t, team.Settings(), got.Settings())
+		assert.Equal(t, team.JoinCode(), got.JoinCode())
+		assert.True(t, team.CreatedAt().Equal(got.CreatedAt()))
+		require.Len(t, got.Members(), 2)
+		for id, m := range team.Members() {
+			assert.Equal(t, m.Role, got.Members()[id].Role)
+			assert.Equal(t, m.Handle, got.Members()[id].Handle)
+			assert.True(t, m.JoinedAt.Equal(got.Members()[id].JoinedAt))
+		}
+		assert.Empty(t, got.GetEvents())
+	})
+
+	t.Run("save publishes aggregate events", func(t *testing.T) {
+		bus := &EventsRecorder{}
+		repo := newRepo(bus)
+		team := NewTeam(t, User1)
+
+		require.NoError(t, repo.Save(ctx, team))
+
+		list := bus.Events()
+		require.Len(t, list, 1)
+		assert.Equal(t, events.EventTypeTeamUpdated, list[0].EventType())
+		assert.Equal(t, team.ID(), list[0].AggregateID())
+	})
+
+	t.Run("modify exclusively fails on unknown team", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		err := repo.ModifyExclusively(ctx, "unknown", func(*teams.Team) error { return nil })
+		assert.ErrorIs(t, err, teams.ErrTeamNotFound)
+	})
+
+	t.Run("concurrent modifications are not lost", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		team := NewTeam(t, User1)
+		require.NoError(t, repo.Save(ctx, team))
+
+		var wg sync.WaitGroup
+		errs := make(chan error, concurrentWriters)
+		for i := 0; i < concurrentWriters; i++ {
+			wg.Add(1)
+			go func(uid string) {
+				defer wg.Done()
+				errs <- repo.ModifyExclusively(ctx, team.ID(), func(team *teams.Team) error {
+					return team.Join(teams.JoinTeamCommand{JoinCode: team.JoinCode(), UserID: uid})
+				})
+			}(fmt.Sprintf("user-%d", i))
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		got, err := repo.Get(ctx, team.ID())
+		require.NoError(t, err)
+		assert.Len(t, got.Members(), concurrentWriters+1)
+	})
+
+	t.Run("get by join code and member", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		team := NewTeam(t, User1)
+		JoinTeam(t, team, User2)
+		other := NewTeam(t, User3)
+		require.NoError(t, repo.Save(ctx, team))
+		require.NoError(t, repo.Save(ctx, other))
+
+		got, err := repo.GetByJoinCode(ctx, team.JoinCode())
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, team.ID(), got.ID())
+
+		list, err := repo.GetTeamsByMemberID(ctx, User2)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, team.ID(), list[0].ID())
+
+		list, err = repo.GetTeamsByMemberID(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Empty(t, list)
+	})
+}
+
+// RoomRepositoryContract runs the conformance suite every rooms.Repository implementation should pass.
+// newRepo should return a new empty repository publishing events to the provided bus.
+func RoomRepositoryContract(t *testing.T, newRepo func(bus events.EventBus) rooms.Repository) {
+	t.Helper()
+	ctx := context.Background()
+
+	t.Run("get returns nil on not found", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		room, err := repo.Get(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, room)
+
+		room, err = repo.GetBySlug(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, room)
+	})
+
+	t.Run("save and get round trip every field", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		createdAt := time.Now().Add(-time.Hour)
+		members := map[string]time.Time{User1: createdAt, User2: createdAt.Add(time.Minute)}
+		settings := rooms.Settings{CardsDeck: NewTestDeck(t), EveryoneCanReveal: true}
+		sessions := []rooms.Session{{GameID: "game-1", StartedAt: createdAt}, {GameID: "game-2", StartedAt: createdAt}}
+		room := rooms.NewRaw("room-id", "slug", "name", User1, members, settings, sessions, createdAt)
+
+		require.NoError(t, repo.Save(ctx, room))
+		got, err := repo.GetBySlug(ctx, room.Slug())
+		require.NoError(t, err)
+		require.NotNil(t, got)
+
+		assert.Equal(t, room.ID(), got.ID())
+		assert.Equal(t, room.Slug(), got.Slug())
+		assert.Equal(t, room.Name(), got.Name())
+		assert.Equal(t, room.OwnerID(), got.OwnerID())
+		assert.Equal(t, room.Settings(), got.Settings())
+		assert.Equal(t, room.MemberIDs(), got.MemberIDs())
+		assert.Equal(t, "game-2", got.CurrentGameID())
+		require.Len(t, got.Sessions(), 2)
+		assert.True(t, createdAt.Equal(got.Sessions()[0].StartedAt))
+		assert.True(t, room.CreatedAt().Equal(got.CreatedAt()))
+		assert.Empty(t, got.GetEvents())
+	})
+
+	t.Run("save publishes aggregate events", func(t *testing.T) {
+		bus := &EventsRecorder{}
+		repo := newRepo(bus)
+		room := NewRoom(t, "slug", User1)
+
+		require.NoError(t, repo.Save(ctx, room))
+
+		list := bus.Events()
+		require.Len(t, list, 1)
+		assert.Equal(t, events.EventTypeRoomUpdated, list[0].EventType())
+		assert.Equal(t, room.ID(), list[0].AggregateID())
+	})
+
+	t.Run("save fails on taken slug", func(t *testing.T) {
+		bus := &EventsRecorder{}
+		repo := newRepo(bus)
+		room := NewRoom(t, "slug", User1)
+		require.NoError(t, repo.Save(ctx, room))
+		require.NoError(t, repo.Save(ctx, room))
+
+		err := repo.Save(ctx, NewRoom(t, "slug", User2))
+		assert.ErrorIs(t, err, rooms.ErrSlugTaken)
+
+		got, err := repo.GetBySlug(ctx, "slug")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, room.ID(), got.ID())
+	})
+
+	t.Run("concurrent creations with the same slug keep one room", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+
+		var wg sync.WaitGroup
+		errs := make(chan error, concurrentWriters)
+		for i := 0; i < concurrentWriters; i++ {
+			wg.Add(1)
+			go func(uid string) {
+				defer wg.Done()
+				errs <- repo.Save(ctx, NewRoom(t, "slug", uid))
+			}(fmt.Sprintf("user-%d", i))
+		}
+		wg.Wait()
+		close(errs)
+
+		saved := 0
+		for err := range errs {
+			if err == nil {
+				saved++
+				continue
+			}
+			assert.ErrorIs(t, err, rooms.ErrSlugTaken)
+		}
+		assert.Equal(t, 1, saved)
+	})
+
+	t.Run("modify exclusively fails on unknown room", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		err := repo.ModifyExclusively(ctx, "unknown", func(*rooms.Room) error { return nil })
+		assert.ErrorIs(t, err, rooms.ErrRoomNotFound)
+	})
+
+	t.Run("concurrent modifications are not lost", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		room := NewRoom(t, "slug", User1)
+		require.NoError(t, repo.Save(ctx, room))
+
+		var wg sync.WaitGroup
+		errs := make(chan error, concurrentWriters)
+		for i := 0; i < concurrentWriters; i++ {
+			wg.Add(1)
+			go func(uid string) {
+				defer wg.Done()
+				errs <- repo.ModifyExclusively(ctx, room.ID(), func(room *rooms.Room) error {
+					room.Enter(uid)
+					return nil
+				})
+			}(fmt.Sprintf("user-%d", i))
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		got, err := repo.Get(ctx, room.ID())
+		require.NoError(t, err)
+		assert.Len(t, got.Members(), concurrentWriters+1)
+	})
+
+	t.Run("get by member", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		room := NewRoom(t, "first", User1)
+		room.Enter(User2)
+		require.NoError(t, repo.Save(ctx, room))
+		require.NoError(t, repo.Save(ctx, NewRoom(t, "second", User3)))
+
+		list, err := repo.GetRoomsByMemberID(ctx, User2)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, room.ID(), list[0].ID())
+
+		list, err = repo.GetRoomsByMemberID(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Empty(t, list)
+	})
+}
+
+// BatchRepositoryContract runs the conformance suite every batches.Repository implementation should pass.
+// newRepo should return a new empty repository publishing events to the provided bus.
+func BatchRepositoryContract(t *testing.T, newRepo func(bus events.EventBus) batches.Repository) {
+	t.Helper()
+	ctx := context.Background()
+
+	t.Run("get returns nil on not found", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		batch, err := repo.Get(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, batch)
+	})
+
+	t.Run("save and get round trip every field", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		createdAt := time.Now().Add(-time.Hour)
+		deadline := createdAt.Add(48 * time.Hour)
+		participants := map[string]time.Time{User1: createdAt, User2: createdAt.Add(time.Minute)}
+		tickets := []*batches.Ticket{
+			{ID: "t1", Name: "first", URL: "https://example.com/1", Votes: map[string]games.Card{User1: "XS", User2: "S"}, RevealedAt: createdAt},
+			{ID: "t2", Name: "second", Votes: map[string]games.Card{User2: "S"}},
+		}
+		batch := batches.NewRaw("batch-id", "name", User1, "team-id", NewTestDeck(t), deadline, participants, tickets, createdAt)
+
+		require.NoError(t, repo.Save(ctx, batch))
+		got, err := repo.Get(ctx, batch.ID())
+		require.NoError(t, err)
+		require.NotNil(t, got)
+
+		assert.Equal(t, batch.ID(), got.ID())
+		assert.Equal(t, batch.Name(), got.Name())
+		assert.Equal(t, batch.FacilitatorID(), got.FacilitatorID())
+		assert.Equal(t, batch.TeamID(), got.TeamID())
+		assert.Equal(t, batch.CardsDeck(), got.CardsDeck())
+		assert.True(t, deadline.Equal(got.Deadline()))
+		assert.Equal(t, batch.ParticipantIDs(), got.ParticipantIDs())
+		require.Len(t, got.Tickets(), 2)
+		assert.Equal(t, "https://example.com/1", got.Tickets()[0].URL)
+		assert.Equal(t, tickets[0].Votes, got.Tickets()[0].Votes)
+		assert.True(t, createdAt.Equal(got.Tickets()[0].RevealedAt))
+		assert.False(t, got.Tickets()[1].IsRevealed())
+		assert.True(t, batch.CreatedAt().Equal(got.CreatedAt()))
+		assert.Empty(t, got.GetEvents())
+	})
+
+	t.Run("save publishes aggregate events", func(t *testing.T) {
+		bus := &EventsRecorder{}
+		repo := newRepo(bus)
+		batch := NewBatch(t, User1, "ticket")
+
+		require.NoError(t, repo.Save(ctx, batch))
+
+		list := bus.Events()
+		require.Len(t, list, 1)
+		assert.Equal(t, events.EventTypeBatchUpdated, list[0].EventType())
+		assert.Equal(t, batch.ID(), list[0].AggregateID())
+	})
+
+	t.Run("modify exclusively fails on unknown batch", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		err := repo.ModifyExclusively(ctx, "unknown", func(*batches.Batch) error { return nil })
+		assert.ErrorIs(t, err, batches.ErrBatchNotFound)
+	})
+
+	t.Run("concurrent modifications are not lost", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		batch := NewBatch(t, User1, "ticket")
+		require.NoError(t, repo.Save(ctx, batch))
+
+		var wg sync.WaitGroup
+		errs := make(chan error, concurrentWriters)
+		for i := 0; i < concurrentWriters; i++ {
+			wg.Add(1)
+			go func(uid string) {
+				defer wg.Done()
+				errs <- repo.ModifyExclusively(ctx, batch.ID(), func(batch *batches.Batch) error {
+					batch.Join(batches.JoinBatchCommand{BatchID: batch.ID(), UserID: uid})
+					return nil
+				})
+			}(fmt.Sprintf("user-%d", i))
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		got, err := repo.Get(ctx, batch.ID())
+		require.NoError(t, err)
+		assert.Len(t, got.Participants(), concurrentWriters+1)
+	})
+
+	t.Run("get by participant", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		batch := NewBatch(t, User1, "ticket")
+		batch.Join(batches.JoinBatchCommand{BatchID: batch.ID(), UserID: User2})
+		require.NoError(t, repo.Save(ctx, batch))
+		require.NoError(t, repo.Save(ctx, NewBatch(t, User3, "ticket")))
+
+		list, err := repo.GetBatchesByParticipantID(ctx, User2)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, batch.ID(), list[0].ID())
+
+		list, err = repo.GetBatchesByParticipantID(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Empty(t, list)
+	})
+
+	t.Run("get overdue batches", func(t *testing.T) {
+		repo := newRepo(&EventsRecorder{})
+		batch := NewBatch(t, User1, "first", "second")
+		require.NoError(t, repo.Save(ctx, batch))
+
+		ids, err := repo.GetOverdueBatchIDs(ctx, time.Now())
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+
+		afterDeadline := batch.Deadline().Add(time.Second)
+		ids, err = repo.GetOverdueBatchIDs(ctx, afterDeadline)
+		require.NoError(t, err)
+		assert.Equal(t, []string{batch.ID()}, ids)
+
+		// batches with all the tickets revealed are not overdue anymore
+		batch.RevealOverdue(afterDeadline)
+		require.NoError(t, repo.Save(ctx, batch))
+		ids, err = repo.GetOverdueBatchIDs(ctx, afterDeadline)
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+	})
+}
@@ -0,0 +1,16 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/rooms"
+)
+
+// NewRoom creates a testing room with the slug owned by specific user.
+func NewRoom(t *testing.T, slug, ownerID string) *rooms.Room {
+	cmd, err := rooms.NewCreateRoomCommand(slug, "room", ownerID, rooms.Settings{CardsDeck: NewTestDeck(t)})
+	require.NoError(t, err)
+	return rooms.NewRoom(*cmd)
+}
@@ -0,0 +1,65 @@
+package test_test
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/audit"
+	"planningpoker/internal/domain/batches"
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/reports"
+	"planningpoker/internal/domain/rooms"
+	"planningpoker/internal/domain/teams"
+	"planningpoker/internal/domain/users"
+	"planningpoker/internal/infra/async"
+	"planningpoker/internal/infra/auth"
+	"planningpoker/internal/infra/eventbus"
+	"planningpoker/internal/infra/http"
+	"planningpoker/internal/infra/repository"
+	"planningpoker/test"
+)
+
+// TestRoutes registers all routes on a single engine as the server does, gin panics on conflicting routes.
+func TestRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	eventBus := eventbus.NewInternalBus()
+	gamesRepo := repository.NewMemoryGameRepository(eventBus, test.NewLogger())
+	usersRepo := repository.NewMemoryUserRepository(eventBus, test.NewLogger())
+	teamsRepo := repository.NewMemoryTeamRepository(eventBus, test.NewLogger())
+	roomsRepo := repository.NewMemoryRoomRepository(eventBus, test.NewLogger())
+	roundsRepo := repository.NewMemoryRoundRepository()
+	batchesRepo := repository.NewMemoryBatchRepository(eventBus, test.NewLogger())
+
+	gamesService, err := games.NewService(gamesRepo, eventBus, games.Limits{}, test.NewLogger())
+	require.NoError(t, err)
+	usersService, err := users.NewService(usersRepo)
+	require.NoError(t, err)
+	auditService, err := audit.NewService(gamesRepo, gamesRepo, usersRepo, eventBus, test.NewLogger())
+	require.NoError(t, err)
+	teamsService, err := teams.NewService(teamsRepo, gamesRepo, gamesService)
+	require.NoError(t, err)
+	roomsService, err := rooms.NewService(roomsRepo, gamesRepo, gamesService)
+	require.NoError(t, err)
+	reportsService, err := reports.NewService(roundsRepo, teamsRepo, roomsRepo, gamesRepo, eventBus, test.NewLogger())
+	require.NoError(t, err)
+	batchesService, err := batches.NewService(batchesRepo, teamsRepo, test.NewLogger())
+	require.NoError(t, err)
+	authenticator := auth.NewUserAuthenticator(usersService, "secret")
+
+	api, err := http.NewAPI(
+		usersService, auditService, teamsService, roomsService, reportsService, batchesService, authenticator, test.NewLogger(),
+	)
+	require.NoError(t, err)
+	asyncAPI := async.NewAPI(gamesService, authenticator, test.NewLogger())
+
+	r := gin.New()
+	assert.NotPanics(t, func() {
+		api.SetupRoutes(r)
+		asyncAPI.SetupRoutes(r)
+		http.NewFrontend(t.TempDir()).SetupRoutes(r)
+	})
+}
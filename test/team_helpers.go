@@ -0,0 +1,23 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"planningpoker/internal/domain/teams"
+)
+
+// NewTeam creates a testing team owned by specific user.
+func NewTeam(t *testing.T, ownerID string) *teams.Team {
+	cmd, err := teams.NewCreateTeamCommand("team", ownerID, teams.Settings{CardsDeck: NewTestDeck(t)})
+	require.NoError(t, err)
+	return teams.NewTeam(*cmd)
+}
+
+// JoinTeam adds the user to the team as a member.
+func JoinTeam(t *testing.T, team *teams.Team, userID string) {
+	cmd, err := teams.NewJoinTeamCommand(team.JoinCode(), userID)
+	require.NoError(t, err)
+	require.NoError(t, team.Join(*cmd))
+}
@@ -0,0 +1,80 @@
+package test_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"planningpoker/internal/domain/games"
+	"planningpoker/internal/domain/state"
+	"planningpoker/internal/domain/users"
+	"planningpoker/internal/infra/async"
+	"planningpoker/internal/infra/eventbus"
+	"planningpoker/internal/infra/repository"
+	"planningpoker/internal/infra/tracing"
+	"planningpoker/test"
+)
+
+func TestWorkflow_Tracing(t *testing.T) {
+	exporter := retainingExporter{tracetest.NewInMemoryExporter()}
+	shutdown := tracing.Setup(exporter, 1)
+
+	eventBus := eventbus.NewInternalBus()
+	gamesRepo := repository.NewMemoryGameRepository(eventBus, test.NewLogger())
+	usersRepo := repository.NewMemoryUserRepository(eventBus, test.NewLogger())
+
+	gamesService, err := games.NewService(gamesRepo, eventBus, games.Limits{}, test.NewLogger())
+	require.NoError(t, err)
+	commands := tracing.NewGamesService(gamesService)
+	// the real socket API publishes the states, so its spans are recorded by the production code
+	asyncAPI := async.NewAPI(commands, nil, test.NewLogger())
+	_, err = state.NewService(gamesRepo, usersRepo, asyncAPI, eventBus, test.NewLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, usersRepo.Save(ctx, *users.NewRaw(test.User1, "John", time.Now())))
+	game := test.NewTestGame(t, test.NewSimpleGame(t, true)).UserJoins(test.User1).Instance()
+	require.NoError(t, gamesRepo.Save(ctx, game))
+	require.NoError(t, eventBus.Drain(ctx))
+
+	// the vote is traced through the event bus down to the states sent to the game and the player
+	ctx, root := tracing.Start(ctx, "test.vote")
+	cmd, err := games.NewVoteCommand(game.ID(), test.User1, "XS", games.ConfidenceNormal)
+	require.NoError(t, err)
+	require.NoError(t, commands.Vote(ctx, *cmd))
+	root.End()
+
+	require.NoError(t, eventBus.Drain(ctx))
+	require.NoError(t, shutdown(ctx))
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		if span.SpanContext.TraceID() == root.SpanContext().TraceID() {
+			spans[span.Name] = span
+		}
+	}
+
+	for _, name := range []string{
+		"games.Vote", "repository.ModifyExclusively", "repository.Save", "eventbus.Publish", "eventbus.Consume",
+		"state.processGameUpdated", "async.SendToGame", "async.SendToPlayer",
+	} {
+		assert.Contains(t, spans, name, "span %s should be a part of the trace", name)
+	}
+
+	processed := spans["state.processGameUpdated"].SpanContext.SpanID()
+	assert.Equal(t, processed, spans["async.SendToGame"].Parent.SpanID())
+	assert.Equal(t, processed, spans["async.SendToPlayer"].Parent.SpanID())
+}
+
+// retainingExporter keeps recorded spans on shutdown, so they can be inspected after flushing.
+type retainingExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func (retainingExporter) Shutdown(context.Context) error {
+	return nil
+}
@@ -8,12 +8,16 @@ export default {
         {name: "Powers of 2", types: ["0", "1", "2", "4", "8", "16", "32", "64", "?"]},
     ],
 
-    create(name, url, deck) {
+    reactions: ["👍", "👎", "🎉", "🤔", "😂", "😮", "☕", "🔥"],
+
+    create(name, url, deck, anonymous, chatDisabled) {
         const ob = {
             name: name,
             url: url,
             cards_deck: deck,
             everyone_can_reveal: true,
+            anonymous: !!anonymous,
+            chat_disabled: !!chatDisabled,
         }
 
         return new Promise((resolve) => {
@@ -52,4 +56,40 @@ export default {
     async restart() {
         notifier.socket.emit("restart")
     },
+
+    async revote() {
+        notifier.socket.emit("revote")
+    },
+
+    // changePasscode replaces the passcode new players should provide, an empty passcode removes the protection
+    changePasscode(passcode) {
+        return new Promise((resolve) => {
+            notifier.socket.emit("passcode", {
+                passcode: passcode,
+            }, res => resolve(res === 'ok'))
+        });
+    },
+
+    // rearrange seats players, order contains current players positions and the index of each position is the new seat
+    rearrange(order) {
+        return new Promise((resolve) => {
+            notifier.socket.emit("seats", {
+                order: order,
+            }, res => resolve(res === 'ok'))
+        });
+    },
+
+    async chat(text) {
+        notifier.socket.emit("chat", {
+            text: text,
+        })
+    },
+
+    // react throws the emoji at the player with the handle, an empty target throws it at the table
+    async react(emoji, target) {
+        notifier.socket.emit("react", {
+            emoji: emoji,
+            target: target || "",
+        })
+    },
 }
\ No newline at end of file
@@ -0,0 +1,8 @@
+const axios = require('axios');
+
+export default {
+    async open(slug) {
+        const rsp = await axios.post(`rooms/${encodeURIComponent(slug)}/open`)
+        return rsp.data.game_id
+    },
+}
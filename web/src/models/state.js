@@ -14,10 +14,21 @@ export default class {
     voted_card;
     can_reveal;
     confidence;
+    attempt;
+    attempts;
+    revote_suggested;
+    handle;
+    chat_disabled;
+    messages = [];
 
     constructor(id) {
         this.id = id
-        notifier.listenGame(id, state => this.updateState(state))
+        notifier.listenChat(history => this.messages = history, message => this.addMessage(message))
+        this.join()
+    }
+
+    join(passcode) {
+        notifier.listenGame(this.id, state => this.updateState(state), passcode)
     }
 
     getCards() {
@@ -36,7 +47,11 @@ export default class {
     }
 
     getPlayers() {
-        return this.players
+        return (this.players || []).map(p => ({...p, me: p.id === this.handle}))
+    }
+
+    isFacilitator() {
+        return this.getPlayers().some(p => p.me && p.facilitator)
     }
 
     canReveal() {
@@ -47,6 +62,41 @@ export default class {
         return this.can_reveal && this.state === stateFinished
     }
 
+    canRevote() {
+        return this.state === stateFinished
+    }
+
+    getAttempts() {
+        return this.attempts || []
+    }
+
+    getRevealedCards() {
+        return this.revealed_cards || []
+    }
+
+    getMessages() {
+        return this.messages
+    }
+
+    // playerName returns the name of the player with the handle, players who left are unknown
+    playerName(handle) {
+        const player = (this.players || []).find(p => p.id === handle)
+        return player ? player.name : "Somebody"
+    }
+
+    addMessage(message) {
+        // the server keeps the last 50 messages, so does the client
+        this.messages = this.messages.concat([message]).slice(-50)
+    }
+
+    async chat(text) {
+        await game.chat(text)
+    }
+
+    async react(emoji, target) {
+        await game.react(emoji, target)
+    }
+
     isActive(card) {
         return this.voted_card === card
     }
@@ -63,6 +113,18 @@ export default class {
         await game.restart(this.id)
     }
 
+    async revote() {
+        await game.revote(this.id)
+    }
+
+    async changePasscode(passcode) {
+        return await game.changePasscode(passcode)
+    }
+
+    async rearrange(order) {
+        return await game.rearrange(order)
+    }
+
     async vote(card) {
         if (this.voted_card === card) {
             this.voted_card = ""
@@ -90,16 +152,5 @@ export default class {
         for (const attribute in state) {
             this[attribute] = state[attribute];
         }
-        this.players && this.players.sort(comparePlayers)
-    }
-}
-
-function comparePlayers(a, b) {
-    if (a.name < b.name) {
-        return -1;
-    }
-    if (a.name > b.name) {
-        return 1;
     }
-    return 0;
 }
@@ -22,7 +22,7 @@ export default {
         } catch (e) {
             if (e.response.status === 401) {
                 const rsp = await axios.post("register", {name: this.name})
-                this.id = rsp.data.user_id
+                this.id = rsp.data.token
             } else {
                 throw e
             }
@@ -1,15 +1,21 @@
 import io from 'socket.io-client';
+import applyPatch from '@/notifier/patch';
 
 export default {
     STATUS_CONNECTED: "connected",
     STATUS_RECONNECTING: "reconnecting",
     STATUS_JOIN_FAILED: "join-failed",
+    STATUS_PASSCODE_REQUIRED: "passcode-required",
     STATUS_JOINED: "joined",
 
     socket: null,
     listensGame: null,
     status: null,
     listenStatus: null,
+    joinError: null,
+    gameState: null,
+    seq: 0,
+    playerSeq: 0,
 
     connect(token) {
         // we should create only one socket per session
@@ -31,30 +37,98 @@ export default {
         this.socket.on('reconnect', () => {
             this.status = this.STATUS_CONNECTED
             if (this.listensGame != null) {
-                this.listenGame(this.listensGame.gameID, this.listensGame.callback)
+                this.listenGame(this.listensGame.gameID, this.listensGame.callback, this.listensGame.passcode)
             }
         })
 
         this.socket.on('reconnecting', () => {
             this.status = this.STATUS_RECONNECTING
         })
+
+        // the server goes down gracefully, the client reconnects to a new instance automatically
+        this.socket.on('shutdown', () => {
+            this.status = this.STATUS_RECONNECTING
+        })
     },
 
-    listenGame(gameID, callback) {
-        this.socket.emit("join", gameID, res => {
-            if (res !== 'ok') {
-                this.listenStatus = this.STATUS_JOIN_FAILED
-            } else {
+    // listenGame joins the game, the passcode is needed only the first time the player joins a protected game
+    listenGame(gameID, callback, passcode) {
+        // the server starts a new stream with a full snapshot on every join
+        this.gameState = null
+        this.seq = 0
+        this.playerSeq = 0
+        this.listenStatus = null
+        this.joinError = null
+        this.socket.emit("join", {game_id: gameID, passcode: passcode || ""}, res => {
+            if (res === 'ok') {
                 this.listenStatus = this.STATUS_JOINED
+            } else if (res.code === 'passcode_required' || res.code === 'wrong_passcode') {
+                this.joinError = res.code
+                this.listenStatus = this.STATUS_PASSCODE_REQUIRED
+            } else {
+                this.listenStatus = this.STATUS_JOIN_FAILED
             }
         })
-        this.socket.on("gameState", state => callback(state))
-        this.listensGame = {gameID: gameID, callback: callback}
+        this.socket.off("gameState")
+        this.socket.off("gameStatePatch")
+        this.socket.off("playerState")
+        this.socket.on("gameState", snapshot => this.applySnapshot(snapshot, callback))
+        this.socket.on("gameStatePatch", patch => this.applyPatch(patch, callback))
+        // the player's own vote and permissions come separately from the state shared by everyone in the game
+        this.socket.on("playerState", snapshot => this.applyPlayerSnapshot(snapshot, callback))
+        this.listensGame = {gameID: gameID, callback: callback, passcode: passcode}
+    },
+
+    // listenChat receives the last chat messages on every join and new messages afterwards
+    listenChat(onHistory, onMessage) {
+        this.socket.off("chatHistory")
+        this.socket.off("chatMessage")
+        this.socket.on("chatHistory", onHistory)
+        this.socket.on("chatMessage", onMessage)
+    },
+
+    applySnapshot(snapshot, callback) {
+        if (snapshot.seq < this.seq) {
+            return
+        }
+        this.seq = snapshot.seq
+        this.gameState = snapshot.state
+        callback(JSON.parse(JSON.stringify(this.gameState)))
+    },
+
+    applyPlayerSnapshot(snapshot, callback) {
+        if (snapshot.seq <= this.playerSeq) {
+            return
+        }
+        this.playerSeq = snapshot.seq
+        callback(snapshot.state)
+    },
+
+    applyPatch(patch, callback) {
+        if (patch.seq <= this.seq) {
+            return
+        }
+        // a message is lost or arrived before the snapshot, ask for the full state
+        if (this.gameState == null || patch.seq !== this.seq + 1) {
+            this.socket.emit("resync", snapshot => {
+                if (snapshot && snapshot.state) {
+                    this.applySnapshot(snapshot, callback)
+                }
+            })
+            return
+        }
+        this.seq = patch.seq
+        this.gameState = applyPatch(this.gameState, patch.patch)
+        callback(JSON.parse(JSON.stringify(this.gameState)))
     },
 
     leaveGame() {
         this.socket.emit("leave")
         this.listensGame = null
         this.listenStatus = null
+        this.joinError = null
+        this.gameState = null
+        this.seq = 0
+        this.playerSeq = 0
     }
 }
\ No newline at end of file
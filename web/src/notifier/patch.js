@@ -0,0 +1,36 @@
+// applies RFC 6902 add/remove/replace operations produced by the backend
+export default function applyPatch(doc, ops) {
+    for (const op of ops) {
+        if (op.path === "") {
+            doc = op.value
+            continue
+        }
+
+        const keys = op.path.substring(1).split("/").map(k => k.replace(/~1/g, "/").replace(/~0/g, "~"))
+        const last = keys.pop()
+        let target = doc
+        for (const k of keys) {
+            target = target[Array.isArray(target) ? parseInt(k) : k]
+        }
+
+        if (Array.isArray(target)) {
+            const idx = last === "-" ? target.length : parseInt(last)
+            if (op.op === "add") {
+                target.splice(idx, 0, op.value)
+            } else if (op.op === "remove") {
+                target.splice(idx, 1)
+            } else {
+                target[idx] = op.value
+            }
+            continue
+        }
+
+        if (op.op === "remove") {
+            delete target[last]
+        } else {
+            target[last] = op.value
+        }
+    }
+
+    return doc
+}
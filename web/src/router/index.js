@@ -2,6 +2,7 @@ import Vue from 'vue'
 import VueRouter from 'vue-router'
 import Home from '../views/Home.vue'
 import Game from '../views/Game.vue'
+import Room from '../views/Room.vue'
 
 Vue.use(VueRouter)
 
@@ -11,6 +12,11 @@ const routes = [
         name: 'Home',
         component: Home
     },
+    {
+        path: '/r/:slug',
+        name: 'Rooms',
+        component: Room,
+    },
     {
         path: '/:id',
         name: 'Games',